@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"drip/internal/client/cli"
+)
+
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+func main() {
+	// Unlike cmd/drip there is no client runtime tuning, telemetry, or
+	// crash capture here: the server sizes itself from its own flags and
+	// the standard GOMAXPROCS/GOGC/GOMEMLIMIT environment variables.
+	cli.SetVersion(Version, GitCommit, BuildTime)
+
+	if err := cli.ExecuteServer(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
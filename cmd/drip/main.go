@@ -7,6 +7,9 @@ import (
 	"runtime/debug"
 
 	"drip/internal/client/cli"
+	"drip/internal/client/telemetry"
+	"drip/internal/shared/utils"
+	"drip/pkg/config"
 )
 
 var (
@@ -16,8 +19,11 @@ var (
 )
 
 func main() {
-	// Performance optimizations
-	setupPerformanceOptimizations()
+	// Writes an opt-in crash report before the panic surfaces; no-op
+	// unless telemetry is enabled in the client config.
+	defer telemetry.CapturePanic(Version)
+
+	tuneRuntime()
 
 	cli.SetVersion(Version, GitCommit, BuildTime)
 
@@ -27,18 +33,41 @@ func main() {
 	}
 }
 
-// setupPerformanceOptimizations configures runtime settings for optimal performance
-func setupPerformanceOptimizations() {
-	// Set GOMAXPROCS to use all available CPU cores
-	numCPU := runtime.NumCPU()
-	runtime.GOMAXPROCS(numCPU)
+// tuneRuntime applies Go runtime tuning: explicit overrides from the client
+// config's runtime: section when present, otherwise cgroup-aware GOMAXPROCS
+// so containers limited to one or two CPUs don't schedule across the host's
+// core count. The GOMAXPROCS/GOGC/GOMEMLIMIT environment variables always
+// take precedence and are left untouched.
+func tuneRuntime() {
+	var rc *config.RuntimeConfig
+	if cfg, err := config.LoadClientConfig(""); err == nil {
+		rc = cfg.Runtime
+	}
 
-	// Reduce GC frequency for high-throughput scenarios
-	// Default is 100, setting to 200 reduces GC overhead at cost of more memory
-	// This is beneficial since we now use buffer pools (less garbage)
-	debug.SetGCPercent(200)
+	if os.Getenv("GOMAXPROCS") == "" {
+		switch {
+		case rc != nil && rc.GOMAXPROCS > 0:
+			runtime.GOMAXPROCS(rc.GOMAXPROCS)
+		default:
+			if quota, ok := utils.CgroupCPUQuota(); ok {
+				procs := int(quota)
+				if procs < 1 {
+					procs = 1
+				}
+				if procs < runtime.NumCPU() {
+					runtime.GOMAXPROCS(procs)
+				}
+			}
+		}
+	}
 
-	// Set memory limit to prevent OOM (adjust based on your server)
-	// This is a soft limit - Go will try to stay under this
-	debug.SetMemoryLimit(8 * 1024 * 1024 * 1024) // 8GB limit
+	if rc == nil {
+		return
+	}
+	if rc.GCPercent > 0 && os.Getenv("GOGC") == "" {
+		debug.SetGCPercent(rc.GCPercent)
+	}
+	if rc.MemoryLimitMB > 0 && os.Getenv("GOMEMLIMIT") == "" {
+		debug.SetMemoryLimit(int64(rc.MemoryLimitMB) << 20)
+	}
 }
@@ -0,0 +1,330 @@
+package capture
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Request is one captured HTTP exchange through the tunnel.
+type Request struct {
+	ID           uint64        `json:"id"`
+	Time         time.Time     `json:"time"`
+	Method       string        `json:"method"`
+	Path         string        `json:"path"`
+	Host         string        `json:"host"`
+	Status       int           `json:"status"`
+	Duration     time.Duration `json:"duration"`
+	ContentType  string        `json:"content_type,omitempty"`
+	RequestSize  int64         `json:"request_size"`
+	ResponseSize int64         `json:"response_size"`
+	ReqHeader    http.Header   `json:"req_header,omitempty"`
+	RespHeader   http.Header   `json:"resp_header,omitempty"`
+
+	// ReqBody and RespBody hold the first BodyLimit bytes of each body,
+	// recorded when the web inspector is enabled. Larger bodies are
+	// truncated, not dropped.
+	ReqBody  []byte `json:"req_body,omitempty"`
+	RespBody []byte `json:"resp_body,omitempty"`
+
+	// Replayed marks requests re-sent to the local service from the web
+	// inspector rather than arriving through the tunnel.
+	Replayed bool `json:"replayed,omitempty"`
+
+	// Verification is set when webhook signature checking is enabled:
+	// "verified" or "invalid". Empty when verification is disabled.
+	Verification string `json:"verification,omitempty"`
+}
+
+// approxSize estimates the memory this entry retains, used to enforce the
+// buffer's byte budget.
+func (r *Request) approxSize() int64 {
+	size := int64(128) // struct overhead
+	size += int64(len(r.Method) + len(r.Path) + len(r.Host) + len(r.ContentType))
+	size += int64(len(r.ReqBody) + len(r.RespBody))
+	size += headerSize(r.ReqHeader)
+	size += headerSize(r.RespHeader)
+	return size
+}
+
+// BodyLimit caps how much of each request/response body is retained per
+// captured exchange.
+const BodyLimit = 64 << 10
+
+// BodyRecorder accumulates up to BodyLimit bytes written through it and
+// discards the rest, so capturing a body never buffers it in full.
+type BodyRecorder struct {
+	buf       []byte
+	truncated bool
+}
+
+// Write implements io.Writer; it never fails, so it is safe as a tee
+// destination on the forwarding path.
+func (b *BodyRecorder) Write(p []byte) (int, error) {
+	if room := BodyLimit - len(b.buf); room > 0 {
+		if len(p) > room {
+			b.buf = append(b.buf, p[:room]...)
+			b.truncated = true
+		} else {
+			b.buf = append(b.buf, p...)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// Bytes returns the recorded prefix of the body.
+func (b *BodyRecorder) Bytes() []byte { return b.buf }
+
+// Truncated reports whether the body exceeded BodyLimit.
+func (b *BodyRecorder) Truncated() bool { return b.truncated }
+
+func headerSize(h http.Header) int64 {
+	var size int64
+	for key, values := range h {
+		size += int64(len(key))
+		for _, v := range values {
+			size += int64(len(v))
+		}
+	}
+	return size
+}
+
+// Filter selects captured requests; zero-valued fields match everything.
+type Filter struct {
+	Method      string        // exact match, case-insensitive
+	Path        string        // substring match
+	StatusMin   int           // inclusive lower bound, 0 = no bound
+	StatusMax   int           // inclusive upper bound, 0 = no bound
+	MinDuration time.Duration // only requests at least this slow
+	Header      string        // "Key: value-substring", or "Key" for presence
+	Since       time.Time     // only requests captured after this time
+	Limit       int           // max results, 0 = unlimited
+}
+
+// Match reports whether a captured request passes the filter.
+func (f Filter) Match(r *Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	if f.Path != "" && !strings.Contains(r.Path, f.Path) {
+		return false
+	}
+	if f.StatusMin > 0 && r.Status < f.StatusMin {
+		return false
+	}
+	if f.StatusMax > 0 && r.Status > f.StatusMax {
+		return false
+	}
+	if f.MinDuration > 0 && r.Duration < f.MinDuration {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if f.Header != "" && !matchHeader(f.Header, r.ReqHeader, r.RespHeader) {
+		return false
+	}
+	return true
+}
+
+func matchHeader(expr string, headers ...http.Header) bool {
+	key, want := expr, ""
+	if idx := strings.Index(expr, ":"); idx >= 0 {
+		key = strings.TrimSpace(expr[:idx])
+		want = strings.TrimSpace(expr[idx+1:])
+	}
+
+	for _, h := range headers {
+		values := h.Values(key)
+		if len(values) == 0 {
+			continue
+		}
+		if want == "" {
+			return true
+		}
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), strings.ToLower(want)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Buffer is a fixed-budget ring buffer of captured requests. Entry IDs are
+// sequential, so the live window is always the contiguous range
+// [nextID-count, nextID); small per-method and per-status-class indexes keep
+// the common filters cheap on busy tunnels.
+type Buffer struct {
+	mu      sync.Mutex
+	entries []*Request
+	head    int // position of the oldest entry
+	count   int
+	nextID  uint64
+
+	maxBytes  int64
+	usedBytes int64
+
+	byMethod map[string][]uint64
+	byClass  map[int][]uint64 // status/100 -> ids
+}
+
+// DefaultMaxEntries bounds the ring when callers pass zero.
+const DefaultMaxEntries = 1024
+
+// DefaultMaxBytes bounds retained memory when callers pass zero.
+const DefaultMaxBytes = 16 << 20
+
+// NewBuffer creates a capture buffer holding at most maxEntries requests and
+// approximately maxBytes of retained data.
+func NewBuffer(maxEntries int, maxBytes int64) *Buffer {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Buffer{
+		entries:  make([]*Request, maxEntries),
+		maxBytes: maxBytes,
+		byMethod: make(map[string][]uint64),
+		byClass:  make(map[int][]uint64),
+	}
+}
+
+// Add records a request, evicting the oldest entries as needed to stay
+// within the entry and byte budgets.
+func (b *Buffer) Add(r *Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r.ID = b.nextID
+	b.nextID++
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+
+	size := r.approxSize()
+	for b.count > 0 && (b.count == len(b.entries) || b.usedBytes+size > b.maxBytes) {
+		b.evictOldestLocked()
+	}
+
+	pos := (b.head + b.count) % len(b.entries)
+	b.entries[pos] = r
+	b.count++
+	b.usedBytes += size
+
+	method := strings.ToUpper(r.Method)
+	b.byMethod[method] = append(b.byMethod[method], r.ID)
+	b.byClass[r.Status/100] = append(b.byClass[r.Status/100], r.ID)
+}
+
+func (b *Buffer) evictOldestLocked() {
+	old := b.entries[b.head]
+	b.entries[b.head] = nil
+	b.head = (b.head + 1) % len(b.entries)
+	b.count--
+	b.usedBytes -= old.approxSize()
+}
+
+// minIDLocked returns the smallest ID still in the buffer.
+func (b *Buffer) minIDLocked() uint64 {
+	return b.nextID - uint64(b.count)
+}
+
+// getLocked returns the entry with the given ID, or nil if evicted.
+func (b *Buffer) getLocked(id uint64) *Request {
+	if b.count == 0 || id >= b.nextID || id < b.minIDLocked() {
+		return nil
+	}
+	pos := (b.head + int(id-b.minIDLocked())) % len(b.entries)
+	return b.entries[pos]
+}
+
+// Get returns the captured request with the given ID, if still buffered.
+func (b *Buffer) Get(id uint64) (*Request, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r := b.getLocked(id)
+	return r, r != nil
+}
+
+// Len returns the number of buffered requests.
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.count
+}
+
+// List returns buffered requests matching the filter, newest first. When the
+// filter names a method or a status range within one class, the matching
+// index narrows the scan.
+func (b *Buffer) List(f Filter) []*Request {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ids := b.indexedIDsLocked(f); ids != nil {
+		return b.collectLocked(ids, f)
+	}
+
+	var results []*Request
+	for i := b.count - 1; i >= 0; i-- {
+		pos := (b.head + i) % len(b.entries)
+		r := b.entries[pos]
+		if !f.Match(r) {
+			continue
+		}
+		results = append(results, r)
+		if f.Limit > 0 && len(results) >= f.Limit {
+			break
+		}
+	}
+	return results
+}
+
+// indexedIDsLocked returns the candidate ID list for an indexable filter, or
+// nil when a full scan is required. Stale IDs are pruned as a side effect.
+func (b *Buffer) indexedIDsLocked(f Filter) []uint64 {
+	if f.Method != "" {
+		method := strings.ToUpper(f.Method)
+		b.byMethod[method] = pruneIDs(b.byMethod[method], b.minIDLocked())
+		return b.byMethod[method]
+	}
+	if f.StatusMin > 0 && f.StatusMax > 0 && f.StatusMin/100 == f.StatusMax/100 {
+		class := f.StatusMin / 100
+		b.byClass[class] = pruneIDs(b.byClass[class], b.minIDLocked())
+		return b.byClass[class]
+	}
+	return nil
+}
+
+func (b *Buffer) collectLocked(ids []uint64, f Filter) []*Request {
+	var results []*Request
+	for i := len(ids) - 1; i >= 0; i-- {
+		r := b.getLocked(ids[i])
+		if r == nil || !f.Match(r) {
+			continue
+		}
+		results = append(results, r)
+		if f.Limit > 0 && len(results) >= f.Limit {
+			break
+		}
+	}
+	return results
+}
+
+// pruneIDs drops IDs below minID from the front of a sorted index list.
+func pruneIDs(ids []uint64, minID uint64) []uint64 {
+	idx := 0
+	for idx < len(ids) && ids[idx] < minID {
+		idx++
+	}
+	if idx == 0 {
+		return ids
+	}
+	return append(ids[:0], ids[idx:]...)
+}
@@ -0,0 +1,42 @@
+package capture
+
+import (
+	"testing"
+
+	json "github.com/goccy/go-json"
+)
+
+// FuzzUnmarshalRequest exercises the JSON decoding of captured HTTP
+// exchanges. Snapshot files under ~/.drip/capture carry headers and body
+// prefixes copied verbatim from public traffic, so the decoder (shared with
+// drip inspect and the web inspector) must tolerate arbitrary bytes inside
+// them. Run with go test -fuzz=FuzzUnmarshalRequest ./internal/client/capture.
+func FuzzUnmarshalRequest(f *testing.F) {
+	f.Add([]byte(`[{"id":1,"method":"GET","path":"/api","status":200,"req_header":{"X-A":["b"]}}]`))
+	f.Add([]byte(`[{"id":2,"req_body":"3q2+7w==","duration":125000000}]`))
+	f.Add([]byte(`[{"time":"not-a-time"}]`))
+	f.Add([]byte(`[null]`))
+	f.Add([]byte(`{`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var entries []*Request
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return
+		}
+
+		// Whatever decoded must be safe to size, filter, and re-encode — the
+		// operations drip inspect performs on a loaded snapshot.
+		for _, r := range entries {
+			if r == nil {
+				continue
+			}
+			if r.approxSize() < 0 {
+				t.Fatalf("negative approx size for %+v", r)
+			}
+			Filter{Method: "GET", Path: "/", Header: "X-A: b"}.Match(r)
+		}
+		if _, err := json.Marshal(entries); err != nil {
+			t.Fatalf("failed to re-encode decoded snapshot: %v", err)
+		}
+	})
+}
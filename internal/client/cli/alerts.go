@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"drip/pkg/config"
+)
+
+const defaultAlertCooldown = 5 * time.Minute
+
+// alerter evaluates connection quality thresholds from the client config and
+// raises desktop notifications or runs a user hook when they are crossed.
+type alerter struct {
+	cfg   *config.AlertConfig
+	label string
+
+	mu           sync.Mutex
+	drops        []time.Time
+	lastFired    map[string]time.Time
+	lastRequests int64
+	lastErrors   int64
+}
+
+// newAlerter returns nil when no alert config is present; callers check for
+// nil before observing.
+func newAlerter(cfg *config.AlertConfig, label string) *alerter {
+	if cfg == nil {
+		return nil
+	}
+	return &alerter{
+		cfg:       cfg,
+		label:     label,
+		lastFired: make(map[string]time.Time),
+	}
+}
+
+// ObserveLatency checks a latency sample against the configured threshold.
+func (a *alerter) ObserveLatency(latency time.Duration) {
+	if a.cfg.LatencyAbove <= 0 || latency <= a.cfg.LatencyAbove {
+		return
+	}
+	a.fire("latency", fmt.Sprintf("latency %s exceeds threshold %s",
+		latency.Round(time.Millisecond), a.cfg.LatencyAbove))
+}
+
+// ObserveDrop records a connection drop and checks the reconnects-per-hour
+// threshold against a sliding one-hour window.
+func (a *alerter) ObserveDrop() {
+	if a.cfg.ReconnectsPerHour <= 0 {
+		return
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-time.Hour)
+
+	a.mu.Lock()
+	a.drops = append(a.drops, now)
+	for len(a.drops) > 0 && a.drops[0].Before(cutoff) {
+		a.drops = a.drops[1:]
+	}
+	count := len(a.drops)
+	a.mu.Unlock()
+
+	if count > a.cfg.ReconnectsPerHour {
+		a.fire("reconnects", fmt.Sprintf("%d reconnects in the last hour (threshold %d)",
+			count, a.cfg.ReconnectsPerHour))
+	}
+}
+
+// ObserveErrorRate compares request and 5xx counter deltas since the last
+// call against the configured backend error rate. Small samples are skipped
+// so a single failing request doesn't trip the alert.
+func (a *alerter) ObserveErrorRate(requests, serverErrors int64) {
+	if a.cfg.ErrorRatePercent <= 0 {
+		return
+	}
+
+	a.mu.Lock()
+	deltaReq := requests - a.lastRequests
+	deltaErr := serverErrors - a.lastErrors
+	a.lastRequests = requests
+	a.lastErrors = serverErrors
+	a.mu.Unlock()
+
+	if deltaReq < 10 || deltaErr <= 0 {
+		return
+	}
+
+	rate := float64(deltaErr) / float64(deltaReq) * 100
+	if rate > a.cfg.ErrorRatePercent {
+		a.fire("error_rate", fmt.Sprintf("backend 5xx rate %.1f%% exceeds %.1f%%",
+			rate, a.cfg.ErrorRatePercent))
+	}
+}
+
+// fire raises an alert unless the same kind fired within the cooldown.
+func (a *alerter) fire(kind, message string) {
+	cooldown := a.cfg.Cooldown
+	if cooldown <= 0 {
+		cooldown = defaultAlertCooldown
+	}
+
+	a.mu.Lock()
+	if last, ok := a.lastFired[kind]; ok && time.Since(last) < cooldown {
+		a.mu.Unlock()
+		return
+	}
+	a.lastFired[kind] = time.Now()
+	a.mu.Unlock()
+
+	title := fmt.Sprintf("drip %s", a.label)
+
+	if a.cfg.Notify {
+		notifyDesktop(title, message)
+	}
+
+	if a.cfg.Hook != "" {
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		cmd := exec.Command(shell, flag, a.cfg.Hook)
+		cmd.Env = append(os.Environ(),
+			"DRIP_ALERT="+kind,
+			"DRIP_ALERT_MESSAGE="+message,
+			"DRIP_TUNNEL="+a.label,
+		)
+		go func() { _ = cmd.Run() }()
+	}
+}
+
+// notifyDesktop sends a best-effort desktop notification using whatever the
+// platform provides; failures are ignored.
+func notifyDesktop(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("notify-send", title, message)
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		return
+	}
+	go func() { _ = cmd.Run() }()
+}
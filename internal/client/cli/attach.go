@@ -3,8 +3,8 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
@@ -60,8 +60,8 @@ func runAttach(_ *cobra.Command, args []string) error {
 
 	if len(args) == 2 {
 		tunnelType := args[0]
-		if tunnelType != "http" && tunnelType != "https" && tunnelType != "tcp" {
-			return fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', or 'tcp')", tunnelType)
+		if tunnelType != "http" && tunnelType != "https" && tunnelType != "tcp" && tunnelType != "udp" {
+			return fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', 'tcp', or 'udp')", tunnelType)
 		}
 
 		port, err := strconv.Atoi(args[1])
@@ -184,24 +184,15 @@ func attachToDaemon(daemon *DaemonInfo) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	tailCmd := exec.Command("tail", "-f", logPath)
-	tailCmd.Stdout = os.Stdout
-	tailCmd.Stderr = os.Stderr
-
-	if err := tailCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start tail: %w", err)
-	}
-
+	stop := make(chan struct{})
 	done := make(chan error, 1)
 	go func() {
-		done <- tailCmd.Wait()
+		done <- followLogFile(logPath, stop)
 	}()
 
 	select {
 	case <-sigCh:
-		if tailCmd.Process != nil {
-			_ = tailCmd.Process.Kill()
-		}
+		close(stop)
 		fmt.Println()
 		fmt.Println(ui.Warning("Detached from tunnel (tunnel is still running)"))
 		fmt.Println(ui.Muted(fmt.Sprintf("Use '%s' to reattach", ui.Cyan(fmt.Sprintf("drip attach %s %d", daemon.Type, daemon.Port)))))
@@ -209,12 +200,49 @@ func attachToDaemon(daemon *DaemonInfo) error {
 		return nil
 	case err := <-done:
 		if err != nil {
-			return fmt.Errorf("tail process exited: %w", err)
+			return fmt.Errorf("log follow failed: %w", err)
 		}
 		return nil
 	}
 }
 
+// followLogFile streams path to stdout until stop closes, polling for new
+// data. It needs no external tail binary, so it works the same in Windows
+// consoles as in Unix shells.
+func followLogFile(path string, stop <-chan struct{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Start a little before the end so attach shows recent history, like
+	// tail -f would.
+	const tailWindow = 4096
+	if info, err := f.Stat(); err == nil && info.Size() > tailWindow {
+		_, _ = f.Seek(-tailWindow, io.SeekEnd)
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			_, _ = os.Stdout.Write(buf[:n])
+		}
+		if err == io.EOF {
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(250 * time.Millisecond):
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
 func truncatePath(path string, maxLen int) string {
 	if len(path) <= maxLen {
 		return path
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [http|https|tcp]",
+	Short: "Dry-run a registration against the server",
+	Long: `Register a tunnel and release it immediately, without exposing
+anything. Verifies that the server is reachable, the token is valid, the
+subdomain is available, and shows the URL a real tunnel would get.
+
+Useful as a pre-flight check in scripts: the exit code is non-zero when
+registration would fail.
+
+Example:
+  drip check                        Check a plain HTTP registration
+  drip check http --subdomain myapp Check a custom subdomain
+  drip check tcp                    Check a TCP registration`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain to check (optional)")
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(_ *cobra.Command, args []string) error {
+	tunnelType := protocol.TunnelTypeHTTP
+	if len(args) == 1 {
+		switch args[0] {
+		case "http":
+			tunnelType = protocol.TunnelTypeHTTP
+		case "https":
+			tunnelType = protocol.TunnelTypeHTTPS
+		case "tcp":
+			tunnelType = protocol.TunnelTypeTCP
+		default:
+			return fmt.Errorf("invalid tunnel type: %s (must be http, https, or tcp)", args[0])
+		}
+	}
+
+	if err := utils.InitLogger(verbose); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer utils.Sync()
+	logger := utils.GetLogger()
+
+	serverAddr, token, err := resolveServerAddrAndToken("check", 0)
+	if err != nil {
+		return err
+	}
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		TunnelType: tunnelType,
+		LocalHost:  "127.0.0.1",
+		LocalPort:  1,
+		Subdomain:  subdomain,
+		Insecure:   insecure,
+	}
+
+	connector := tcp.NewTunnelClient(connConfig, logger)
+	if err := connector.Connect(); err != nil {
+		fmt.Println(ui.Error(fmt.Sprintf("Registration would fail: %v", err)))
+		return fmt.Errorf("check failed")
+	}
+
+	url := connector.GetURL()
+	assigned := connector.GetSubdomain()
+	_ = connector.Close()
+
+	lines := []string{
+		ui.KeyValue("Server", serverAddr),
+		ui.KeyValue("Token", "accepted"),
+		ui.KeyValue("Subdomain", assigned),
+		ui.KeyValue("URL", ui.URL(url)),
+		"",
+		ui.Muted("The registration was released; nothing is exposed."),
+	}
+	fmt.Println(ui.SuccessBox("Registration Check Passed", lines...))
+	return nil
+}
@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"drip/internal/shared/stats"
+	json "github.com/goccy/go-json"
+)
+
+// Daemons expose runtime state over a per-daemon unix control socket next to
+// their info file, so stats/stop/list talk to the live process instead of
+// inferring state from files. The protocol is one JSON request line
+// ({"op": "status"|"stats"|"stop"|"reload"}) answered with one JSON
+// document. The info file remains the discovery registry and the fallback
+// for daemons predating the socket.
+
+const controlTimeout = 2 * time.Second
+
+// controlSocketPath returns the control socket path for a daemon.
+func controlSocketPath(tunnelType string, port int) string {
+	return filepath.Join(getDaemonDir(), fmt.Sprintf("%s_%d.sock", tunnelType, port))
+}
+
+// controlRequest is one request line sent to a daemon's control socket.
+type controlRequest struct {
+	Op string `json:"op"`
+}
+
+// controlError is returned when a control request cannot be served.
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// controlAck is returned for ops that carry no data (stop, reload).
+type controlAck struct {
+	OK bool `json:"ok"`
+}
+
+// daemonStats is the response to the "stats" op: live counters of a running
+// daemon, with traffic summed across reconnect sessions.
+type daemonStats struct {
+	Type            string `json:"type"`
+	Port            int    `json:"port"`
+	URL             string `json:"url"`
+	Subdomain       string `json:"subdomain,omitempty"`
+	PID             int    `json:"pid"`
+	UptimeSeconds   int64  `json:"uptime_seconds"`
+	TotalRequests   int64  `json:"total_requests"`
+	BytesIn         int64  `json:"bytes_in"`
+	BytesOut        int64  `json:"bytes_out"`
+	SpeedIn         int64  `json:"speed_in"`
+	SpeedOut        int64  `json:"speed_out"`
+	ActiveConns     int64  `json:"active_connections"`
+	Reconnects      int    `json:"reconnects,omitempty"`
+	DowntimeSeconds int64  `json:"downtime_seconds,omitempty"`
+}
+
+// controlServer serves the control socket for one daemon process.
+type controlServer struct {
+	mu       sync.Mutex
+	info     *DaemonInfo
+	listener net.Listener
+
+	// source reads the current session's counters; nil between sessions.
+	// base* accumulate totals from completed sessions so stats stay
+	// cumulative across reconnects.
+	source       func() *stats.TrafficStats
+	baseRequests int64
+	baseIn       int64
+	baseOut      int64
+
+	// stopFn and reloadFn are wired by the tunnel runner: stop shuts the
+	// daemon down gracefully, reload tears down and re-registers the tunnel.
+	stopFn   func()
+	reloadFn func()
+}
+
+// startControlServer starts serving the daemon's control socket. Best
+// effort: a daemon without a control socket still works, it just cannot
+// answer live queries.
+func startControlServer(info *DaemonInfo) *controlServer {
+	path := controlSocketPath(info.Type, info.Port)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil
+	}
+	// A previous daemon that died without cleanup leaves the socket behind;
+	// CheckExistingDaemon has already established we own this type/port.
+	_ = os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil
+	}
+
+	s := &controlServer{info: info, listener: listener}
+	go s.serve()
+	return s
+}
+
+// SetOps wires the stop and reload handlers invoked by control requests.
+func (s *controlServer) SetOps(stop, reload func()) {
+	s.mu.Lock()
+	s.stopFn = stop
+	s.reloadFn = reload
+	s.mu.Unlock()
+}
+
+// SetSource points the server at the current session's traffic counters.
+func (s *controlServer) SetSource(fn func() *stats.TrafficStats) {
+	s.mu.Lock()
+	s.source = fn
+	s.mu.Unlock()
+}
+
+// EndSession folds a finished session's totals into the cumulative base and
+// detaches the live source until the next session connects.
+func (s *controlServer) EndSession(snap stats.Snapshot) {
+	s.mu.Lock()
+	s.baseRequests += snap.TotalRequests
+	s.baseIn += snap.TotalBytesIn
+	s.baseOut += snap.TotalBytesOut
+	s.source = nil
+	s.mu.Unlock()
+}
+
+// Close stops the server and removes the socket file.
+func (s *controlServer) Close() {
+	_ = s.listener.Close()
+	_ = os.Remove(controlSocketPath(s.info.Type, s.info.Port))
+}
+
+func (s *controlServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *controlServer) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(controlTimeout))
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return
+	}
+
+	var req controlRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		s.reply(conn, controlError{Error: "invalid request"})
+		return
+	}
+
+	switch req.Op {
+	case "status":
+		s.mu.Lock()
+		info := *s.info
+		s.mu.Unlock()
+		s.reply(conn, &info)
+	case "stats":
+		s.reply(conn, s.statsLocked())
+	case "stop":
+		s.mu.Lock()
+		fn := s.stopFn
+		s.mu.Unlock()
+		if fn == nil {
+			s.reply(conn, controlError{Error: "stop not supported"})
+			return
+		}
+		s.reply(conn, controlAck{OK: true})
+		fn()
+	case "reload":
+		s.mu.Lock()
+		fn := s.reloadFn
+		s.mu.Unlock()
+		if fn == nil {
+			s.reply(conn, controlError{Error: "reload not supported"})
+			return
+		}
+		s.reply(conn, controlAck{OK: true})
+		fn()
+	default:
+		s.reply(conn, controlError{Error: fmt.Sprintf("unknown op: %s", req.Op)})
+	}
+}
+
+func (s *controlServer) reply(conn net.Conn, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_, _ = conn.Write(append(data, '\n'))
+}
+
+// statsLocked assembles the cumulative stats response.
+func (s *controlServer) statsLocked() *daemonStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := &daemonStats{
+		Type:            s.info.Type,
+		Port:            s.info.Port,
+		URL:             s.info.URL,
+		Subdomain:       s.info.Subdomain,
+		PID:             s.info.PID,
+		UptimeSeconds:   int64(time.Since(s.info.StartTime).Seconds()),
+		TotalRequests:   s.baseRequests,
+		BytesIn:         s.baseIn,
+		BytesOut:        s.baseOut,
+		Reconnects:      s.info.Reconnects,
+		DowntimeSeconds: int64(s.info.Downtime.Seconds()),
+	}
+
+	if s.source != nil {
+		if st := s.source(); st != nil {
+			snap := st.GetSnapshot()
+			out.TotalRequests += snap.TotalRequests
+			out.BytesIn += snap.TotalBytesIn
+			out.BytesOut += snap.TotalBytesOut
+			out.SpeedIn = snap.SpeedIn
+			out.SpeedOut = snap.SpeedOut
+			out.ActiveConns = snap.ActiveConnections
+		}
+	}
+	return out
+}
+
+// controlCall sends one op to a daemon's control socket and decodes the
+// response into out.
+func controlCall(tunnelType string, port int, op string, out interface{}) error {
+	conn, err := net.DialTimeout("unix", controlSocketPath(tunnelType, port), controlTimeout)
+	if err != nil {
+		return fmt.Errorf("daemon not reachable: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(controlTimeout))
+
+	req, _ := json.Marshal(controlRequest{Op: op})
+	if _, err := conn.Write(append(req, '\n')); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var ctlErr controlError
+	if json.Unmarshal(line, &ctlErr) == nil && ctlErr.Error != "" {
+		return fmt.Errorf("daemon refused request: %s", ctlErr.Error)
+	}
+
+	if err := json.Unmarshal(line, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// queryDaemonStats asks a running daemon for its live stats.
+func queryDaemonStats(tunnelType string, port int) (*daemonStats, error) {
+	var out daemonStats
+	if err := controlCall(tunnelType, port, "stats", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// queryDaemonStatus asks a running daemon for its current info. A successful
+// response proves the process is alive, unlike a PID liveness guess.
+func queryDaemonStatus(tunnelType string, port int) (*DaemonInfo, error) {
+	var out DaemonInfo
+	if err := controlCall(tunnelType, port, "status", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// requestDaemonStop asks a running daemon to shut down gracefully.
+func requestDaemonStop(tunnelType string, port int) error {
+	var ack controlAck
+	if err := controlCall(tunnelType, port, "stop", &ack); err != nil {
+		return err
+	}
+	if !ack.OK {
+		return fmt.Errorf("daemon did not acknowledge stop")
+	}
+	return nil
+}
+
+// requestDaemonReload asks a running daemon to re-establish its tunnel.
+func requestDaemonReload(tunnelType string, port int) error {
+	var ack controlAck
+	if err := controlCall(tunnelType, port, "reload", &ack); err != nil {
+		return err
+	}
+	if !ack.OK {
+		return fmt.Errorf("daemon did not acknowledge reload")
+	}
+	return nil
+}
@@ -15,13 +15,19 @@ import (
 // DaemonInfo stores information about a running daemon process
 type DaemonInfo struct {
 	PID        int       `json:"pid"`
-	Type       string    `json:"type"`       // "http" or "tcp"
-	Port       int       `json:"port"`       // Local port being tunneled
-	Subdomain  string    `json:"subdomain"`  // Subdomain if specified
-	Server     string    `json:"server"`     // Server address
-	URL        string    `json:"url"`        // Tunnel URL
-	StartTime  time.Time `json:"start_time"` // When the daemon started
-	Executable string    `json:"executable"` // Path to the executable
+	Type       string    `json:"type"`               // "http" or "tcp"
+	Port       int       `json:"port"`               // Local port being tunneled
+	Subdomain  string    `json:"subdomain"`          // Subdomain if specified
+	Server     string    `json:"server"`             // Server address
+	URL        string    `json:"url"`                // Tunnel URL
+	StartTime  time.Time `json:"start_time"`         // When the daemon started
+	Executable string    `json:"executable"`         // Path to the executable
+	Services   []string  `json:"services,omitempty"` // Discovered service names (e.g. gRPC)
+
+	// Stability history, updated by the tunnel runner on drops/reconnects
+	Reconnects int           `json:"reconnects,omitempty"` // Number of connection drops
+	Downtime   time.Duration `json:"downtime,omitempty"`   // Cumulative time spent disconnected
+	LastDrop   time.Time     `json:"last_drop,omitempty"`  // When the connection last dropped
 }
 
 // getDaemonDir returns the directory for storing daemon info
@@ -143,6 +149,25 @@ func KillProcess(pid int) error {
 	return nil
 }
 
+// CheckExistingDaemon refuses to start a tunnel when a daemon for the same
+// type and port is already running, pointing the user at attach/stop instead.
+// Stale registry entries (process gone) are cleaned up and do not block.
+func CheckExistingDaemon(tunnelType string, port int) error {
+	info, err := LoadDaemonInfo(tunnelType, port)
+	if err != nil || info == nil {
+		return nil
+	}
+
+	if !IsProcessRunning(info.PID) {
+		RemoveDaemonInfo(tunnelType, port)
+		return nil
+	}
+
+	return fmt.Errorf("a %s tunnel for port %d is already running (PID %d)\n"+
+		"  use 'drip attach %s %d' to view it, or 'drip stop %s %d' to stop it",
+		tunnelType, port, info.PID, tunnelType, port, tunnelType, port)
+}
+
 // StartDaemon starts the current process as a daemon
 func StartDaemon(tunnelType string, port int, args []string) error {
 	// Get the executable path
@@ -10,10 +10,13 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-// getSysProcAttr returns platform-specific process attributes for daemonization
+// getSysProcAttr returns platform-specific process attributes for daemonization.
+// The daemon is fully detached from the launching console so closing the
+// terminal window does not take the tunnel down with it.
 func getSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
-		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
 	}
 }
 
@@ -30,13 +33,30 @@ func isProcessRunningOS(process *os.Process) bool {
 		return false
 	}
 
-	return exitCode == 259
+	return exitCode == uint32(windows.STILL_ACTIVE)
 }
 
-// killProcessOS kills a process using OS-specific method
+// killProcessOS kills a process using OS-specific method. The daemon is
+// pulled into a throwaway Job Object first, so any helper children it
+// spawned are terminated with it instead of being orphaned.
 func killProcessOS(process *os.Process) error {
-	// On Windows, use Kill() directly
-	return process.Kill()
+	handle, err := windows.OpenProcess(
+		windows.PROCESS_TERMINATE|windows.PROCESS_SET_QUOTA,
+		false, uint32(process.Pid),
+	)
+	if err != nil {
+		return process.Kill()
+	}
+	defer windows.CloseHandle(handle)
+
+	if job, err := windows.CreateJobObject(nil, nil); err == nil {
+		defer windows.CloseHandle(job)
+		if err := windows.AssignProcessToJobObject(job, handle); err == nil {
+			return windows.TerminateJobObject(job, 1)
+		}
+	}
+
+	return windows.TerminateProcess(handle, 1)
 }
 
 // setupDaemonCmd configures the command for daemon mode
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var rdpFileOut string
+
+var rdpCmd = &cobra.Command{
+	Use:   "rdp [port]",
+	Short: "Tunnel Remote Desktop (RDP) and emit a ready-to-open .rdp file",
+	Long: `Tunnel a local RDP server (default port 3389) and write a .rdp
+connection file pointing at the allocated public host and port.
+
+Example:
+  drip rdp                          Tunnel localhost:3389
+  drip rdp 3390 --file work.rdp     Custom port and output file`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runDesktopTunnel("rdp", 3389, args)
+	},
+}
+
+var vncCmd = &cobra.Command{
+	Use:   "vnc [port]",
+	Short: "Tunnel VNC and print a ready-to-open vnc:// URL",
+	Long: `Tunnel a local VNC server (default port 5900) and print a vnc://
+URL with the allocated public host and port.
+
+Example:
+  drip vnc                          Tunnel localhost:5900
+  drip vnc 5901                     Custom port`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runDesktopTunnel("vnc", 5900, args)
+	},
+}
+
+func init() {
+	rdpCmd.Flags().StringVar(&rdpFileOut, "file", "", "Path for the .rdp file (default: drip-<port>.rdp)")
+	rootCmd.AddCommand(rdpCmd)
+	rootCmd.AddCommand(vncCmd)
+}
+
+func runDesktopTunnel(kind string, defaultPort int, args []string) error {
+	port := defaultPort
+	if len(args) == 1 {
+		p, err := strconv.Atoi(args[0])
+		if err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("invalid port number: %s", args[0])
+		}
+		port = p
+	}
+
+	if err := CheckExistingDaemon("tcp", port); err != nil {
+		return err
+	}
+
+	serverAddr, token, err := resolveServerAddrAndToken(kind, port)
+	if err != nil {
+		return err
+	}
+
+	tunnelReadyHook = func(url string) {
+		host, publicPort, ok := splitTunnelURL(url)
+		if !ok {
+			return
+		}
+		switch kind {
+		case "rdp":
+			path := rdpFileOut
+			if path == "" {
+				path = fmt.Sprintf("drip-%d.rdp", port)
+			}
+			if err := writeRDPFile(path, host, publicPort); err != nil {
+				fmt.Println(ui.Warning(fmt.Sprintf("Failed to write RDP file: %v", err)))
+				return
+			}
+			fmt.Println(ui.Success("Connection file written: " + path))
+			fmt.Println(ui.Muted("  Open it with your Remote Desktop client."))
+		case "vnc":
+			fmt.Println(ui.Success("VNC URL: " + ui.URL(fmt.Sprintf("vnc://%s:%s", host, publicPort))))
+		}
+	}
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		TunnelType: protocol.TunnelTypeTCP,
+		LocalHost:  "127.0.0.1",
+		LocalPort:  port,
+		Subdomain:  subdomain,
+		Insecure:   insecure,
+	}
+
+	return runTunnelWithUI(connConfig, nil)
+}
+
+// splitTunnelURL extracts host and port from a tcp://host:port tunnel URL.
+func splitTunnelURL(url string) (host, port string, ok bool) {
+	addr := strings.TrimPrefix(url, "tcp://")
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", false
+	}
+	return host, port, true
+}
+
+// writeRDPFile emits a minimal .rdp connection file for the public endpoint.
+func writeRDPFile(path, host, port string) error {
+	content := strings.Join([]string{
+		fmt.Sprintf("full address:s:%s:%s", host, port),
+		"prompt for credentials:i:1",
+		"authentication level:i:2",
+		"screen mode id:i:2",
+		"use multimon:i:0",
+		"",
+	}, "\r\n")
+	return os.WriteFile(path, []byte(content), 0600)
+}
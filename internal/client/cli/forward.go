@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var forwardAddress string
+
+var forwardCmd = &cobra.Command{
+	Use:   "forward <subdomain> <local-port>",
+	Short: "Forward a remote tunnel to a local port",
+	Long: `Pull traffic from a tunnel on the server down to a local port — the
+opposite direction of a normal tunnel. Lets you reach a teammate's
+tunneled service (e.g. a database) without its public TCP port.
+
+Example:
+  drip forward alice-pg 5432        Reach tunnel 'alice-pg' on localhost:5432
+  drip forward api-demo 8080        Reach tunnel 'api-demo' on localhost:8080
+
+Note: Requires the same auth token as the tunnel's server`,
+	Args: cobra.ExactArgs(2),
+	RunE: runForward,
+}
+
+func init() {
+	forwardCmd.Flags().StringVarP(&forwardAddress, "address", "a", "127.0.0.1", "Local address to listen on (default: 127.0.0.1)")
+	rootCmd.AddCommand(forwardCmd)
+}
+
+func runForward(_ *cobra.Command, args []string) error {
+	targetSubdomain := args[0]
+	port, err := strconv.Atoi(args[1])
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", args[1])
+	}
+
+	serverAddr, token, err := resolveServerAddrAndToken("forward", port)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.InitLogger(verbose); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer utils.Sync()
+
+	client := tcp.NewForwardClient(&tcp.ForwardConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		Subdomain:  targetSubdomain,
+		LocalHost:  forwardAddress,
+		LocalPort:  port,
+		Insecure:   insecure,
+	}, utils.GetLogger())
+
+	if err := client.Start(); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Forwarding %s -> tunnel %q on %s", client.Addr(), targetSubdomain, serverAddr)))
+	fmt.Println(ui.Muted("Each local connection is piped into the tunnel. Press Ctrl+C to stop."))
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	fmt.Println(ui.RenderShuttingDown())
+	client.Stop()
+	fmt.Println(ui.Success("Forward closed"))
+	return nil
+}
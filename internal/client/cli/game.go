@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var gameUDPPort int
+
+var gameCmd = &cobra.Command{
+	Use:   "game <tcp-port>",
+	Short: "Tunnel a game server and print the address players should use",
+	Long: `Tunnel a local game server (Minecraft, Valheim, etc.) and print the
+single public host:port players connect to.
+
+Example:
+  drip game 25565                   Tunnel a Minecraft server
+  drip game 2456 --udp 2456         Tunnel a Valheim server (TCP + UDP)
+
+The --udp flag runs a paired UDP tunnel for games that use both transports.
+The paired tunnel gets its own public port; a custom subdomain gains a -udp
+suffix so the two registrations don't collide.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGame,
+}
+
+func init() {
+	gameCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	gameCmd.Flags().IntVar(&gameUDPPort, "udp", 0, "Also tunnel this local UDP port")
+	rootCmd.AddCommand(gameCmd)
+}
+
+func runGame(_ *cobra.Command, args []string) error {
+	port, err := strconv.Atoi(args[0])
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", args[0])
+	}
+
+	if gameUDPPort != 0 && (gameUDPPort < 1 || gameUDPPort > 65535) {
+		return fmt.Errorf("invalid UDP port number: %d", gameUDPPort)
+	}
+
+	if err := CheckExistingDaemon("tcp", port); err != nil {
+		return err
+	}
+
+	serverAddr, token, err := resolveServerAddrAndToken("game", port)
+	if err != nil {
+		return err
+	}
+
+	tunnelReadyHook = func(url string) {
+		host, publicPort, ok := splitTunnelURL(url)
+		if !ok {
+			return
+		}
+		fmt.Println(ui.Success("Players connect to: " + ui.URL(fmt.Sprintf("%s:%s", host, publicPort))))
+	}
+
+	if gameUDPPort != 0 {
+		if err := CheckExistingDaemon("udp", gameUDPPort); err != nil {
+			return err
+		}
+
+		udpSubdomain := subdomain
+		if udpSubdomain != "" {
+			udpSubdomain += "-udp"
+		}
+		udpConfig := &tcp.ConnectorConfig{
+			ServerAddr: serverAddr,
+			Token:      token,
+			TunnelType: protocol.TunnelTypeUDP,
+			LocalHost:  "127.0.0.1",
+			LocalPort:  gameUDPPort,
+			Subdomain:  udpSubdomain,
+			Insecure:   insecure,
+		}
+
+		if err := utils.InitLogger(verbose); err != nil {
+			return fmt.Errorf("failed to initialize logger: %w", err)
+		}
+
+		udpStop := make(chan struct{})
+		udpDone := make(chan struct{})
+		go runGameUDPTunnel(udpConfig, udpStop, udpDone)
+		defer func() {
+			close(udpStop)
+			select {
+			case <-udpDone:
+			case <-time.After(2 * time.Second):
+			}
+		}()
+	}
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		TunnelType: protocol.TunnelTypeTCP,
+		LocalHost:  "127.0.0.1",
+		LocalPort:  port,
+		Subdomain:  subdomain,
+		Insecure:   insecure,
+	}
+
+	return runTunnelWithUI(connConfig, nil)
+}
+
+// runGameUDPTunnel keeps the paired UDP tunnel connected alongside the
+// foreground TCP tunnel, a slimmed-down runTunnelWithUI without its own
+// display.
+func runGameUDPTunnel(connConfig *tcp.ConnectorConfig, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	logger := utils.GetLogger()
+	announced := false
+
+	for {
+		connector := tcp.NewTunnelClient(connConfig, logger)
+		if err := connector.Connect(); err != nil {
+			if isNonRetryableError(err) {
+				fmt.Println(ui.Error("UDP tunnel failed: " + err.Error()))
+				return
+			}
+			select {
+			case <-stop:
+				return
+			case <-time.After(reconnectInterval):
+				continue
+			}
+		}
+
+		if !announced {
+			if host, publicPort, ok := splitTunnelURL(connector.GetURL()); ok {
+				fmt.Println(ui.Success("UDP players connect to: " + ui.URL(fmt.Sprintf("%s:%s", host, publicPort))))
+				announced = true
+			}
+		}
+
+		disconnected := make(chan struct{})
+		go func() {
+			connector.Wait()
+			close(disconnected)
+		}()
+
+		select {
+		case <-stop:
+			connector.Close()
+			return
+		case <-disconnected:
+			select {
+			case <-stop:
+				return
+			case <-time.After(reconnectInterval):
+			}
+		}
+	}
+}
@@ -2,7 +2,7 @@ package cli
 
 import (
 	"fmt"
-	"strconv"
+	"time"
 
 	"drip/internal/client/tcp"
 	"drip/internal/shared/protocol"
@@ -11,10 +11,37 @@ import (
 )
 
 var (
-	subdomain    string
-	daemonMode   bool
-	daemonMarker bool
-	localAddress string
+	subdomain     string
+	daemonMode    bool
+	daemonMarker  bool
+	localAddress  string
+	unixSocket    string
+	localBackends []string
+	stickyMode    string
+	alertBytes    string
+	alertStop     bool
+
+	webhookProvider string
+	webhookSecret   string
+	webhookReject   bool
+	webhookDedupe   time.Duration
+
+	maxConcurrent int
+	dialRetry     time.Duration
+
+	transportMode string
+	remotePort    int
+	nameStyle     string
+	tunnelAlias   string
+	customDomain  string
+	webUI         bool
+	inspectLive   bool
+	outputMode    string
+	urlFile       string
+	redirectRules []string
+	overrideRules []string
+	trailingSlash bool
+	alsoTunnels   []string
 )
 
 var httpCmd = &cobra.Command{
@@ -25,29 +52,59 @@ var httpCmd = &cobra.Command{
 Example:
   drip http 3000                    Tunnel localhost:3000
   drip http 8080 --subdomain myapp  Use custom subdomain
+  drip http --unix /run/app.sock    Tunnel a unix domain socket
 
 Configuration:
   First time: Run 'drip config init' to save server and token
   Subsequent: Just run 'drip http <port>'
 
 Note: Uses TCP over TLS 1.3 for secure communication`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runHTTP,
 }
 
 func init() {
 	httpCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	httpCmd.Flags().StringVar(&nameStyle, "name-style", "", "Generated subdomain style: words for names like quiet-otter-42")
 	httpCmd.Flags().BoolVarP(&daemonMode, "daemon", "d", false, "Run in background (daemon mode)")
 	httpCmd.Flags().StringVarP(&localAddress, "address", "a", "127.0.0.1", "Local address to forward to (default: 127.0.0.1)")
+	httpCmd.Flags().StringVar(&unixSocket, "unix", "", "Forward to a unix domain socket instead of a port (e.g. /var/run/app.sock)")
+	httpCmd.Flags().StringSliceVar(&localBackends, "backend", nil, "Additional local backends (host:port) to balance across")
+	httpCmd.Flags().StringVar(&stickyMode, "sticky", "", "Sticky session mode with multiple backends: cookie or ip")
+	httpCmd.Flags().StringVar(&alertBytes, "alert-bytes", "", "Warn when cumulative traffic exceeds this limit (e.g. 5GB)")
+	httpCmd.Flags().BoolVar(&alertStop, "stop-at-limit", false, "Stop the tunnel instead of warning when --alert-bytes is reached")
+	httpCmd.Flags().StringVar(&webhookProvider, "verify-webhook", "", "Verify webhook signatures: stripe, github, or slack")
+	httpCmd.Flags().StringVar(&webhookSecret, "webhook-secret", "", "Webhook signing secret (or set DRIP_WEBHOOK_SECRET)")
+	httpCmd.Flags().BoolVar(&webhookReject, "reject-invalid", false, "Reject requests with invalid webhook signatures (401)")
+	httpCmd.Flags().DurationVar(&webhookDedupe, "dedupe-webhooks", 0, "Acknowledge duplicate webhook deliveries (same delivery ID) within this window without forwarding (e.g. 5m)")
+	httpCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum simultaneous requests forwarded to the local service (0 = unlimited)")
+	httpCmd.Flags().DurationVar(&dialRetry, "dial-retry", 0, "Retry refused local dials for this window before failing (smooths dev-server restarts, e.g. 5s)")
+	httpCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	httpCmd.Flags().StringVar(&tunnelAlias, "alias", "", "Stable alias that always resolves to this token's current tunnel")
+	httpCmd.Flags().StringVar(&customDomain, "domain", "", "Custom domain CNAMEd at the drip server (e.g. app.example.com)")
+	httpCmd.Flags().BoolVar(&webUI, "ui", false, "Serve a local web inspector (headers, bodies, replay) on 127.0.0.1:4040")
+	httpCmd.Flags().BoolVar(&inspectLive, "inspect", false, "Print a log line per request under the stats display")
+	httpCmd.Flags().StringVarP(&outputMode, "output", "o", "", "Output mode: json (machine-readable) or none")
+	httpCmd.Flags().StringVar(&urlFile, "url-file", "", "Write the assigned public URL to this file")
+	httpCmd.Flags().StringArrayVar(&redirectRules, "redirect", nil, "Server-side redirect rule 'from->to' (repeatable; 'from' may end in /*)")
+	httpCmd.Flags().StringArrayVar(&overrideRules, "override", nil, "Answer a path with a canned response: 'path->status[ body]' (repeatable; path may end in /*)")
+	httpCmd.Flags().BoolVar(&trailingSlash, "trailing-slash", false, "Redirect extensionless paths without a trailing slash to path + '/'")
+	httpCmd.Flags().StringArrayVar(&alsoTunnels, "also", nil, "Additional subdomain:port served over the same connection (repeatable)")
 	httpCmd.Flags().BoolVar(&daemonMarker, "daemon-child", false, "Internal flag for daemon child process")
 	httpCmd.Flags().MarkHidden("daemon-child")
 	rootCmd.AddCommand(httpCmd)
 }
 
 func runHTTP(_ *cobra.Command, args []string) error {
-	port, err := strconv.Atoi(args[0])
-	if err != nil || port < 1 || port > 65535 {
-		return fmt.Errorf("invalid port number: %s", args[0])
+	port, err := resolveLocalTarget(args)
+	if err != nil {
+		return err
+	}
+
+	if !daemonMarker {
+		if err := CheckExistingDaemon("http", port); err != nil {
+			return err
+		}
 	}
 
 	if daemonMode && !daemonMarker {
@@ -59,14 +116,59 @@ func runHTTP(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	if stickyMode != "" && stickyMode != "cookie" && stickyMode != "ip" {
+		return fmt.Errorf("invalid sticky mode: %s (must be 'cookie' or 'ip')", stickyMode)
+	}
+
+	if nameStyle != "" && nameStyle != "words" {
+		return fmt.Errorf("invalid name style: %s (must be 'words')", nameStyle)
+	}
+
+	if err := validateWebhookFlags(); err != nil {
+		return err
+	}
+
+	redirects, err := parseRedirectRules(redirectRules)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := parseOverrideRules(overrideRules)
+	if err != nil {
+		return err
+	}
+
+	extras, err := parseExtraTunnels(alsoTunnels)
+	if err != nil {
+		return err
+	}
+	announceExtraTunnels(extras)
+
 	connConfig := &tcp.ConnectorConfig{
-		ServerAddr: serverAddr,
-		Token:      token,
-		TunnelType: protocol.TunnelTypeHTTP,
-		LocalHost:  localAddress,
-		LocalPort:  port,
-		Subdomain:  subdomain,
-		Insecure:   insecure,
+		ServerAddr:         serverAddr,
+		Token:              token,
+		TunnelType:         protocol.TunnelTypeHTTP,
+		LocalHost:          localAddress,
+		LocalPort:          port,
+		Subdomain:          subdomain,
+		NameStyle:          nameStyle,
+		Alias:              tunnelAlias,
+		CustomDomain:       customDomain,
+		Insecure:           insecure,
+		LocalTargets:       buildLocalTargets(localAddress, port, localBackends),
+		StickyMode:         stickyMode,
+		Redirects:          redirects,
+		Overrides:          overrides,
+		ForceTrailingSlash: trailingSlash,
+		ExtraTunnels:       extras,
+		WebhookProvider:    webhookProvider,
+		WebhookSecret:      webhookSecret,
+		WebhookReject:      webhookReject,
+		WebhookDedupe:      webhookDedupe,
+		MaxConcurrent:      maxConcurrent,
+		DialRetry:          dialRetry,
+		LocalSocket:        unixSocket,
+		Transport:          transportMode,
 	}
 
 	var daemon *DaemonInfo
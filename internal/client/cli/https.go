@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"strconv"
 
 	"drip/internal/client/tcp"
 	"drip/internal/shared/protocol"
@@ -24,23 +23,52 @@ Configuration:
   Subsequent: Just run 'drip https <port>'
 
 Note: Uses TCP over TLS 1.3 for secure communication`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runHTTPS,
 }
 
 func init() {
 	httpsCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	httpsCmd.Flags().StringVar(&nameStyle, "name-style", "", "Generated subdomain style: words for names like quiet-otter-42")
 	httpsCmd.Flags().BoolVarP(&daemonMode, "daemon", "d", false, "Run in background (daemon mode)")
 	httpsCmd.Flags().StringVarP(&localAddress, "address", "a", "127.0.0.1", "Local address to forward to (default: 127.0.0.1)")
+	httpsCmd.Flags().StringVar(&unixSocket, "unix", "", "Forward to a unix domain socket instead of a port (e.g. /var/run/app.sock)")
+	httpsCmd.Flags().StringSliceVar(&localBackends, "backend", nil, "Additional local backends (host:port) to balance across")
+	httpsCmd.Flags().StringVar(&stickyMode, "sticky", "", "Sticky session mode with multiple backends: cookie or ip")
+	httpsCmd.Flags().StringVar(&alertBytes, "alert-bytes", "", "Warn when cumulative traffic exceeds this limit (e.g. 5GB)")
+	httpsCmd.Flags().BoolVar(&alertStop, "stop-at-limit", false, "Stop the tunnel instead of warning when --alert-bytes is reached")
+	httpsCmd.Flags().StringVar(&webhookProvider, "verify-webhook", "", "Verify webhook signatures: stripe, github, or slack")
+	httpsCmd.Flags().StringVar(&webhookSecret, "webhook-secret", "", "Webhook signing secret (or set DRIP_WEBHOOK_SECRET)")
+	httpsCmd.Flags().BoolVar(&webhookReject, "reject-invalid", false, "Reject requests with invalid webhook signatures (401)")
+	httpsCmd.Flags().DurationVar(&webhookDedupe, "dedupe-webhooks", 0, "Acknowledge duplicate webhook deliveries (same delivery ID) within this window without forwarding (e.g. 5m)")
+	httpsCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum simultaneous requests forwarded to the local service (0 = unlimited)")
+	httpsCmd.Flags().DurationVar(&dialRetry, "dial-retry", 0, "Retry refused local dials for this window before failing (smooths dev-server restarts, e.g. 5s)")
+	httpsCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	httpsCmd.Flags().StringVar(&tunnelAlias, "alias", "", "Stable alias that always resolves to this token's current tunnel")
+	httpsCmd.Flags().StringVar(&customDomain, "domain", "", "Custom domain CNAMEd at the drip server (e.g. app.example.com)")
+	httpsCmd.Flags().BoolVar(&webUI, "ui", false, "Serve a local web inspector (headers, bodies, replay) on 127.0.0.1:4040")
+	httpsCmd.Flags().BoolVar(&inspectLive, "inspect", false, "Print a log line per request under the stats display")
+	httpsCmd.Flags().StringVarP(&outputMode, "output", "o", "", "Output mode: json (machine-readable) or none")
+	httpsCmd.Flags().StringVar(&urlFile, "url-file", "", "Write the assigned public URL to this file")
+	httpsCmd.Flags().StringArrayVar(&redirectRules, "redirect", nil, "Server-side redirect rule 'from->to' (repeatable; 'from' may end in /*)")
+	httpsCmd.Flags().StringArrayVar(&overrideRules, "override", nil, "Answer a path with a canned response: 'path->status[ body]' (repeatable; path may end in /*)")
+	httpsCmd.Flags().BoolVar(&trailingSlash, "trailing-slash", false, "Redirect extensionless paths without a trailing slash to path + '/'")
+	httpsCmd.Flags().StringArrayVar(&alsoTunnels, "also", nil, "Additional subdomain:port served over the same connection (repeatable)")
 	httpsCmd.Flags().BoolVar(&daemonMarker, "daemon-child", false, "Internal flag for daemon child process")
 	httpsCmd.Flags().MarkHidden("daemon-child")
 	rootCmd.AddCommand(httpsCmd)
 }
 
 func runHTTPS(_ *cobra.Command, args []string) error {
-	port, err := strconv.Atoi(args[0])
-	if err != nil || port < 1 || port > 65535 {
-		return fmt.Errorf("invalid port number: %s", args[0])
+	port, err := resolveLocalTarget(args)
+	if err != nil {
+		return err
+	}
+
+	if !daemonMarker {
+		if err := CheckExistingDaemon("https", port); err != nil {
+			return err
+		}
 	}
 
 	if daemonMode && !daemonMarker {
@@ -52,14 +80,59 @@ func runHTTPS(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	if stickyMode != "" && stickyMode != "cookie" && stickyMode != "ip" {
+		return fmt.Errorf("invalid sticky mode: %s (must be 'cookie' or 'ip')", stickyMode)
+	}
+
+	if nameStyle != "" && nameStyle != "words" {
+		return fmt.Errorf("invalid name style: %s (must be 'words')", nameStyle)
+	}
+
+	if err := validateWebhookFlags(); err != nil {
+		return err
+	}
+
+	redirects, err := parseRedirectRules(redirectRules)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := parseOverrideRules(overrideRules)
+	if err != nil {
+		return err
+	}
+
+	extras, err := parseExtraTunnels(alsoTunnels)
+	if err != nil {
+		return err
+	}
+	announceExtraTunnels(extras)
+
 	connConfig := &tcp.ConnectorConfig{
-		ServerAddr: serverAddr,
-		Token:      token,
-		TunnelType: protocol.TunnelTypeHTTPS,
-		LocalHost:  localAddress,
-		LocalPort:  port,
-		Subdomain:  subdomain,
-		Insecure:   insecure,
+		ServerAddr:         serverAddr,
+		Token:              token,
+		TunnelType:         protocol.TunnelTypeHTTPS,
+		LocalHost:          localAddress,
+		LocalPort:          port,
+		Subdomain:          subdomain,
+		NameStyle:          nameStyle,
+		Alias:              tunnelAlias,
+		CustomDomain:       customDomain,
+		Insecure:           insecure,
+		LocalTargets:       buildLocalTargets(localAddress, port, localBackends),
+		StickyMode:         stickyMode,
+		Redirects:          redirects,
+		Overrides:          overrides,
+		ForceTrailingSlash: trailingSlash,
+		ExtraTunnels:       extras,
+		WebhookProvider:    webhookProvider,
+		WebhookSecret:      webhookSecret,
+		WebhookReject:      webhookReject,
+		WebhookDedupe:      webhookDedupe,
+		MaxConcurrent:      maxConcurrent,
+		DialRetry:          dialRetry,
+		LocalSocket:        unixSocket,
+		Transport:          transportMode,
 	}
 
 	var daemon *DaemonInfo
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"drip/internal/client/capture"
+	"drip/internal/client/webhook"
+	"drip/internal/shared/ui"
+	json "github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inspectMethod      string
+	inspectPath        string
+	inspectStatus      string
+	inspectMinDuration time.Duration
+	inspectHeader      string
+	inspectSort        string
+	inspectLimit       int
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <type> <port>",
+	Short: "Inspect captured tunnel requests",
+	Long: `Inspect recently captured requests for a tunnel.
+
+HTTP tunnels record recent exchanges (method, path, status, duration,
+sizes, content type) which can be filtered and sorted here.
+
+Examples:
+  drip inspect http 3000                          Recent requests
+  drip inspect http 3000 --status 5xx             Only server errors
+  drip inspect http 3000 --sort duration          Slowest first
+  drip inspect http 3000 --path /api --method POST
+  drip inspect http 3000 --min-duration 500ms`,
+	Args: cobra.ExactArgs(2),
+	RunE: runInspect,
+}
+
+func init() {
+	inspectCmd.Flags().StringVar(&inspectMethod, "method", "", "Filter by HTTP method")
+	inspectCmd.Flags().StringVar(&inspectPath, "path", "", "Filter by path substring")
+	inspectCmd.Flags().StringVar(&inspectStatus, "status", "", "Filter by status code (e.g. 404) or class (e.g. 5xx)")
+	inspectCmd.Flags().DurationVar(&inspectMinDuration, "min-duration", 0, "Only requests at least this slow")
+	inspectCmd.Flags().StringVar(&inspectHeader, "header", "", "Filter by header, e.g. 'Content-Type: json' or just 'Authorization'")
+	inspectCmd.Flags().StringVar(&inspectSort, "sort", "time", "Sort column: time, duration, size, or status")
+	inspectCmd.Flags().IntVar(&inspectLimit, "limit", 50, "Maximum number of requests to show")
+	rootCmd.AddCommand(inspectCmd)
+}
+
+// getCaptureDir returns the directory where capture snapshots are stored
+func getCaptureDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "capture")
+	}
+	return filepath.Join(home, ".drip", "capture")
+}
+
+// captureFilePath returns the snapshot path for a tunnel
+func captureFilePath(tunnelType string, port int) string {
+	return filepath.Join(getCaptureDir(), fmt.Sprintf("%s_%d.json", tunnelType, port))
+}
+
+// writeCaptureSnapshot persists the most recent captured requests so other
+// drip processes (drip inspect) can read them. Best effort.
+func writeCaptureSnapshot(path string, buf *capture.Buffer) {
+	entries := buf.List(capture.Filter{Limit: 200})
+	if len(entries) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
+
+// loadCaptureSnapshot reads a persisted capture snapshot
+func loadCaptureSnapshot(tunnelType string, port int) ([]*capture.Request, error) {
+	data, err := os.ReadFile(captureFilePath(tunnelType, port))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read capture snapshot: %w", err)
+	}
+
+	var entries []*capture.Request
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse capture snapshot: %w", err)
+	}
+	return entries, nil
+}
+
+// parseStatusFilter converts "404" or "5xx" into an inclusive status range.
+func parseStatusFilter(s string) (int, int, error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+
+	lower := strings.ToLower(s)
+	if strings.HasSuffix(lower, "xx") && len(lower) == 3 {
+		class, err := strconv.Atoi(lower[:1])
+		if err == nil && class >= 1 && class <= 5 {
+			return class * 100, class*100 + 99, nil
+		}
+	}
+
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 100 || code > 599 {
+		return 0, 0, fmt.Errorf("invalid status filter: %s (use a code like 404 or a class like 5xx)", s)
+	}
+	return code, code, nil
+}
+
+func runInspect(_ *cobra.Command, args []string) error {
+	tunnelType := args[0]
+	port, err := strconv.Atoi(args[1])
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", args[1])
+	}
+
+	statusMin, statusMax, err := parseStatusFilter(inspectStatus)
+	if err != nil {
+		return err
+	}
+
+	entries, err := loadCaptureSnapshot(tunnelType, port)
+	if err != nil {
+		return err
+	}
+
+	filter := capture.Filter{
+		Method:      inspectMethod,
+		Path:        inspectPath,
+		StatusMin:   statusMin,
+		StatusMax:   statusMax,
+		MinDuration: inspectMinDuration,
+		Header:      inspectHeader,
+	}
+
+	var matched []*capture.Request
+	for _, r := range entries {
+		if filter.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+
+	if len(matched) == 0 {
+		fmt.Println()
+		fmt.Println(ui.Info(
+			"No Captured Requests",
+			"",
+			ui.Muted("Requests are captured while an HTTP tunnel is running."),
+		))
+		return nil
+	}
+
+	switch inspectSort {
+	case "time":
+		sort.Slice(matched, func(i, j int) bool { return matched[i].Time.After(matched[j].Time) })
+	case "duration":
+		sort.Slice(matched, func(i, j int) bool { return matched[i].Duration > matched[j].Duration })
+	case "size":
+		sort.Slice(matched, func(i, j int) bool { return matched[i].ResponseSize > matched[j].ResponseSize })
+	case "status":
+		sort.Slice(matched, func(i, j int) bool { return matched[i].Status > matched[j].Status })
+	default:
+		return fmt.Errorf("invalid sort column: %s (must be time, duration, size, or status)", inspectSort)
+	}
+
+	if inspectLimit > 0 && len(matched) > inspectLimit {
+		matched = matched[:inspectLimit]
+	}
+
+	table := ui.NewTable([]string{"TIME", "METHOD", "PATH", "STATUS", "DURATION", "SIZE", "TYPE"}).
+		WithTitle(fmt.Sprintf("Captured Requests (%s :%d)", tunnelType, port))
+
+	for _, r := range matched {
+		table.AddRow([]string{
+			ui.Muted(r.Time.Format("15:04:05")),
+			ui.Highlight(r.Method) + verificationMark(r.Verification),
+			truncateRequestPath(r.Path, 40),
+			formatStatus(r.Status),
+			formatCaptureDuration(r.Duration),
+			formatCaptureSize(r.ResponseSize),
+			ui.Muted(shortContentType(r.ContentType)),
+		})
+	}
+
+	fmt.Print(table.Render())
+	return nil
+}
+
+// formatRequestLine renders one completed exchange for the live request
+// log (--inspect).
+func formatRequestLine(r *capture.Request) string {
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return fmt.Sprintf("%s %s %s %s %s %s",
+		ui.Muted(t.Format("15:04:05")),
+		ui.Highlight(r.Method)+verificationMark(r.Verification),
+		truncateRequestPath(r.Path, 40),
+		formatStatus(r.Status),
+		formatCaptureDuration(r.Duration),
+		formatCaptureSize(r.ResponseSize),
+	)
+}
+
+// truncateRequestPath shortens a URL path for table display. Unlike
+// truncatePath (attach.go), which preserves the filename of a filesystem
+// path, request paths just get cut with an ellipsis.
+func truncateRequestPath(path string, max int) string {
+	if len(path) <= max {
+		return path
+	}
+	return path[:max-1] + "…"
+}
+
+// verificationMark annotates a request when webhook signature verification
+// ran on it (--verify-webhook).
+func verificationMark(v string) string {
+	switch v {
+	case webhook.ResultVerified:
+		return ui.Green(" ✓")
+	case webhook.ResultInvalid:
+		return ui.Red(" ✗")
+	case webhook.ResultDuplicate:
+		return ui.Muted(" ⧉")
+	default:
+		return ""
+	}
+}
+
+func formatStatus(status int) string {
+	s := strconv.Itoa(status)
+	switch {
+	case status >= 500:
+		return ui.Red(s)
+	case status >= 400:
+		return ui.Yellow(s)
+	default:
+		return ui.Green(s)
+	}
+}
+
+func formatCaptureDuration(d time.Duration) string {
+	if d >= time.Second {
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+func formatCaptureSize(n int64) string {
+	if n < 0 {
+		return ui.Muted("-") // chunked/unknown length
+	}
+	return ui.FormatBytes(n)
+}
+
+// shortContentType strips parameters like "; charset=utf-8"
+func shortContentType(ct string) string {
+	if idx := strings.Index(ct, ";"); idx >= 0 {
+		ct = ct[:idx]
+	}
+	return strings.TrimSpace(ct)
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"drip/internal/shared/stats"
+	json "github.com/goccy/go-json"
+)
+
+// LifetimeStats holds cumulative per-tunnel statistics persisted across
+// restarts in the client state dir.
+type LifetimeStats struct {
+	Type          string        `json:"type"`
+	Port          int           `json:"port"`
+	TotalBytesIn  int64         `json:"total_bytes_in"`
+	TotalBytesOut int64         `json:"total_bytes_out"`
+	TotalRequests int64         `json:"total_requests"`
+	TotalUptime   time.Duration `json:"total_uptime"`
+	FirstStarted  time.Time     `json:"first_started"`
+	LastUpdated   time.Time     `json:"last_updated"`
+}
+
+// getLifetimeStatsDir returns the directory for persisted tunnel statistics
+func getLifetimeStatsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "stats")
+	}
+	return filepath.Join(home, ".drip", "stats")
+}
+
+// getLifetimeStatsPath returns the path to a tunnel's stats file
+func getLifetimeStatsPath(tunnelType string, port int) string {
+	return filepath.Join(getLifetimeStatsDir(), fmt.Sprintf("%s_%d.json", tunnelType, port))
+}
+
+// LoadLifetimeStats loads persisted stats for a tunnel, or nil if none exist
+func LoadLifetimeStats(tunnelType string, port int) (*LifetimeStats, error) {
+	data, err := os.ReadFile(getLifetimeStatsPath(tunnelType, port))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read lifetime stats: %w", err)
+	}
+
+	var ls LifetimeStats
+	if err := json.Unmarshal(data, &ls); err != nil {
+		return nil, fmt.Errorf("failed to parse lifetime stats: %w", err)
+	}
+
+	return &ls, nil
+}
+
+// SaveLifetimeStats persists stats for a tunnel
+func SaveLifetimeStats(ls *LifetimeStats) error {
+	dir := getLifetimeStatsDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create stats directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(ls, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lifetime stats: %w", err)
+	}
+
+	if err := os.WriteFile(getLifetimeStatsPath(ls.Type, ls.Port), data, 0600); err != nil {
+		return fmt.Errorf("failed to write lifetime stats: %w", err)
+	}
+
+	return nil
+}
+
+// ListAllLifetimeStats returns persisted stats for every known tunnel
+func ListAllLifetimeStats() ([]*LifetimeStats, error) {
+	dir := getLifetimeStatsDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read stats directory: %w", err)
+	}
+
+	var all []*LifetimeStats
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var ls LifetimeStats
+		if err := json.Unmarshal(data, &ls); err != nil {
+			continue
+		}
+
+		all = append(all, &ls)
+	}
+
+	return all, nil
+}
+
+// RecordSessionStats folds a finished session's snapshot into the persisted
+// lifetime stats for the tunnel. Errors are returned for the caller to log;
+// stats are advisory and must not abort the tunnel.
+func RecordSessionStats(tunnelType string, port int, snap stats.Snapshot) error {
+	ls, err := LoadLifetimeStats(tunnelType, port)
+	if err != nil || ls == nil {
+		ls = &LifetimeStats{
+			Type:         tunnelType,
+			Port:         port,
+			FirstStarted: time.Now().Add(-snap.Uptime),
+		}
+	}
+
+	ls.TotalBytesIn += snap.TotalBytesIn
+	ls.TotalBytesOut += snap.TotalBytesOut
+	ls.TotalRequests += snap.TotalRequests
+	ls.TotalUptime += snap.Uptime
+	ls.LastUpdated = time.Now()
+
+	return SaveLifetimeStats(ls)
+}
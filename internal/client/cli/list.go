@@ -39,10 +39,26 @@ In interactive mode, you can select a tunnel to:
 
 func init() {
 	listCmd.Flags().BoolVarP(&interactiveMode, "interactive", "i", false, "Interactive mode for attach/stop")
+	listCmd.Flags().StringVarP(&outputMode, "output", "o", "", "Output mode: json (machine-readable) or none")
 	rootCmd.AddCommand(listCmd)
 }
 
+// listOutput is one running tunnel in the --output json array.
+type listOutput struct {
+	Type       string `json:"type"`
+	Port       int    `json:"port"`
+	URL        string `json:"url"`
+	Subdomain  string `json:"subdomain"`
+	PID        int    `json:"pid"`
+	UptimeSec  int64  `json:"uptime_seconds"`
+	Reconnects int    `json:"reconnects"`
+}
+
 func runList(_ *cobra.Command, _ []string) error {
+	if err := validateOutputMode(); err != nil {
+		return err
+	}
+
 	CleanupStaleDaemons()
 
 	daemons, err := ListAllDaemons()
@@ -50,6 +66,32 @@ func runList(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to list daemons: %w", err)
 	}
 
+	if machineOutput() {
+		out := make([]listOutput, 0, len(daemons))
+		for _, d := range daemons {
+			if live, err := queryDaemonStatus(d.Type, d.Port); err == nil {
+				*d = *live
+			}
+			if !IsProcessRunning(d.PID) {
+				RemoveDaemonInfo(d.Type, d.Port)
+				continue
+			}
+			out = append(out, listOutput{
+				Type:       d.Type,
+				Port:       d.Port,
+				URL:        d.URL,
+				Subdomain:  d.Subdomain,
+				PID:        d.PID,
+				UptimeSec:  int64(time.Since(d.StartTime).Seconds()),
+				Reconnects: d.Reconnects,
+			})
+		}
+		if outputMode == "json" {
+			printJSON(out)
+		}
+		return nil
+	}
+
 	if len(daemons) == 0 {
 		fmt.Println()
 		fmt.Println(ui.Info(
@@ -63,11 +105,16 @@ func runList(_ *cobra.Command, _ []string) error {
 		return nil
 	}
 
-	table := ui.NewTable([]string{"#", "TYPE", "PORT", "URL", "PID", "UPTIME"}).
+	table := ui.NewTable([]string{"#", "TYPE", "PORT", "URL", "PID", "UPTIME", "DROPS", "DOWNTIME", "SERVICES"}).
 		WithTitle("Running Tunnels")
 
 	idx := 1
 	for _, d := range daemons {
+		// Prefer the daemon's own view over the registry file: the socket
+		// answers only while the process is alive and carries fresh counters.
+		if live, err := queryDaemonStatus(d.Type, d.Port); err == nil {
+			*d = *live
+		}
 		if !IsProcessRunning(d.PID) {
 			RemoveDaemonInfo(d.Type, d.Port)
 			continue
@@ -91,6 +138,9 @@ func runList(_ *cobra.Command, _ []string) error {
 			ui.URL(d.URL),
 			ui.Muted(fmt.Sprintf("%d", d.PID)),
 			FormatDuration(uptime),
+			formatDrops(d.Reconnects),
+			formatDowntime(d.Downtime),
+			formatServices(d.Services),
 		})
 		idx++
 	}
@@ -112,6 +162,33 @@ func runList(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+func formatDrops(reconnects int) string {
+	if reconnects == 0 {
+		return ui.Muted("0")
+	}
+	return fmt.Sprintf("%d", reconnects)
+}
+
+// formatServices renders discovered service labels (e.g. gRPC reflection),
+// truncated to keep the table readable.
+func formatServices(services []string) string {
+	if len(services) == 0 {
+		return ui.Muted("-")
+	}
+	joined := strings.Join(services, ", ")
+	if len(joined) > 40 {
+		joined = joined[:39] + "…"
+	}
+	return ui.Cyan(joined)
+}
+
+func formatDowntime(downtime time.Duration) string {
+	if downtime == 0 {
+		return ui.Muted("-")
+	}
+	return FormatDuration(downtime)
+}
+
 func runInteractiveList(daemons []*DaemonInfo) error {
 	var runningDaemons []*DaemonInfo
 	for _, d := range daemons {
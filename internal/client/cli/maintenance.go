@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"drip/internal/shared/ui"
+	"drip/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var maintenanceMessage string
+
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance <on|off> <type> <port>",
+	Short: "Toggle maintenance mode for a running tunnel",
+	Long: `Toggle maintenance mode for a running background tunnel.
+
+While maintenance mode is on, the server answers public requests for the
+tunnel's subdomain with a 503 maintenance page instead of forwarding them.
+The tunnel connection stays up, so turning maintenance off resumes traffic
+instantly.
+
+Examples:
+  drip maintenance on http 3000 --message "deploying"
+  drip maintenance off http 3000`,
+	Args: cobra.ExactArgs(3),
+	RunE: runMaintenance,
+}
+
+func init() {
+	maintenanceCmd.Flags().StringVarP(&maintenanceMessage, "message", "m", "", "Message shown on the maintenance page")
+	rootCmd.AddCommand(maintenanceCmd)
+}
+
+func runMaintenance(_ *cobra.Command, args []string) error {
+	var on bool
+	switch args[0] {
+	case "on":
+		on = true
+	case "off":
+		on = false
+	default:
+		return fmt.Errorf("invalid action: %s (must be 'on' or 'off')", args[0])
+	}
+
+	tunnelType := args[1]
+	if tunnelType != "http" && tunnelType != "https" {
+		return fmt.Errorf("invalid tunnel type: %s (maintenance mode applies to 'http' and 'https' tunnels)", tunnelType)
+	}
+
+	port, err := strconv.Atoi(args[2])
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", args[2])
+	}
+
+	info, err := LoadDaemonInfo(tunnelType, port)
+	if err != nil {
+		return fmt.Errorf("failed to load daemon info: %w", err)
+	}
+	if info == nil || info.Subdomain == "" {
+		return fmt.Errorf("no %s tunnel running on port %d (maintenance mode requires a background tunnel, see 'drip list')", tunnelType, port)
+	}
+
+	serverAddr := info.Server
+	token := authToken
+	if serverAddr == "" || token == "" {
+		if cfg, cfgErr := config.LoadClientConfig(""); cfgErr == nil {
+			if serverAddr == "" {
+				serverAddr = cfg.Server
+			}
+			if token == "" {
+				token = cfg.Token
+			}
+		}
+	}
+	if serverAddr == "" {
+		return fmt.Errorf("server address unknown, pass --server or run 'drip config init'")
+	}
+
+	if err := requestMaintenance(serverAddr, token, info.Subdomain, on, maintenanceMessage); err != nil {
+		return err
+	}
+
+	if on {
+		fmt.Println(ui.Success(fmt.Sprintf("Maintenance mode enabled for %s", info.Subdomain)))
+	} else {
+		fmt.Println(ui.Success(fmt.Sprintf("Maintenance mode disabled for %s", info.Subdomain)))
+	}
+	return nil
+}
+
+// requestMaintenance calls the server's /maintenance endpoint over the
+// public TLS port (the listener multiplexes HTTP on the same port).
+func requestMaintenance(serverAddr, token, subdomain string, on bool, message string) error {
+	tlsConfig := config.GetClientTLSConfigInsecure()
+	if !insecure {
+		host, _, _ := net.SplitHostPort(serverAddr)
+		tlsConfig = config.GetClientTLSConfig(host)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	params := url.Values{}
+	params.Set("subdomain", subdomain)
+	params.Set("on", strconv.FormatBool(on))
+	if message != "" {
+		params.Set("message", message)
+	}
+
+	reqURL := fmt.Sprintf("https://%s/maintenance?%s", serverAddr, params.Encode())
+	req, err := http.NewRequest(http.MethodPost, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("server rejected maintenance request: %s", string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// netwatchInterval is how often the watcher samples the clock and the
+	// interface set.
+	netwatchInterval = 3 * time.Second
+
+	// wakeJumpThreshold is how far the wall clock must jump past a ticker
+	// interval before it is treated as a sleep/wake cycle.
+	wakeJumpThreshold = 15 * time.Second
+)
+
+// watchNetworkChanges emits a reason whenever the machine appears to have
+// slept (the wall clock jumped across a ticker interval) or the set of
+// active interface addresses changed (Wi-Fi roam, VPN toggle, cable swap).
+// The tunnel runner uses it to reconnect immediately instead of leaving the
+// tunnel dead until a heartbeat timeout after a laptop wakes.
+func watchNetworkChanges(stop <-chan struct{}) <-chan string {
+	events := make(chan string, 1)
+
+	go func() {
+		ticker := time.NewTicker(netwatchInterval)
+		defer ticker.Stop()
+
+		last := time.Now()
+		lastFingerprint := interfaceFingerprint()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				// Compare wall clocks: the monotonic clock pauses during
+				// sleep on some platforms and would hide the gap.
+				wallDelta := now.Round(0).Sub(last.Round(0))
+				if wallDelta > netwatchInterval+wakeJumpThreshold {
+					lastFingerprint = interfaceFingerprint()
+					select {
+					case events <- "system woke from sleep":
+					default:
+					}
+				} else if fp := interfaceFingerprint(); fp != lastFingerprint {
+					lastFingerprint = fp
+					select {
+					case events <- "network configuration changed":
+					default:
+					}
+				}
+				last = now
+			}
+		}
+	}()
+
+	return events
+}
+
+// interfaceFingerprint summarizes the up interfaces and their addresses, so
+// route changes and VPN toggles show up as a changed string.
+func interfaceFingerprint() string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, ifc := range ifaces {
+		if ifc.Flags&net.FlagUp == 0 {
+			continue
+		}
+		sb.WriteString(ifc.Name)
+		addrs, _ := ifc.Addrs()
+		for _, addr := range addrs {
+			sb.WriteString(addr.String())
+		}
+		sb.WriteByte(';')
+	}
+	return sb.String()
+}
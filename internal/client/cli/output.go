@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	json "github.com/goccy/go-json"
+)
+
+// validateOutputMode checks the shared --output flag. "json" replaces the
+// styled display with one machine-readable object per event; "none"
+// silences the display entirely, for scripts that only want --url-file.
+func validateOutputMode() error {
+	switch outputMode {
+	case "", "json", "none":
+		return nil
+	}
+	return fmt.Errorf("invalid output mode: %s (must be 'json' or 'none')", outputMode)
+}
+
+// machineOutput reports whether the styled terminal display is suppressed.
+func machineOutput() bool {
+	return outputMode == "json" || outputMode == "none"
+}
+
+func printJSON(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// tunnelOutput is the object printed by --output json once the tunnel is
+// connected.
+type tunnelOutput struct {
+	URL       string `json:"url"`
+	Subdomain string `json:"subdomain"`
+	Type      string `json:"type"`
+	Local     string `json:"local"`
+	Server    string `json:"server"`
+}
+
+// writeURLFile writes the assigned public URL to the --url-file path,
+// replacing any previous contents, so scripts can read the URL without
+// parsing command output. Rewritten on every reconnect in case the
+// subdomain changed.
+func writeURLFile(url string) {
+	if urlFile == "" || url == "" {
+		return
+	}
+	if err := os.WriteFile(urlFile, []byte(url+"\n"), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write --url-file: %v\n", err)
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var pipeCmd = &cobra.Command{
+	Use:   "pipe",
+	Short: "Tunnel stdin/stdout to a public TCP endpoint",
+	Long: `Expose a public TCP endpoint connected to this process's stdin/stdout.
+
+Example:
+  tar cz ./dist | drip pipe         Share a one-shot download
+  drip pipe > received.bin          Receive bytes from whoever connects
+  drip pipe | jq .                  Pipe incoming data into another tool
+
+Visitors are served one at a time: bytes they send appear on stdout, and
+stdin is streamed back to them, netcat style. Status output goes to
+stderr so piped data stays clean.
+
+Configuration:
+  First time: Run 'drip config init' to save server and token
+  Subsequent: Just run 'drip pipe'`,
+	Args: cobra.NoArgs,
+	RunE: runPipe,
+}
+
+func init() {
+	pipeCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	pipeCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	pipeCmd.Flags().IntVar(&remotePort, "remote-port", 0, "Fixed public port, pinned to your token across restarts (0 = random)")
+	rootCmd.AddCommand(pipeCmd)
+}
+
+func runPipe(_ *cobra.Command, _ []string) error {
+	serverAddr, token, err := resolveServerAddrAndToken("pipe", 0)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.InitLogger(verbose); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer utils.Sync()
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		TunnelType: protocol.TunnelTypeTCP,
+		Subdomain:  subdomain,
+		Insecure:   insecure,
+		Transport:  transportMode,
+		RemotePort: remotePort,
+		PipeStdio:  true,
+	}
+
+	// No reconnect loop here: stdin is not replayable, so a lost control
+	// connection ends the pipe rather than silently resuming mid-stream.
+	connector := tcp.NewTunnelClient(connConfig, utils.GetLogger())
+	if err := connector.Connect(); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	fmt.Fprintln(os.Stderr, ui.Success(fmt.Sprintf("Piping stdin/stdout via %s", ui.URL(connector.GetURL()))))
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	disconnected := make(chan struct{})
+	go func() {
+		connector.Wait()
+		close(disconnected)
+	}()
+
+	select {
+	case <-quit:
+		fmt.Fprintln(os.Stderr, ui.RenderShuttingDown())
+		_ = connector.Close()
+	case <-disconnected:
+		fmt.Fprintln(os.Stderr, ui.RenderConnectionLost())
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"drip/internal/shared/ui"
+	"github.com/spf13/cobra"
+)
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload <type> <port>",
+	Short: "Ask a background tunnel to reconnect",
+	Long: `Tear down and re-establish the connection of a running background
+tunnel without restarting its process. Useful to force a fresh session
+after server-side changes.
+
+Example:
+  drip reload http 3000`,
+	Args: cobra.ExactArgs(2),
+	RunE: runReload,
+}
+
+func init() {
+	rootCmd.AddCommand(reloadCmd)
+}
+
+func runReload(_ *cobra.Command, args []string) error {
+	tunnelType := args[0]
+	if !isExactType(tunnelType) {
+		return fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', 'tcp', or 'udp')", tunnelType)
+	}
+	port, err := strconv.Atoi(args[1])
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", args[1])
+	}
+
+	info, err := LoadDaemonInfo(tunnelType, port)
+	if err != nil {
+		return err
+	}
+	if info == nil || !IsProcessRunning(info.PID) {
+		return fmt.Errorf("no running %s tunnel for port %d (see 'drip list')", tunnelType, port)
+	}
+
+	if err := requestDaemonReload(tunnelType, port); err != nil {
+		return fmt.Errorf("failed to reload tunnel: %w", err)
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Reload requested for %s tunnel on port %d", tunnelType, port)))
+	return nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 
+	"drip/internal/client/telemetry"
 	"drip/internal/shared/ui"
 
 	"github.com/spf13/cobra"
@@ -45,6 +46,12 @@ Features:
   ✓ Auto-save configuration
   ✓ Custom subdomains
   ✓ Authentication via token`,
+	// Anonymous usage counters are a no-op unless telemetry is enabled in
+	// the client config.
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		telemetry.Init()
+		telemetry.CountFeature(Version, cmd.Name())
+	},
 }
 
 func init() {
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"drip/internal/shared/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// scanTarget is one well-known local dev port to probe.
+type scanTarget struct {
+	Port  int
+	Label string
+	Type  string // tunnel type to suggest: "http" or "tcp"
+}
+
+// scanTargets lists the ports drip scan probes, in display order.
+var scanTargets = []scanTarget{
+	{3000, "Node / React / Rails", "http"},
+	{3001, "Node (alternate)", "http"},
+	{4200, "Angular", "http"},
+	{5173, "Vite", "http"},
+	{5000, "Flask / .NET", "http"},
+	{8000, "Django / FastAPI", "http"},
+	{8080, "Generic web server", "http"},
+	{8888, "Jupyter", "http"},
+	{4000, "Phoenix / Jekyll", "http"},
+	{1313, "Hugo", "http"},
+	{9000, "PHP-FPM / Play", "http"},
+	{5432, "PostgreSQL", "tcp"},
+	{3306, "MySQL", "tcp"},
+	{6379, "Redis", "tcp"},
+	{27017, "MongoDB", "tcp"},
+}
+
+const scanTimeout = 300 * time.Millisecond
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Discover local services and offer to tunnel them",
+	Long: `Probe common local development ports (web frameworks, databases)
+and offer to tunnel a detected service interactively.
+
+Example:
+  drip scan`,
+	RunE: runScan,
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+}
+
+func runScan(_ *cobra.Command, _ []string) error {
+	fmt.Println()
+	fmt.Println(ui.Muted(fmt.Sprintf("Scanning %d common ports on localhost...", len(scanTargets))))
+
+	detected := probePorts(scanTargets)
+	if len(detected) == 0 {
+		fmt.Println()
+		fmt.Println(ui.Info(
+			"No Services Detected",
+			"",
+			ui.Muted("Nothing is listening on the common dev ports."),
+			ui.Muted("Start your app, or tunnel a port directly:"),
+			"",
+			ui.Cyan("  drip http <port>"),
+		))
+		return nil
+	}
+
+	table := ui.NewTable([]string{"#", "PORT", "SERVICE", "TUNNEL"}).
+		WithTitle("Detected Services")
+	for i, t := range detected {
+		table.AddRow([]string{
+			ui.Highlight(strconv.Itoa(i + 1)),
+			strconv.Itoa(t.Port),
+			t.Label,
+			ui.Cyan(t.Type),
+		})
+	}
+	fmt.Print(table.Render())
+
+	fmt.Print(ui.Muted("Select a service to tunnel (number) or 'q' to quit: "))
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	input = strings.TrimSpace(input)
+	if input == "" || input == "q" || input == "Q" {
+		return nil
+	}
+
+	selection, err := strconv.Atoi(input)
+	if err != nil || selection < 1 || selection > len(detected) {
+		return fmt.Errorf("invalid selection: %s", input)
+	}
+
+	target := detected[selection-1]
+	fmt.Println()
+	fmt.Println(ui.Success(fmt.Sprintf("Starting %s tunnel for port %d (%s)", target.Type, target.Port, target.Label)))
+
+	args := []string{strconv.Itoa(target.Port)}
+	if target.Type == "tcp" {
+		return runTCP(nil, args)
+	}
+	return runHTTP(nil, args)
+}
+
+// probePorts dials the targets concurrently and returns those accepting
+// connections, preserving display order.
+func probePorts(targets []scanTarget) []scanTarget {
+	open := make([]bool, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i, port int) {
+			defer wg.Done()
+			conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), scanTimeout)
+			if err == nil {
+				conn.Close()
+				open[i] = true
+			}
+		}(i, t.Port)
+	}
+	wg.Wait()
+
+	var detected []scanTarget
+	for i, t := range targets {
+		if open[i] {
+			detected = append(detected, t)
+		}
+	}
+	return detected
+}
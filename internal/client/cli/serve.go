@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve <directory>",
+	Short: "Serve a local directory over a tunnel",
+	Long: `Serve a local directory with an embedded file server and tunnel it.
+
+Example:
+  drip serve ./dist                  Share a build output directory
+  drip serve . --subdomain demo      Share the current directory
+
+The embedded server handles index.html, directory listings, MIME types,
+and range requests, so there is no need to run a separate static file
+server first.
+
+Configuration:
+  First time: Run 'drip config init' to save server and token
+  Subsequent: Just run 'drip serve <directory>'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	serveCmd.Flags().BoolVarP(&daemonMode, "daemon", "d", false, "Run in background (daemon mode)")
+	serveCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	serveCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum simultaneous requests served (0 = unlimited)")
+	serveCmd.Flags().BoolVar(&daemonMarker, "daemon-child", false, "Internal flag for daemon child process")
+	serveCmd.Flags().MarkHidden("daemon-child")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(_ *cobra.Command, args []string) error {
+	dir, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid directory: %s", args[0])
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cannot serve %s: %w", args[0], err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", args[0])
+	}
+
+	if !daemonMarker {
+		if err := CheckExistingDaemon("serve", 0); err != nil {
+			return err
+		}
+	}
+
+	if daemonMode && !daemonMarker {
+		return StartDaemon("serve", 0, buildDaemonArgs("serve", args, subdomain, "127.0.0.1"))
+	}
+
+	serverAddr, token, err := resolveServerAddrAndToken("serve", 0)
+	if err != nil {
+		return err
+	}
+
+	// The file server binds an ephemeral loopback port; the tunnel forwards
+	// to it like any other local HTTP service. net/http's FileServer covers
+	// index.html, listings, MIME types, and range requests.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start file server: %w", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	fileServer := &http.Server{Handler: http.FileServer(http.Dir(dir))}
+	go func() {
+		_ = fileServer.Serve(ln)
+	}()
+	defer fileServer.Close()
+
+	fmt.Println(ui.Success(fmt.Sprintf("Serving %s", dir)))
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr:    serverAddr,
+		Token:         token,
+		TunnelType:    protocol.TunnelTypeHTTP,
+		LocalHost:     "127.0.0.1",
+		LocalPort:     port,
+		Subdomain:     subdomain,
+		Insecure:      insecure,
+		MaxConcurrent: maxConcurrent,
+		Transport:     transportMode,
+	}
+
+	var daemon *DaemonInfo
+	if daemonMarker {
+		daemon = newDaemonInfo("serve", port, subdomain, serverAddr)
+	}
+
+	return runTunnelWithUI(connConfig, daemon)
+}
@@ -1,22 +1,36 @@
 package cli
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"drip/internal/server/audit"
+	"drip/internal/server/notify"
+	"drip/internal/server/plugin"
+	"drip/internal/server/policy"
 	"drip/internal/server/proxy"
 	"drip/internal/server/tcp"
 	"drip/internal/server/tunnel"
+	"drip/internal/server/watchdog"
 	"drip/internal/shared/constants"
+	"drip/internal/shared/netutil"
 	"drip/internal/shared/utils"
 	"drip/pkg/config"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 var (
@@ -30,6 +44,26 @@ var (
 	serverTLSCert    string
 	serverTLSKey     string
 	serverPprofPort  int
+	serverMaxBytes   string
+	serverSNIPort    int
+	serverH2CPort    int
+	serverNotifyURL  string
+	serverSyslogAddr string
+	serverPolicy     string
+	serverPluginAddr string
+	serverTOTPSecret string
+	serverObserver   string
+	serverStatusPage bool
+	serverMaxUplink  string
+	serverMemBudget  string
+	serverSubLen     int
+	serverNameStyle  string
+	serverBrandBlock string
+	serverPowBits    int
+	serverWatchdog   string
+	serverPidFile    string
+	serverDrain      string
+	serverQUICPort   int
 )
 
 var serverCmd = &cobra.Command{
@@ -57,6 +91,58 @@ func init() {
 
 	// Performance profiling
 	serverCmd.Flags().IntVar(&serverPprofPort, "pprof", getEnvInt("DRIP_PPROF_PORT", 0), "Enable pprof on specified port (env: DRIP_PPROF_PORT)")
+
+	// Traffic limits
+	serverCmd.Flags().StringVar(&serverMaxBytes, "max-tunnel-bytes", getEnvString("DRIP_MAX_TUNNEL_BYTES", ""), "Per-tunnel traffic limit, e.g. 50GB (env: DRIP_MAX_TUNNEL_BYTES)")
+	serverCmd.Flags().StringVar(&serverMaxUplink, "max-uplink", getEnvString("DRIP_MAX_UPLINK", ""), "Outbound budget per second shared fairly across tunnels, e.g. 100MB (env: DRIP_MAX_UPLINK)")
+	serverCmd.Flags().StringVar(&serverMemBudget, "max-tunnel-memory", getEnvString("DRIP_MAX_TUNNEL_MEMORY", ""), "In-flight body bytes allowed per tunnel before 413/503, e.g. 64MB (env: DRIP_MAX_TUNNEL_MEMORY)")
+
+	// SNI routing for HTTPS tunnels (TLS stays end-to-end)
+	serverCmd.Flags().IntVar(&serverSNIPort, "sni-port", getEnvInt("DRIP_SNI_PORT", 0), "Route raw TLS by SNI to HTTPS tunnels on this port, e.g. 443 (env: DRIP_SNI_PORT)")
+
+	// Cleartext public edge with HTTP/2 (h2c), e.g. behind a TLS-terminating LB
+	serverCmd.Flags().IntVar(&serverH2CPort, "h2c-port", getEnvInt("DRIP_H2C_PORT", 0), "Serve the public HTTP handler in cleartext with HTTP/2 (h2c) on this port (env: DRIP_H2C_PORT)")
+
+	// Expiry notifications
+	serverCmd.Flags().StringVar(&serverNotifyURL, "notify-webhook", getEnvString("DRIP_NOTIFY_WEBHOOK", ""), "Webhook URL for expiring reservation/cert warnings (env: DRIP_NOTIFY_WEBHOOK)")
+	serverCmd.Flags().StringVar(&serverSyslogAddr, "syslog-addr", getEnvString("DRIP_SYSLOG_ADDR", ""), "Forward server events as RFC 5424 syslog to udp://host:port or tcp://host:port (env: DRIP_SYSLOG_ADDR)")
+	serverCmd.Flags().StringVar(&serverPolicy, "policy-script", getEnvString("DRIP_POLICY_SCRIPT", ""), "Path to a per-request policy rule script (deny/redirect/tag; env: DRIP_POLICY_SCRIPT)")
+	serverCmd.Flags().StringVar(&serverPluginAddr, "plugin-addr", getEnvString("DRIP_PLUGIN_ADDR", ""), "host:port of a gRPC plugin process for auth/routing/accounting (env: DRIP_PLUGIN_ADDR)")
+	serverCmd.Flags().StringVar(&serverTOTPSecret, "admin-totp", getEnvString("DRIP_ADMIN_TOTP", ""), "Base32 TOTP secret required for admin /login in addition to the token (env: DRIP_ADMIN_TOTP)")
+	serverCmd.Flags().StringVar(&serverObserver, "observer-token", getEnvString("DRIP_OBSERVER_TOKEN", ""), "Read-only token that can view stats and tunnels but not change anything (env: DRIP_OBSERVER_TOKEN)")
+	serverCmd.Flags().BoolVar(&serverStatusPage, "status-page", getEnvString("DRIP_STATUS_PAGE", "") == "true", "Serve a public status page on status.<domain> (env: DRIP_STATUS_PAGE)")
+	serverCmd.Flags().IntVar(&serverSubLen, "subdomain-length", getEnvInt("DRIP_SUBDOMAIN_LENGTH", 0), "Length of generated subdomains, 6-16 (env: DRIP_SUBDOMAIN_LENGTH)")
+	serverCmd.Flags().StringVar(&serverNameStyle, "name-style", getEnvString("DRIP_NAME_STYLE", ""), "Default generated subdomain style: words for names like quiet-otter-42 (env: DRIP_NAME_STYLE)")
+	serverCmd.Flags().StringVar(&serverBrandBlock, "brand-blocklist", getEnvString("DRIP_BRAND_BLOCKLIST", ""), "Comma-separated names to refuse in requested subdomains, added to the built-in phishing list (env: DRIP_BRAND_BLOCKLIST)")
+	serverCmd.Flags().IntVar(&serverPowBits, "registration-pow", getEnvInt("DRIP_REGISTRATION_POW", 0), "Require anonymous registrations to solve a proof-of-work of this many leading zero bits, 0 = off; ignored when --auth-token is set (env: DRIP_REGISTRATION_POW)")
+	serverCmd.Flags().StringVar(&serverWatchdog, "watchdog-interval", getEnvString("DRIP_WATCHDOG_INTERVAL", "1m"), "Health watchdog sampling interval, 0 to disable (env: DRIP_WATCHDOG_INTERVAL)")
+
+	// Process lifecycle for running under a supervisor
+	serverCmd.Flags().StringVar(&serverPidFile, "pid-file", getEnvString("DRIP_PID_FILE", ""), "Write the server PID to this file and remove it on exit (env: DRIP_PID_FILE)")
+	serverCmd.Flags().IntVar(&serverQUICPort, "quic-port", getEnvInt("DRIP_QUIC_PORT", 0), "Also accept control channels over QUIC on this UDP port, 0 = off; clients dial their configured server port, so this is usually the same number as --port (env: DRIP_QUIC_PORT)")
+	serverCmd.Flags().StringVar(&serverDrain, "drain-timeout", getEnvString("DRIP_DRAIN_TIMEOUT", ""), "On shutdown, stop accepting connections and wait up to this long for tunnels to disconnect, e.g. 30s (env: DRIP_DRAIN_TIMEOUT)")
+}
+
+// serverRootCmd is the entrypoint for the standalone drip-server binary. It
+// exposes the same flags as `drip server` at the top level and carries none
+// of the client-side machinery: no telemetry, no client config, no runtime
+// tuning derived from client assumptions.
+var serverRootCmd = &cobra.Command{
+	Use:   "drip-server",
+	Short: "Drip tunnel server",
+	Long: `Run the Drip tunnel server.
+
+Accepts the same flags as 'drip server'. Send SIGHUP to reload the TLS
+certificate, SIGTERM to shut down (draining first when --drain-timeout is
+set).`,
+	RunE: runServer,
+}
+
+// ExecuteServer runs the standalone server entrypoint used by cmd/drip-server.
+func ExecuteServer() error {
+	serverRootCmd.Version = Version
+	serverRootCmd.Flags().AddFlagSet(serverCmd.Flags())
+	return serverRootCmd.Execute()
 }
 
 func runServer(_ *cobra.Command, _ []string) error {
@@ -67,6 +153,15 @@ func runServer(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("TLS private key path is required (use --tls-key flag or DRIP_TLS_KEY environment variable)")
 	}
 
+	var drainTimeout time.Duration
+	if serverDrain != "" && serverDrain != "0" {
+		var err error
+		drainTimeout, err = time.ParseDuration(serverDrain)
+		if err != nil {
+			return fmt.Errorf("invalid --drain-timeout value %q: %w", serverDrain, err)
+		}
+	}
+
 	if err := utils.InitServerLogger(serverDebug); err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
@@ -74,6 +169,13 @@ func runServer(_ *cobra.Command, _ []string) error {
 
 	logger := utils.GetLogger()
 
+	if serverPidFile != "" {
+		if err := os.WriteFile(serverPidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+			logger.Fatal("Failed to write PID file", zap.String("path", serverPidFile), zap.Error(err))
+		}
+		defer os.Remove(serverPidFile)
+	}
+
 	logger.Info("Starting Drip Server",
 		zap.String("version", Version),
 		zap.String("commit", GitCommit),
@@ -117,8 +219,33 @@ func runServer(_ *cobra.Command, _ []string) error {
 		zap.String("key", serverTLSKey),
 	)
 
+	// Handshakes consult an atomic holder instead of a static certificate
+	// slice so SIGHUP can swap in a renewed certificate without a restart.
+	var activeCert atomic.Value
+	activeCert.Store(&tlsConfig.Certificates[0])
+	tlsConfig.Certificates = nil
+	tlsConfig.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return activeCert.Load().(*tls.Certificate), nil
+	}
+
 	tunnelManager := tunnel.NewManager(logger)
 
+	if serverSubLen > 0 {
+		tunnelManager.SetSubdomainLength(serverSubLen)
+		logger.Info("Generated subdomain length set", zap.Int("length", serverSubLen))
+	}
+
+	if serverNameStyle != "" {
+		tunnelManager.SetNameStyle(serverNameStyle)
+		logger.Info("Generated subdomain style set", zap.String("style", serverNameStyle))
+	}
+
+	if serverBrandBlock != "" {
+		entries := strings.Split(serverBrandBlock, ",")
+		tunnelManager.AddBrandBlocklist(entries)
+		logger.Info("Brand blocklist extended", zap.Int("entries", len(entries)))
+	}
+
 	portAllocator, err := tcp.NewPortAllocator(serverTCPPortMin, serverTCPPortMax)
 	if err != nil {
 		logger.Fatal("Invalid TCP port range", zap.Error(err))
@@ -126,14 +253,160 @@ func runServer(_ *cobra.Command, _ []string) error {
 
 	listenAddr := fmt.Sprintf("0.0.0.0:%d", serverPort)
 
-	httpHandler := proxy.NewHandler(tunnelManager, logger, serverDomain, serverAuthToken)
+	var maxTunnelBytes int64
+	if serverMaxBytes != "" {
+		maxTunnelBytes, err = parseByteSize(serverMaxBytes)
+		if err != nil {
+			logger.Fatal("Invalid --max-tunnel-bytes value", zap.String("value", serverMaxBytes), zap.Error(err))
+		}
+	}
+
+	var memBudget int64
+	if serverMemBudget != "" {
+		memBudget, err = parseByteSize(serverMemBudget)
+		if err != nil {
+			logger.Fatal("Invalid --max-tunnel-memory value", zap.String("value", serverMemBudget), zap.Error(err))
+		}
+	}
+
+	if serverMaxUplink != "" {
+		uplinkRate, err := parseByteSize(serverMaxUplink)
+		if err != nil {
+			logger.Fatal("Invalid --max-uplink value", zap.String("value", serverMaxUplink), zap.Error(err))
+		}
+		netutil.EnableFairScheduler(uplinkRate)
+		logger.Info("Fair uplink scheduling enabled",
+			zap.Int64("bytes_per_sec", uplinkRate),
+		)
+	}
+
+	rootCtx, rootCancel := context.WithCancel(context.Background())
+	defer rootCancel()
+
+	httpHandler := proxy.NewHandler(rootCtx, tunnelManager, logger, serverDomain, serverAuthToken, maxTunnelBytes, memBudget)
+
+	if serverTOTPSecret != "" {
+		httpHandler.SetAdminTOTP(serverTOTPSecret)
+		logger.Info("Admin TOTP login factor enabled")
+	}
+
+	if serverObserver != "" {
+		httpHandler.SetObserverToken(serverObserver)
+		logger.Info("Read-only observer role enabled")
+	}
+
+	if serverStatusPage {
+		httpHandler.SetStatusPage(true)
+		logger.Info("Public status page enabled", zap.String("host", "status."+serverDomain))
+	}
+
+	if serverPolicy != "" {
+		engine, err := policy.NewEngine(serverPolicy, logger)
+		if err != nil {
+			logger.Fatal("Invalid --policy-script", zap.String("path", serverPolicy), zap.Error(err))
+		}
+		httpHandler.SetPolicy(engine)
+		logger.Info("Policy script loaded", zap.String("path", serverPolicy))
+	}
 
 	listener := tcp.NewListener(listenAddr, tlsConfig, serverAuthToken, tunnelManager, logger, portAllocator, serverDomain, displayPort, httpHandler)
 
+	if serverPowBits > 0 {
+		if serverAuthToken != "" {
+			logger.Warn("--registration-pow ignored: registrations already require the auth token")
+		} else {
+			listener.SetRegistrationPoW(serverPowBits)
+			logger.Info("Anonymous registration proof-of-work enabled", zap.Int("bits", serverPowBits))
+		}
+	}
+
+	if serverPluginAddr != "" {
+		pluginClient := plugin.NewClient(serverPluginAddr, logger)
+		listener.SetPlugin(pluginClient)
+		httpHandler.SetPlugin(pluginClient)
+		logger.Info("Plugin extension enabled", zap.String("addr", serverPluginAddr))
+	}
+
 	if err := listener.Start(); err != nil {
 		logger.Fatal("Failed to start TCP listener", zap.Error(err))
 	}
 
+	if serverQUICPort > 0 {
+		if err := listener.StartQUIC(fmt.Sprintf("0.0.0.0:%d", serverQUICPort)); err != nil {
+			logger.Fatal("Failed to start QUIC listener", zap.Error(err))
+		}
+	}
+
+	// The watchdog catches slow leaks (goroutines, heap, frame backlogs,
+	// pending responses) before they become an OOM kill.
+	var dog *watchdog.Watchdog
+	if serverWatchdog != "" && serverWatchdog != "0" {
+		wdInterval, err := time.ParseDuration(serverWatchdog)
+		if err != nil {
+			logger.Fatal("Invalid --watchdog-interval value", zap.String("value", serverWatchdog), zap.Error(err))
+		}
+		dog = watchdog.New(logger, wdInterval, filepath.Join(os.TempDir(), "drip-watchdog"))
+		dog.AddGauge("tunnels", 100, func() int64 {
+			return int64(tunnelManager.Count())
+		})
+		dog.AddGauge("pending_responses", 256, func() int64 {
+			var total int64
+			for _, tc := range tunnelManager.List() {
+				total += tc.GetActiveConnections()
+			}
+			return total
+		})
+		dog.AddGauge("frame_backlog_bytes", 32<<20, func() int64 {
+			var total int64
+			for _, tc := range tunnelManager.List() {
+				total += tc.GetBufferedBytes()
+			}
+			return total
+		})
+		dog.Start()
+		logger.Info("Health watchdog enabled", zap.Duration("interval", wdInterval))
+	}
+
+	var h2cServer *http.Server
+	if serverH2CPort > 0 {
+		h2cServer = &http.Server{
+			Addr:              fmt.Sprintf("0.0.0.0:%d", serverH2CPort),
+			Handler:           h2c.NewHandler(httpHandler, &http2.Server{}),
+			ReadHeaderTimeout: constants.PublicReadHeaderTimeout,
+			MaxHeaderBytes:    constants.PublicMaxHeaderBytes,
+		}
+		go func() {
+			logger.Info("h2c public listener started", zap.Int("port", serverH2CPort))
+			if err := h2cServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("h2c listener failed", zap.Error(err))
+			}
+		}()
+	}
+
+	var sniRouter *tcp.SNIRouter
+	if serverSNIPort > 0 {
+		sniRouter = tcp.NewSNIRouter(rootCtx, fmt.Sprintf("0.0.0.0:%d", serverSNIPort), serverDomain, tunnelManager, logger)
+		if err := sniRouter.Start(); err != nil {
+			logger.Fatal("Failed to start SNI router", zap.Error(err))
+		}
+	}
+
+	var notifier *notify.Notifier
+	if serverNotifyURL != "" {
+		notifier = notify.NewNotifier(serverNotifyURL, serverTLSCert, tunnelManager, logger)
+		notifier.Start()
+	}
+
+	var syslogFwd *audit.SyslogForwarder
+	if serverSyslogAddr != "" {
+		syslogFwd, err = audit.NewSyslogForwarder(serverSyslogAddr, logger)
+		if err != nil {
+			logger.Fatal("Invalid --syslog-addr", zap.String("addr", serverSyslogAddr), zap.Error(err))
+		}
+		syslogFwd.Start(tunnelManager.Events())
+		logger.Info("Syslog forwarding enabled", zap.String("addr", serverSyslogAddr))
+	}
+
 	logger.Info("Drip Server started",
 		zap.String("address", listenAddr),
 		zap.String("domain", serverDomain),
@@ -143,14 +416,84 @@ func runServer(_ *cobra.Command, _ []string) error {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	<-quit
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	for running := true; running; {
+		select {
+		case <-reload:
+			cert, err := tls.LoadX509KeyPair(serverTLSCert, serverTLSKey)
+			if err != nil {
+				logger.Error("TLS certificate reload failed, keeping current certificate",
+					zap.String("cert", serverTLSCert),
+					zap.Error(err),
+				)
+				continue
+			}
+			activeCert.Store(&cert)
+			logger.Info("TLS certificate reloaded", zap.String("cert", serverTLSCert))
+		case <-quit:
+			running = false
+		}
+	}
+
+	if drainTimeout > 0 {
+		logger.Info("Draining before shutdown",
+			zap.Duration("timeout", drainTimeout),
+			zap.Int("tunnels", tunnelManager.Count()),
+		)
+		listener.Drain()
+
+		deadline := time.After(drainTimeout)
+		tick := time.NewTicker(time.Second)
+	drain:
+		for tunnelManager.Count() > 0 {
+			select {
+			case <-deadline:
+				break drain
+			case <-quit:
+				// A second signal cuts the drain short.
+				break drain
+			case <-tick.C:
+			}
+		}
+		tick.Stop()
+		logger.Info("Drain finished", zap.Int("tunnels_remaining", tunnelManager.Count()))
+	}
 
 	logger.Info("Shutting down server...")
+	rootCancel()
+
+	if dog != nil {
+		dog.Stop()
+	}
+
+	if notifier != nil {
+		notifier.Stop()
+	}
+
+	if syslogFwd != nil {
+		syslogFwd.Stop()
+	}
+
+	if sniRouter != nil {
+		sniRouter.Stop()
+	}
+
+	if h2cServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := h2cServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("h2c listener shutdown error", zap.Error(err))
+		}
+		cancel()
+	}
 
 	if err := listener.Stop(); err != nil {
 		logger.Error("Error stopping listener", zap.Error(err))
 	}
 
+	tunnelManager.Shutdown()
+
 	logger.Info("Server stopped")
 	return nil
 }
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"drip/internal/shared/ui"
+	"drip/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var serverRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the server (same as 'drip server')",
+	RunE:  runServer,
+}
+
+var serverTunnelsCmd = &cobra.Command{
+	Use:   "tunnels",
+	Short: "List tunnels on a running server",
+	Long: `Query a running Drip server's admin API and list its active
+tunnels, matching the ergonomics of 'drip list' for local daemons.
+
+The server address and token come from --server/--token or the saved
+client config ('drip config init').
+
+Example:
+  drip server tunnels
+  drip server tunnels --server drip.example.com:8443 --token SECRET`,
+	RunE: runServerTunnels,
+}
+
+func init() {
+	serverRunCmd.Flags().AddFlagSet(serverCmd.Flags())
+	serverCmd.AddCommand(serverRunCmd)
+	serverCmd.AddCommand(serverTunnelsCmd)
+}
+
+// serverStatsResponse mirrors the /stats endpoint payload.
+type serverStatsResponse struct {
+	TotalTunnels int `json:"total_tunnels"`
+	Tunnels      []struct {
+		Subdomain         string   `json:"subdomain"`
+		TunnelType        string   `json:"tunnel_type"`
+		LastActive        int64    `json:"last_active"`
+		BytesIn           int64    `json:"bytes_in"`
+		BytesOut          int64    `json:"bytes_out"`
+		ActiveConnections int64    `json:"active_connections"`
+		LatencyMs         int64    `json:"latency_ms"`
+		Services          []string `json:"services"`
+	} `json:"tunnels"`
+}
+
+func runServerTunnels(_ *cobra.Command, _ []string) error {
+	serverAddr := serverURL
+	token := authToken
+	if serverAddr == "" || token == "" {
+		if cfg, cfgErr := config.LoadClientConfig(""); cfgErr == nil {
+			if serverAddr == "" {
+				serverAddr = cfg.Server
+			}
+			if token == "" {
+				token = cfg.Token
+			}
+		}
+	}
+	if serverAddr == "" {
+		return fmt.Errorf("server address unknown, pass --server or run 'drip config init'")
+	}
+
+	stats, err := fetchServerStats(serverAddr, token)
+	if err != nil {
+		return err
+	}
+
+	if stats.TotalTunnels == 0 {
+		fmt.Println()
+		fmt.Println(ui.Info(
+			"No Active Tunnels",
+			"",
+			ui.Muted("The server has no connected tunnels."),
+		))
+		return nil
+	}
+
+	table := ui.NewTable([]string{"SUBDOMAIN", "TYPE", "ACTIVE", "RTT", "TRAFFIC", "LAST ACTIVE", "SERVICES"}).
+		WithTitle(fmt.Sprintf("Tunnels on %s", serverAddr))
+	for _, t := range stats.Tunnels {
+		table.AddRow([]string{
+			ui.Highlight(t.Subdomain),
+			ui.Cyan(t.TunnelType),
+			strconv.FormatInt(t.ActiveConnections, 10),
+			formatRTT(t.LatencyMs),
+			ui.FormatBytes(t.BytesIn + t.BytesOut),
+			formatLastActive(t.LastActive),
+			formatServices(t.Services),
+		})
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+// fetchServerStats calls the server's /stats endpoint over the public TLS
+// port (the listener multiplexes HTTP on the same port).
+func fetchServerStats(serverAddr, token string) (*serverStatsResponse, error) {
+	tlsConfig := config.GetClientTLSConfigInsecure()
+	if !insecure {
+		host, _, _ := net.SplitHostPort(serverAddr)
+		tlsConfig = config.GetClientTLSConfig(host)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/stats", serverAddr), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("server rejected stats request: %s", strings.TrimSpace(string(body)))
+	}
+
+	var stats serverStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to parse server response: %w", err)
+	}
+	return &stats, nil
+}
+
+// formatRTT renders a measured round-trip time, or a dash before the first
+// measurement completes.
+func formatRTT(ms int64) string {
+	if ms == 0 {
+		return ui.Muted("-")
+	}
+	return fmt.Sprintf("%dms", ms)
+}
+
+// formatLastActive renders a tunnel's last activity as a relative time.
+func formatLastActive(unix int64) string {
+	if unix == 0 {
+		return ui.Muted("-")
+	}
+	since := time.Since(time.Unix(unix, 0))
+	if since < time.Second {
+		return "now"
+	}
+	return since.Truncate(time.Second).String() + " ago"
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"drip/pkg/config"
+)
+
+// serverProbeTimeout bounds each endpoint probe at startup.
+const serverProbeTimeout = 3 * time.Second
+
+// serverFailover holds the remaining candidate endpoints, ordered by probed
+// latency, after the one currently in use. The reconnect loop consumes it
+// when a connection attempt fails.
+var serverFailover []string
+
+// selectServer picks the server endpoint to connect to. With a single
+// configured endpoint it is returned as-is; with several, all are probed
+// concurrently and the fastest wins, with the rest queued for failover.
+func selectServer(cfg *config.ClientConfig) string {
+	candidates := serverCandidates(cfg)
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	ordered := orderServersByLatency(candidates)
+	serverFailover = ordered[1:]
+	return ordered[0]
+}
+
+// nextFailoverServer pops the next candidate endpoint, if any remain.
+func nextFailoverServer() (string, bool) {
+	if len(serverFailover) == 0 {
+		return "", false
+	}
+	next := serverFailover[0]
+	serverFailover = serverFailover[1:]
+	return next, true
+}
+
+// serverCandidates merges the primary server and any extra endpoints,
+// dropping empties and duplicates while preserving config order.
+func serverCandidates(cfg *config.ClientConfig) []string {
+	seen := make(map[string]bool)
+	candidates := make([]string, 0, len(cfg.Servers)+1)
+	for _, addr := range append([]string{cfg.Server}, cfg.Servers...) {
+		if addr == "" || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		candidates = append(candidates, addr)
+	}
+	return candidates
+}
+
+// orderServersByLatency probes each endpoint with a TCP dial and returns the
+// candidates sorted fastest-first. Unreachable endpoints sort last, keeping
+// their config order, so they still serve as a final fallback.
+func orderServersByLatency(candidates []string) []string {
+	type probe struct {
+		addr string
+		rtt  time.Duration
+		ok   bool
+	}
+
+	probes := make([]probe, len(candidates))
+	var wg sync.WaitGroup
+	for i, addr := range candidates {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+
+			start := time.Now()
+			conn, err := net.DialTimeout("tcp", addr, serverProbeTimeout)
+			if err != nil {
+				probes[i] = probe{addr: addr}
+				return
+			}
+			conn.Close()
+			probes[i] = probe{addr: addr, rtt: time.Since(start), ok: true}
+		}(i, addr)
+	}
+	wg.Wait()
+
+	sort.SliceStable(probes, func(a, b int) bool {
+		if probes[a].ok != probes[b].ok {
+			return probes[a].ok
+		}
+		return probes[a].rtt < probes[b].rtt
+	})
+
+	ordered := make([]string, len(probes))
+	for i, p := range probes {
+		ordered[i] = p.addr
+	}
+	return ordered
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"drip/internal/server/tcp"
+	"drip/internal/shared/constants"
+	"drip/internal/shared/ui"
+	"drip/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var serverValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the server configuration before a restart",
+	Long: `Check the server configuration without starting it: certificate
+files, certificate expiry and hostname coverage, port availability, the
+TCP tunnel port range, and DNS resolution of the base domain. Prints
+actionable errors so a bad config is caught before a production restart.
+
+Example:
+  drip server validate --domain drip.example.com --tls-cert cert.pem --tls-key key.pem`,
+	RunE: runServerValidate,
+}
+
+func init() {
+	serverValidateCmd.Flags().AddFlagSet(serverCmd.Flags())
+	serverCmd.AddCommand(serverValidateCmd)
+}
+
+func runServerValidate(_ *cobra.Command, _ []string) error {
+	fmt.Println()
+	fmt.Println(ui.Muted("Validating server configuration..."))
+	fmt.Println()
+
+	failures := 0
+	fail := func(name string, err error) {
+		failures++
+		fmt.Println(ui.Error(fmt.Sprintf("%s: %v", name, err)))
+	}
+	pass := func(name, detail string) {
+		line := name
+		if detail != "" {
+			line += ": " + detail
+		}
+		fmt.Println(ui.Success(line))
+	}
+	warn := func(name, detail string) {
+		fmt.Println(ui.Warning(name + ": " + detail))
+	}
+
+	// Domain.
+	if serverDomain == "" {
+		fail("Domain", fmt.Errorf("no domain configured (use --domain or DRIP_DOMAIN)"))
+	} else if serverDomain == constants.DefaultDomain {
+		warn("Domain", fmt.Sprintf("using the default %q, public clients will not reach it", constants.DefaultDomain))
+	} else {
+		pass("Domain", serverDomain)
+	}
+
+	// TLS certificate.
+	serverConfig := &config.ServerConfig{
+		TLSEnabled:  true,
+		TLSCertFile: serverTLSCert,
+		TLSKeyFile:  serverTLSKey,
+	}
+	tlsConfig, err := serverConfig.LoadTLSConfig()
+	if err != nil {
+		fail("TLS certificate", err)
+	} else {
+		pass("TLS certificate", serverTLSCert)
+		validateCertificate(tlsConfig, pass, warn, fail)
+	}
+
+	// Listen port.
+	if ln, err := net.Listen("tcp", fmt.Sprintf(":%d", serverPort)); err != nil {
+		warn("Listen port", fmt.Sprintf("port %d is in use (server already running?)", serverPort))
+	} else {
+		ln.Close()
+		pass("Listen port", fmt.Sprintf("%d is available", serverPort))
+	}
+
+	// TCP tunnel port range.
+	if _, err := tcp.NewPortAllocator(serverTCPPortMin, serverTCPPortMax); err != nil {
+		fail("TCP port range", err)
+	} else {
+		pass("TCP port range", fmt.Sprintf("%d-%d", serverTCPPortMin, serverTCPPortMax))
+	}
+
+	// DNS.
+	if serverDomain != "" && serverDomain != constants.DefaultDomain {
+		if _, err := net.LookupHost(serverDomain); err != nil {
+			fail("DNS", fmt.Errorf("%s does not resolve: %v", serverDomain, err))
+		} else {
+			pass("DNS", serverDomain+" resolves")
+		}
+		if _, err := net.LookupHost("drip-validate." + serverDomain); err != nil {
+			warn("Wildcard DNS", fmt.Sprintf("*.%s does not resolve, tunnel subdomains will be unreachable", serverDomain))
+		} else {
+			pass("Wildcard DNS", "*."+serverDomain+" resolves")
+		}
+	}
+
+	// Auth.
+	if serverAuthToken == "" {
+		warn("Auth token", "not set, anyone can register tunnels")
+	} else {
+		pass("Auth token", "configured")
+	}
+
+	fmt.Println()
+	if failures > 0 {
+		return fmt.Errorf("%d check(s) failed", failures)
+	}
+	fmt.Println(ui.Success("Configuration is valid"))
+	return nil
+}
+
+// validateCertificate checks expiry and hostname coverage of the loaded
+// server certificate.
+func validateCertificate(tlsConfig *tls.Config, pass, warn func(name, detail string), fail func(name string, err error)) {
+	if tlsConfig == nil || len(tlsConfig.Certificates) == 0 || len(tlsConfig.Certificates[0].Certificate) == 0 {
+		return
+	}
+
+	cert, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	if err != nil {
+		fail("Certificate parse", err)
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case now.After(cert.NotAfter):
+		fail("Certificate expiry", fmt.Errorf("expired on %s", cert.NotAfter.Format("2006-01-02")))
+	case cert.NotAfter.Sub(now) < 30*24*time.Hour:
+		warn("Certificate expiry", fmt.Sprintf("expires in %d days (%s)",
+			int(cert.NotAfter.Sub(now).Hours()/24), cert.NotAfter.Format("2006-01-02")))
+	default:
+		pass("Certificate expiry", cert.NotAfter.Format("2006-01-02"))
+	}
+
+	if serverDomain != "" {
+		if err := cert.VerifyHostname(serverDomain); err != nil {
+			fail("Certificate hostname", fmt.Errorf("does not cover %s", serverDomain))
+		} else {
+			pass("Certificate hostname", "covers "+serverDomain)
+		}
+		if err := cert.VerifyHostname("drip-validate." + serverDomain); err != nil {
+			warn("Certificate wildcard", fmt.Sprintf("does not cover *.%s, HTTPS tunnels will show certificate errors", serverDomain))
+		} else {
+			pass("Certificate wildcard", "covers *."+serverDomain)
+		}
+	}
+}
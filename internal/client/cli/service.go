@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"drip/internal/shared/ui"
+	"github.com/spf13/cobra"
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Run a tunnel as a system service",
+	Long: `Install a tunnel as a system service so it starts at boot and is
+restarted by the service manager: systemd on Linux, launchd on macOS, and
+the Windows service manager.
+
+Example:
+  drip service install http 3000 --subdomain app
+  drip service status http 3000
+  drip service uninstall http 3000`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install <type> <port>",
+	Short: "Install a tunnel as a system service",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runServiceInstall,
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status <type> <port>",
+	Short: "Show the state of an installed tunnel service",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runServiceStatus,
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall <type> <port>",
+	Short: "Stop and remove an installed tunnel service",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runServiceUninstall,
+}
+
+func init() {
+	serviceInstallCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	serviceInstallCmd.Flags().StringVarP(&localAddress, "address", "a", "127.0.0.1", "Local address to forward to (default: 127.0.0.1)")
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	rootCmd.AddCommand(serviceCmd)
+}
+
+// parseServiceArgs validates the <type> <port> pair shared by every service
+// subcommand.
+func parseServiceArgs(args []string) (string, int, error) {
+	tunnelType := args[0]
+	if !isExactType(tunnelType) {
+		return "", 0, fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', 'tcp', or 'udp')", tunnelType)
+	}
+	port, err := strconv.Atoi(args[1])
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port number: %s", args[1])
+	}
+	return tunnelType, port, nil
+}
+
+// serviceName returns the service manager identifier for a tunnel.
+func serviceName(tunnelType string, port int) string {
+	return fmt.Sprintf("drip-%s-%d", tunnelType, port)
+}
+
+func runServiceInstall(_ *cobra.Command, args []string) error {
+	tunnelType, port, err := parseServiceArgs(args)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable path: %w", err)
+	}
+
+	// The service runs the tunnel in the foreground; the service manager
+	// supervises and restarts it, not the drip daemon machinery.
+	cmdArgs := []string{tunnelType, strconv.Itoa(port)}
+	if subdomain != "" {
+		cmdArgs = append(cmdArgs, "--subdomain", subdomain)
+	}
+	if localAddress != "" && localAddress != "127.0.0.1" {
+		cmdArgs = append(cmdArgs, "--address", localAddress)
+	}
+	if serverURL != "" {
+		cmdArgs = append(cmdArgs, "--server", serverURL)
+	}
+	if authToken != "" {
+		cmdArgs = append(cmdArgs, "--token", authToken)
+	}
+
+	name := serviceName(tunnelType, port)
+	description := fmt.Sprintf("drip %s tunnel for local port %d", strings.ToUpper(tunnelType), port)
+
+	path, err := installService(name, description, executable, cmdArgs)
+	if err != nil {
+		return fmt.Errorf("failed to install service: %w", err)
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Installed service %s", name)))
+	fmt.Println(ui.Muted("  " + path))
+	fmt.Println(ui.Muted(fmt.Sprintf("  drip service status %s %d       Check it", tunnelType, port)))
+	fmt.Println(ui.Muted(fmt.Sprintf("  drip service uninstall %s %d    Remove it", tunnelType, port)))
+	return nil
+}
+
+func runServiceStatus(_ *cobra.Command, args []string) error {
+	tunnelType, port, err := parseServiceArgs(args)
+	if err != nil {
+		return err
+	}
+
+	state, err := serviceState(serviceName(tunnelType, port))
+	if err != nil {
+		return fmt.Errorf("failed to query service: %w", err)
+	}
+
+	if state == "running" {
+		fmt.Println(ui.Success(fmt.Sprintf("%s is %s", serviceName(tunnelType, port), state)))
+	} else {
+		fmt.Println(ui.Warning(fmt.Sprintf("%s is %s", serviceName(tunnelType, port), state)))
+	}
+	return nil
+}
+
+func runServiceUninstall(_ *cobra.Command, args []string) error {
+	tunnelType, port, err := parseServiceArgs(args)
+	if err != nil {
+		return err
+	}
+
+	name := serviceName(tunnelType, port)
+	if err := uninstallService(name); err != nil {
+		return fmt.Errorf("failed to uninstall service: %w", err)
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Uninstalled service %s", name)))
+	return nil
+}
@@ -0,0 +1,97 @@
+//go:build darwin
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Services install as launchd agents under ~/Library/LaunchAgents with a
+// com.drip.<name> label.
+
+const launchdPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+</dict>
+</plist>
+`
+
+func launchdLabel(name string) string {
+	return "com.drip." + name
+}
+
+func launchdPlistPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel(name)+".plist"), nil
+}
+
+// installService writes a launchd plist and loads it. Returns the plist
+// path.
+func installService(name, _ string, executable string, args []string) (string, error) {
+	path, err := launchdPlistPath(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create LaunchAgents directory: %w", err)
+	}
+
+	var programArgs strings.Builder
+	for _, arg := range append([]string{executable}, args...) {
+		fmt.Fprintf(&programArgs, "\t\t<string>%s</string>\n", arg)
+	}
+	plist := fmt.Sprintf(launchdPlistTemplate, launchdLabel(name), programArgs.String())
+	if err := os.WriteFile(path, []byte(plist), 0644); err != nil {
+		return "", fmt.Errorf("failed to write plist: %w", err)
+	}
+
+	if out, err := exec.Command("launchctl", "load", "-w", path).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("launchctl load: %s", strings.TrimSpace(string(out)))
+	}
+	return path, nil
+}
+
+// serviceState reports whether the launchd job is loaded and running.
+func serviceState(name string) (string, error) {
+	out, err := exec.Command("launchctl", "list", launchdLabel(name)).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("no such service: %s", name)
+	}
+	// launchctl list prints a PID key for running jobs and "-" otherwise.
+	if strings.Contains(string(out), `"PID"`) {
+		return "running", nil
+	}
+	return "loaded (not running)", nil
+}
+
+// uninstallService unloads the launchd job and removes its plist.
+func uninstallService(name string) error {
+	path, err := launchdPlistPath(name)
+	if err != nil {
+		return err
+	}
+	if out, err := exec.Command("launchctl", "unload", "-w", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl unload: %s", strings.TrimSpace(string(out)))
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove plist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+//go:build linux
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Services install as systemd units: a system unit when running as root,
+// a user unit (systemctl --user) otherwise.
+
+const systemdUnitTemplate = `[Unit]
+Description=%s
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart=%s
+Restart=always
+RestartSec=5
+
+[Install]
+WantedBy=%s
+`
+
+func systemdUserMode() bool {
+	return os.Geteuid() != 0
+}
+
+func systemdUnitPath(name string) (string, error) {
+	if !systemdUserMode() {
+		return filepath.Join("/etc/systemd/system", name+".service"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user", name+".service"), nil
+}
+
+func systemctl(args ...string) error {
+	if systemdUserMode() {
+		args = append([]string{"--user"}, args...)
+	}
+	cmd := exec.Command("systemctl", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// installService writes a systemd unit, enables it, and starts it. Returns
+// the unit path.
+func installService(name, description, executable string, args []string) (string, error) {
+	path, err := systemdUnitPath(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create unit directory: %w", err)
+	}
+
+	execStart := executable + " " + strings.Join(args, " ")
+	target := "multi-user.target"
+	if systemdUserMode() {
+		target = "default.target"
+	}
+	unit := fmt.Sprintf(systemdUnitTemplate, description, execStart, target)
+	if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+		return "", fmt.Errorf("failed to write unit file: %w", err)
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		return "", err
+	}
+	if err := systemctl("enable", "--now", name+".service"); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// serviceState reports the systemd unit's active state.
+func serviceState(name string) (string, error) {
+	args := []string{"is-active", name + ".service"}
+	if systemdUserMode() {
+		args = append([]string{"--user"}, args...)
+	}
+	out, _ := exec.Command("systemctl", args...).CombinedOutput()
+	state := strings.TrimSpace(string(out))
+	if state == "" {
+		return "", fmt.Errorf("no such service: %s", name)
+	}
+	if state == "active" {
+		return "running", nil
+	}
+	return state, nil
+}
+
+// uninstallService stops, disables, and removes the systemd unit.
+func uninstallService(name string) error {
+	if err := systemctl("disable", "--now", name+".service"); err != nil {
+		return err
+	}
+	path, err := systemdUnitPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove unit file: %w", err)
+	}
+	return systemctl("daemon-reload")
+}
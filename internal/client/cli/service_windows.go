@@ -0,0 +1,59 @@
+//go:build windows
+
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Services install through the Windows service manager with sc.exe.
+
+func scRun(args ...string) (string, error) {
+	out, err := exec.Command("sc", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("sc %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// installService registers and starts a Windows service. Returns the
+// service identifier.
+func installService(name, description, executable string, args []string) (string, error) {
+	binPath := fmt.Sprintf("\"%s\" %s", executable, strings.Join(args, " "))
+	if _, err := scRun("create", name, "binPath=", binPath, "start=", "auto", "DisplayName=", description); err != nil {
+		return "", err
+	}
+	if _, err := scRun("description", name, description); err != nil {
+		return "", err
+	}
+	if _, err := scRun("start", name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// serviceState reports the Windows service state.
+func serviceState(name string) (string, error) {
+	out, err := scRun("query", name)
+	if err != nil {
+		return "", fmt.Errorf("no such service: %s", name)
+	}
+	switch {
+	case strings.Contains(out, "RUNNING"):
+		return "running", nil
+	case strings.Contains(out, "STOPPED"):
+		return "stopped", nil
+	default:
+		return "unknown", nil
+	}
+}
+
+// uninstallService stops and deletes the Windows service.
+func uninstallService(name string) error {
+	// Stop may fail when the service is already stopped; deletion decides.
+	_, _ = scRun("stop", name)
+	_, err := scRun("delete", name)
+	return err
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareExpire       time.Duration
+	shareMaxDownloads int
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <file-or-directory>",
+	Short: "Share a file or directory through an ephemeral tunnel",
+	Long: `Serve a file or directory read-only and tunnel it under a random
+subdomain for quick one-off transfers. The link expires automatically.
+
+Example:
+  drip share ./build.zip                    Share one file for an hour
+  drip share ./dist --expire 10m            Share a directory for 10 minutes
+  drip share ./key.pem --max-downloads 1    Link dies after one download`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	shareCmd.Flags().DurationVar(&shareExpire, "expire", time.Hour, "Close the tunnel after this duration")
+	shareCmd.Flags().IntVar(&shareMaxDownloads, "max-downloads", 0, "Close the tunnel after this many downloads (0 = unlimited)")
+	rootCmd.AddCommand(shareCmd)
+}
+
+func runShare(_ *cobra.Command, args []string) error {
+	path := args[0]
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot share %s: %w", path, err)
+	}
+
+	if err := utils.InitLogger(verbose); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer utils.Sync()
+	logger := utils.GetLogger()
+
+	serverAddr, token, err := resolveServerAddrAndToken("share", 0)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start local server: %w", err)
+	}
+	localPort := listener.Addr().(*net.TCPAddr).Port
+
+	var downloads atomic.Int64
+	limitReached := make(chan struct{})
+	srv := &http.Server{Handler: shareHandler(path, info.IsDir(), &downloads, limitReached)}
+	go srv.Serve(listener)
+	defer srv.Shutdown(context.Background())
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		TunnelType: protocol.TunnelTypeHTTP,
+		LocalHost:  "127.0.0.1",
+		LocalPort:  localPort,
+		Insecure:   insecure,
+	}
+
+	connector := tcp.NewTunnelClient(connConfig, logger)
+	fmt.Println(ui.RenderConnecting(serverAddr, 0, 1))
+	if err := connector.Connect(); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer connector.Close()
+
+	lines := []string{
+		ui.KeyValue("Sharing", path),
+		ui.KeyValue("Link", connector.GetURL()),
+		ui.KeyValue("Expires", fmt.Sprintf("in %s", shareExpire)),
+	}
+	if shareMaxDownloads > 0 {
+		lines = append(lines, ui.KeyValue("Downloads", fmt.Sprintf("max %d", shareMaxDownloads)))
+	}
+	lines = append(lines, "", ui.Muted("Press Ctrl+C to stop sharing."))
+	fmt.Println(ui.SuccessBox("Share Link Ready", lines...))
+
+	disconnected := make(chan struct{})
+	go func() {
+		connector.Wait()
+		close(disconnected)
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case <-quit:
+		fmt.Println(ui.Success("Share stopped"))
+	case <-time.After(shareExpire):
+		fmt.Println(ui.Success("Share link expired"))
+	case <-limitReached:
+		fmt.Println(ui.Success(fmt.Sprintf("Download limit reached (%d)", shareMaxDownloads)))
+	case <-disconnected:
+		return fmt.Errorf("connection to server lost")
+	}
+	return nil
+}
+
+// shareHandler serves the shared path read-only, counting downloads and
+// signalling limitReached when the configured maximum is hit.
+func shareHandler(path string, isDir bool, downloads *atomic.Int64, limitReached chan struct{}) http.Handler {
+	var serve http.Handler
+	if isDir {
+		serve = http.FileServer(http.Dir(path))
+	} else {
+		filename := filepath.Base(path)
+		serve = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			http.ServeFile(w, r, path)
+		})
+	}
+
+	var closeOnce sync.Once
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		serve.ServeHTTP(w, r)
+
+		if r.Method == http.MethodGet {
+			n := downloads.Add(1)
+			if shareMaxDownloads > 0 && n >= int64(shareMaxDownloads) {
+				closeOnce.Do(func() { close(limitReached) })
+			}
+		}
+	})
+}
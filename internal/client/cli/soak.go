@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+	json "github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	soakDuration time.Duration
+	soakInterval time.Duration
+	soakReport   string
+)
+
+var soakCmd = &cobra.Command{
+	Use:   "soak",
+	Short: "Run a long-haul connection stability test",
+	Long: `Keep a tunnel up for a long period and record how it behaves.
+
+A built-in local HTTP endpoint is tunneled and probed through the public
+URL at a fixed interval; each probe records round-trip latency and the
+client's heap and goroutine counts, and every disconnect is logged. At the
+end (or on Ctrl+C) a JSON report is written for diagnosing long-haul
+stability issues: disconnect clustering, latency drift, memory growth.
+
+Example:
+  drip soak                          1 hour with 30s probes
+  drip soak --duration 24h           Full-day soak
+  drip soak --interval 10s --report soak.json`,
+	RunE: runSoak,
+}
+
+func init() {
+	soakCmd.Flags().DurationVar(&soakDuration, "duration", time.Hour, "How long to keep the tunnel up")
+	soakCmd.Flags().DurationVar(&soakInterval, "interval", 30*time.Second, "Time between self-probes")
+	soakCmd.Flags().StringVar(&soakReport, "report", "", "Report file path (default ~/.drip/soak/soak-<timestamp>.json)")
+	rootCmd.AddCommand(soakCmd)
+}
+
+// soakProbe is one self-probe through the public URL.
+type soakProbe struct {
+	Time       time.Time `json:"time"`
+	LatencyMS  int64     `json:"latency_ms"`
+	Error      string    `json:"error,omitempty"`
+	HeapBytes  uint64    `json:"heap_bytes"`
+	Goroutines int       `json:"goroutines"`
+}
+
+// soakResult is the report written at the end of a soak run.
+type soakResult struct {
+	Server   string    `json:"server"`
+	URL      string    `json:"url"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+
+	Probes        []soakProbe `json:"probes"`
+	ProbeFailures int         `json:"probe_failures"`
+
+	Disconnects     int         `json:"disconnects"`
+	DisconnectTimes []time.Time `json:"disconnect_times,omitempty"`
+
+	LatencyMinMS   int64 `json:"latency_min_ms"`
+	LatencyAvgMS   int64 `json:"latency_avg_ms"`
+	LatencyMaxMS   int64 `json:"latency_max_ms"`
+	LatencyDriftMS int64 `json:"latency_drift_ms"` // avg of last quarter minus avg of first quarter
+
+	MaxHeapBytes  uint64 `json:"max_heap_bytes"`
+	MaxGoroutines int    `json:"max_goroutines"`
+}
+
+// soakTunnel keeps the tunnel up for the duration of the soak and tracks
+// the public URL and disconnects across reconnects.
+type soakTunnel struct {
+	mu          sync.Mutex
+	url         string
+	disconnects []time.Time
+	fatal       error
+}
+
+func (s *soakTunnel) setURL(url string) {
+	s.mu.Lock()
+	s.url = url
+	s.mu.Unlock()
+}
+
+func (s *soakTunnel) getURL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.url
+}
+
+func runSoak(_ *cobra.Command, _ []string) error {
+	if err := utils.InitLogger(verbose); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer utils.Sync()
+	logger := utils.GetLogger()
+
+	// The probe target: a loopback HTTP endpoint this process controls, so
+	// the soak measures the tunnel and not some unrelated local service.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start probe endpoint: %w", err)
+	}
+	defer listener.Close()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "drip soak ok")
+	})
+	go func() { _ = http.Serve(listener, mux) }()
+	localPort := listener.Addr().(*net.TCPAddr).Port
+
+	serverAddr, token, err := resolveServerAddrAndToken("soak", localPort)
+	if err != nil {
+		return err
+	}
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr: serverAddr,
+		Token:      token,
+		TunnelType: protocol.TunnelTypeHTTP,
+		LocalHost:  "127.0.0.1",
+		LocalPort:  localPort,
+		Subdomain:  subdomain,
+		Insecure:   insecure,
+	}
+
+	st := &soakTunnel{}
+	stop := make(chan struct{})
+	workerDone := make(chan struct{})
+	go soakWorker(connConfig, logger, st, stop, workerDone)
+
+	// Wait for the first URL before probing.
+	for i := 0; i < 100 && st.getURL() == ""; i++ {
+		st.mu.Lock()
+		fatal := st.fatal
+		st.mu.Unlock()
+		if fatal != nil {
+			return fatal
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	if st.getURL() == "" {
+		close(stop)
+		return fmt.Errorf("tunnel did not come up")
+	}
+
+	result := &soakResult{
+		Server:  serverAddr,
+		URL:     st.getURL(),
+		Started: time.Now(),
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Success(fmt.Sprintf("Soak started: %s for %s, probing every %s",
+		ui.URL(result.URL), FormatDuration(soakDuration), soakInterval)))
+	fmt.Println(ui.Muted("Ctrl+C ends the soak early and still writes the report"))
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	probeTicker := time.NewTicker(soakInterval)
+	defer probeTicker.Stop()
+	deadline := time.After(soakDuration)
+
+loop:
+	for {
+		select {
+		case <-probeTicker.C:
+			probe := runSoakProbe(client, st.getURL())
+			result.Probes = append(result.Probes, probe)
+			if probe.Error != "" {
+				result.ProbeFailures++
+				fmt.Println(ui.Warning(fmt.Sprintf("Probe failed: %s", probe.Error)))
+			}
+		case <-deadline:
+			break loop
+		case <-quit:
+			fmt.Println()
+			fmt.Println(ui.Muted("Soak interrupted, writing report..."))
+			break loop
+		}
+	}
+
+	close(stop)
+	select {
+	case <-workerDone:
+	case <-time.After(5 * time.Second):
+	}
+
+	result.Finished = time.Now()
+	st.mu.Lock()
+	result.Disconnects = len(st.disconnects)
+	result.DisconnectTimes = st.disconnects
+	st.mu.Unlock()
+	summarizeSoak(result)
+
+	path, err := writeSoakReport(result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Info(
+		"Soak Report",
+		"",
+		ui.Muted("Duration     ")+FormatDuration(result.Finished.Sub(result.Started)),
+		ui.Muted("Probes       ")+fmt.Sprintf("%d (%d failed)", len(result.Probes), result.ProbeFailures),
+		ui.Muted("Disconnects  ")+fmt.Sprintf("%d", result.Disconnects),
+		ui.Muted("Latency      ")+fmt.Sprintf("min %dms  avg %dms  max %dms  drift %+dms",
+			result.LatencyMinMS, result.LatencyAvgMS, result.LatencyMaxMS, result.LatencyDriftMS),
+		ui.Muted("Memory       ")+fmt.Sprintf("peak heap %s, peak goroutines %d",
+			ui.FormatBytes(int64(result.MaxHeapBytes)), result.MaxGoroutines),
+		"",
+		ui.Muted("Report: ")+path,
+	))
+	return nil
+}
+
+// soakWorker keeps the tunnel connected, recording each drop.
+func soakWorker(connConfig *tcp.ConnectorConfig, logger *zap.Logger, st *soakTunnel, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		connector := tcp.NewTunnelClient(connConfig, logger)
+		if err := connector.Connect(); err != nil {
+			if isNonRetryableError(err) {
+				st.mu.Lock()
+				st.fatal = fmt.Errorf("failed to connect: %w", err)
+				st.mu.Unlock()
+				return
+			}
+			select {
+			case <-stop:
+				return
+			case <-time.After(reconnectInterval):
+				continue
+			}
+		}
+
+		st.setURL(connector.GetURL())
+
+		disconnected := make(chan struct{})
+		go func() {
+			connector.Wait()
+			close(disconnected)
+		}()
+
+		select {
+		case <-stop:
+			connector.Close()
+			return
+		case <-disconnected:
+			st.mu.Lock()
+			st.disconnects = append(st.disconnects, time.Now())
+			st.mu.Unlock()
+			select {
+			case <-stop:
+				return
+			case <-time.After(reconnectInterval):
+			}
+		}
+	}
+}
+
+// runSoakProbe fetches the public URL once and samples process health.
+func runSoakProbe(client *http.Client, url string) soakProbe {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	probe := soakProbe{
+		Time:       time.Now(),
+		HeapBytes:  mem.HeapAlloc,
+		Goroutines: runtime.NumGoroutine(),
+	}
+
+	start := time.Now()
+	resp, err := client.Get(url)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		probe.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+		return probe
+	}
+	probe.LatencyMS = time.Since(start).Milliseconds()
+	return probe
+}
+
+// summarizeSoak fills in the aggregate latency and memory fields.
+func summarizeSoak(r *soakResult) {
+	var ok []int64
+	for _, p := range r.Probes {
+		if p.HeapBytes > r.MaxHeapBytes {
+			r.MaxHeapBytes = p.HeapBytes
+		}
+		if p.Goroutines > r.MaxGoroutines {
+			r.MaxGoroutines = p.Goroutines
+		}
+		if p.Error == "" {
+			ok = append(ok, p.LatencyMS)
+		}
+	}
+	if len(ok) == 0 {
+		return
+	}
+
+	r.LatencyMinMS, r.LatencyMaxMS = ok[0], ok[0]
+	var sum int64
+	for _, ms := range ok {
+		if ms < r.LatencyMinMS {
+			r.LatencyMinMS = ms
+		}
+		if ms > r.LatencyMaxMS {
+			r.LatencyMaxMS = ms
+		}
+		sum += ms
+	}
+	r.LatencyAvgMS = sum / int64(len(ok))
+
+	// Drift: how much slower the last quarter of the run was than the first.
+	if quarter := len(ok) / 4; quarter > 0 {
+		var first, last int64
+		for _, ms := range ok[:quarter] {
+			first += ms
+		}
+		for _, ms := range ok[len(ok)-quarter:] {
+			last += ms
+		}
+		r.LatencyDriftMS = last/int64(quarter) - first/int64(quarter)
+	}
+}
+
+// writeSoakReport writes the report JSON and returns its path.
+func writeSoakReport(r *soakResult) (string, error) {
+	path := soakReport
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		dir := filepath.Join(home, ".drip", "soak")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create report directory: %w", err)
+		}
+		path = filepath.Join(dir, fmt.Sprintf("soak-%s.json", r.Started.Format("20060102-150405")))
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	socksUser string
+	socksPass string
+)
+
+var socksCmd = &cobra.Command{
+	Use:   "socks",
+	Short: "Start SOCKS5 proxy tunnel",
+	Long: `Expose a public SOCKS5 proxy that tunnels into your local network.
+
+Example:
+  drip socks                        Proxy with a generated password
+  drip socks --user me --pass s3cr3t Use your own credentials
+
+Point a browser or curl at the printed socks5:// endpoint and traffic is
+relayed through this machine, so internal hosts (staging environments,
+private services) resolve and connect as if you were browsing locally.
+
+The server enforces username/password authentication before any traffic
+reaches your network; without --pass a random password is generated and
+printed once.
+
+Configuration:
+  First time: Run 'drip config init' to save server and token
+  Subsequent: Just run 'drip socks'`,
+	Args: cobra.NoArgs,
+	RunE: runSOCKS,
+}
+
+func init() {
+	socksCmd.Flags().BoolVarP(&daemonMode, "daemon", "d", false, "Run in background (daemon mode)")
+	socksCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	socksCmd.Flags().StringVar(&socksUser, "user", "drip", "SOCKS5 username visitors must present")
+	socksCmd.Flags().StringVar(&socksPass, "pass", "", "SOCKS5 password (default: generated and printed)")
+	socksCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum simultaneous proxied connections (0 = unlimited)")
+	socksCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	socksCmd.Flags().IntVar(&remotePort, "remote-port", 0, "Fixed public port, pinned to your token across restarts (0 = random)")
+	socksCmd.Flags().BoolVar(&daemonMarker, "daemon-child", false, "Internal flag for daemon child process")
+	socksCmd.Flags().MarkHidden("daemon-child")
+	rootCmd.AddCommand(socksCmd)
+}
+
+func runSOCKS(_ *cobra.Command, args []string) error {
+	if !daemonMarker {
+		if err := CheckExistingDaemon("socks", 0); err != nil {
+			return err
+		}
+	}
+
+	generated := false
+	if socksPass == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		socksPass = hex.EncodeToString(b)
+		generated = true
+	}
+
+	if daemonMode && !daemonMarker {
+		return StartDaemon("socks", 0, buildDaemonArgs("socks", args, subdomain, "127.0.0.1"))
+	}
+
+	serverAddr, token, err := resolveServerAddrAndToken("socks", 0)
+	if err != nil {
+		return err
+	}
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr:    serverAddr,
+		Token:         token,
+		TunnelType:    protocol.TunnelTypeSOCKS,
+		Subdomain:     subdomain,
+		Insecure:      insecure,
+		MaxConcurrent: maxConcurrent,
+		Transport:     transportMode,
+		RemotePort:    remotePort,
+		SocksUser:     socksUser,
+		SocksPass:     socksPass,
+	}
+
+	if generated {
+		fmt.Println(ui.Success(fmt.Sprintf("SOCKS5 credentials: %s / %s", socksUser, socksPass)))
+	}
+
+	var daemon *DaemonInfo
+	if daemonMarker {
+		daemon = newDaemonInfo("socks", 0, subdomain, serverAddr)
+	}
+
+	return runTunnelWithUI(connConfig, daemon)
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"drip/internal/shared/ui"
+	json "github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+)
+
+var statsAllTime bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [type] [port]",
+	Short: "Show cumulative tunnel statistics",
+	Long: `Show cumulative per-tunnel statistics persisted across restarts.
+
+By default only tunnels that are currently running are shown; use
+--all-time to include every tunnel that has ever run on this machine.
+
+With a type and port, live counters are read from the running background
+tunnel over its control socket.
+
+Example:
+  drip stats             Stats for running tunnels
+  drip stats --all-time  Lifetime stats for all known tunnels
+  drip stats http 3000   Live stats of one background tunnel`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runStatsCmd,
+}
+
+var (
+	statsExportFormat string
+	statsExportSince  time.Duration
+)
+
+var statsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tunnel statistics as CSV or JSON",
+	Long: `Export persisted per-tunnel statistics to stdout.
+
+Example:
+  drip stats export --format csv             Export everything as CSV
+  drip stats export --format json            Export everything as JSON
+  drip stats export --format csv --since 168h  Only tunnels active in the last 7 days`,
+	RunE: runStatsExport,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsAllTime, "all-time", false, "Include tunnels that are not currently running")
+	statsExportCmd.Flags().StringVar(&statsExportFormat, "format", "csv", "Output format: csv or json")
+	statsExportCmd.Flags().DurationVar(&statsExportSince, "since", 0, "Only include tunnels active within this duration (e.g. 168h)")
+	statsCmd.AddCommand(statsExportCmd)
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStatsCmd(_ *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		return fmt.Errorf("expected both a type and a port, e.g. 'drip stats http 3000'")
+	}
+	if len(args) == 2 {
+		return runDaemonStats(args[0], args[1])
+	}
+
+	records, err := ListAllLifetimeStats()
+	if err != nil {
+		return fmt.Errorf("failed to load stats: %w", err)
+	}
+
+	if !statsAllTime {
+		var running []*LifetimeStats
+		for _, ls := range records {
+			info, err := LoadDaemonInfo(ls.Type, ls.Port)
+			if err == nil && info != nil && IsProcessRunning(info.PID) {
+				running = append(running, ls)
+			}
+		}
+		records = running
+	}
+
+	if len(records) == 0 {
+		fmt.Println()
+		fmt.Println(ui.Info(
+			"No Tunnel Statistics",
+			"",
+			ui.Muted("Stats are recorded as tunnels run. Try:"),
+			"",
+			ui.Cyan("  drip stats --all-time"),
+		))
+		return nil
+	}
+
+	title := "Tunnel Statistics"
+	if statsAllTime {
+		title = "Tunnel Statistics (all time)"
+	}
+
+	table := ui.NewTable([]string{"TYPE", "PORT", "REQUESTS", "IN", "OUT", "UPTIME", "LAST ACTIVE"}).
+		WithTitle(title)
+
+	for _, ls := range records {
+		lastActive := ui.Muted("-")
+		if !ls.LastUpdated.IsZero() {
+			lastActive = FormatDuration(time.Since(ls.LastUpdated)) + " ago"
+		}
+
+		table.AddRow([]string{
+			ui.Highlight(ls.Type),
+			fmt.Sprintf("%d", ls.Port),
+			fmt.Sprintf("%d", ls.TotalRequests),
+			ui.FormatBytes(ls.TotalBytesIn),
+			ui.FormatBytes(ls.TotalBytesOut),
+			FormatDuration(ls.TotalUptime),
+			lastActive,
+		})
+	}
+
+	fmt.Print(table.Render())
+	return nil
+}
+
+// runDaemonStats shows live counters of one background tunnel, read from
+// the daemon over its control socket.
+func runDaemonStats(tunnelType, portArg string) error {
+	if !isExactType(tunnelType) {
+		return fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', 'tcp', or 'udp')", tunnelType)
+	}
+	port, err := strconv.Atoi(portArg)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", portArg)
+	}
+
+	info, err := LoadDaemonInfo(tunnelType, port)
+	if err != nil {
+		return err
+	}
+	if info == nil || !IsProcessRunning(info.PID) {
+		return fmt.Errorf("no running %s tunnel for port %d (see 'drip list')", tunnelType, port)
+	}
+
+	ds, err := queryDaemonStats(tunnelType, port)
+	if err != nil {
+		return fmt.Errorf("failed to query daemon: %w", err)
+	}
+
+	lines := []string{
+		ui.Muted("URL        ") + ui.URL(ds.URL),
+		ui.Muted("Uptime     ") + FormatDuration(time.Duration(ds.UptimeSeconds)*time.Second),
+		ui.Muted("Requests   ") + fmt.Sprintf("%d", ds.TotalRequests),
+		ui.Muted("Traffic    ") + fmt.Sprintf("↓ %s  ↑ %s", ui.FormatBytes(ds.BytesIn), ui.FormatBytes(ds.BytesOut)),
+		ui.Muted("Speed      ") + fmt.Sprintf("↓ %s/s  ↑ %s/s", ui.FormatBytes(ds.SpeedIn), ui.FormatBytes(ds.SpeedOut)),
+		ui.Muted("PID        ") + fmt.Sprintf("%d", ds.PID),
+	}
+	if ds.ActiveConns > 0 {
+		lines = append(lines, ui.Muted("Active     ")+fmt.Sprintf("%d connections", ds.ActiveConns))
+	}
+	if ds.Reconnects > 0 {
+		lines = append(lines, ui.Muted("Drops      ")+fmt.Sprintf("%d (%s down)", ds.Reconnects, FormatDuration(time.Duration(ds.DowntimeSeconds)*time.Second)))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Info(fmt.Sprintf("%s tunnel on port %d", strings.ToUpper(ds.Type), ds.Port), append([]string{""}, lines...)...))
+	return nil
+}
+
+func runStatsExport(_ *cobra.Command, _ []string) error {
+	if statsExportFormat != "csv" && statsExportFormat != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'csv' or 'json')", statsExportFormat)
+	}
+
+	records, err := ListAllLifetimeStats()
+	if err != nil {
+		return fmt.Errorf("failed to load stats: %w", err)
+	}
+
+	if statsExportSince > 0 {
+		cutoff := time.Now().Add(-statsExportSince)
+		var recent []*LifetimeStats
+		for _, ls := range records {
+			if ls.LastUpdated.After(cutoff) {
+				recent = append(recent, ls)
+			}
+		}
+		records = recent
+	}
+
+	if statsExportFormat == "json" {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode stats: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write([]string{"type", "port", "total_requests", "total_bytes_in", "total_bytes_out", "total_uptime_seconds", "first_started", "last_updated"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, ls := range records {
+		row := []string{
+			ls.Type,
+			strconv.Itoa(ls.Port),
+			strconv.FormatInt(ls.TotalRequests, 10),
+			strconv.FormatInt(ls.TotalBytesIn, 10),
+			strconv.FormatInt(ls.TotalBytesOut, 10),
+			strconv.FormatInt(int64(ls.TotalUptime.Seconds()), 10),
+			ls.FirstStarted.Format(time.RFC3339),
+			ls.LastUpdated.Format(time.RFC3339),
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -2,52 +2,98 @@ package cli
 
 import (
 	"fmt"
+	"path"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	stopOlderThan time.Duration
+	stopSubdomain string
+)
+
 var stopCmd = &cobra.Command{
-	Use:   "stop <type> <port>|all",
+	Use:   "stop [<type> <port>|all]",
 	Short: "Stop background tunnels",
-	Long: `Stop one or all background tunnels.
+	Long: `Stop one or more background tunnels.
+
+Type and port accept glob patterns, and tunnels can also be selected by
+age or subdomain.
 
 Examples:
-  drip stop http 3000    Stop HTTP tunnel on port 3000
-  drip stop https 8443   Stop HTTPS tunnel on port 8443
-  drip stop tcp 5432     Stop TCP tunnel on port 5432
-  drip stop all          Stop all running tunnels
+  drip stop http 3000          Stop HTTP tunnel on port 3000
+  drip stop https 8443         Stop HTTPS tunnel on port 8443
+  drip stop tcp 5432           Stop TCP tunnel on port 5432
+  drip stop http '*'           Stop all HTTP tunnels
+  drip stop --older-than 12h   Stop tunnels running longer than 12 hours
+  drip stop --subdomain myapp  Stop the tunnel using subdomain myapp
+  drip stop all                Stop all running tunnels
 
 Use 'drip list' to see running tunnels.`,
 	Aliases: []string{"kill"},
-	Args:    cobra.MinimumNArgs(1),
+	Args:    cobra.MaximumNArgs(2),
 	RunE:    runStop,
 }
 
 func init() {
+	stopCmd.Flags().DurationVar(&stopOlderThan, "older-than", 0, "Only stop tunnels running longer than this duration (e.g. 12h)")
+	stopCmd.Flags().StringVar(&stopSubdomain, "subdomain", "", "Only stop tunnels using this subdomain")
 	rootCmd.AddCommand(stopCmd)
 }
 
 func runStop(_ *cobra.Command, args []string) error {
-	if args[0] == "all" {
+	if len(args) > 0 && args[0] == "all" {
 		return stopAllDaemons()
 	}
 
-	if len(args) < 2 {
-		return fmt.Errorf("usage: drip stop <type> <port> or drip stop all")
+	if len(args) == 0 && stopOlderThan == 0 && stopSubdomain == "" {
+		return fmt.Errorf("usage: drip stop <type> <port>, drip stop all, or a filter flag (--older-than, --subdomain)")
 	}
 
-	tunnelType := args[0]
-	if tunnelType != "http" && tunnelType != "https" && tunnelType != "tcp" {
-		return fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', or 'tcp')", tunnelType)
+	typePattern := "*"
+	portPattern := "*"
+	if len(args) > 0 {
+		typePattern = args[0]
+	}
+	if len(args) > 1 {
+		portPattern = args[1]
 	}
 
-	port, err := strconv.Atoi(args[1])
-	if err != nil || port < 1 || port > 65535 {
-		return fmt.Errorf("invalid port number: %s", args[1])
+	if !isTypePattern(typePattern) {
+		return fmt.Errorf("invalid tunnel type: %s (must be 'http', 'https', 'tcp', 'udp', or a pattern)", typePattern)
+	}
+
+	// Exact type+port without filters keeps the precise single-tunnel errors.
+	if isExactType(typePattern) && stopOlderThan == 0 && stopSubdomain == "" {
+		if port, err := strconv.Atoi(portPattern); err == nil {
+			if port < 1 || port > 65535 {
+				return fmt.Errorf("invalid port number: %s", portPattern)
+			}
+			return stopDaemon(typePattern, port)
+		}
 	}
 
-	return stopDaemon(tunnelType, port)
+	return stopMatching(typePattern, portPattern)
+}
+
+func isExactType(t string) bool {
+	return t == "http" || t == "https" || t == "tcp" || t == "udp"
+}
+
+// isTypePattern reports whether t is an exact tunnel type or a glob pattern
+// that could match one.
+func isTypePattern(t string) bool {
+	if isExactType(t) {
+		return true
+	}
+	for _, known := range []string{"http", "https", "tcp", "udp"} {
+		if ok, _ := path.Match(t, known); ok {
+			return true
+		}
+	}
+	return false
 }
 
 func stopDaemon(tunnelType string, port int) error {
@@ -65,7 +111,7 @@ func stopDaemon(tunnelType string, port int) error {
 		return fmt.Errorf("tunnel was not running (cleaned up stale entry)")
 	}
 
-	if err := KillProcess(info.PID); err != nil {
+	if err := terminateDaemon(info); err != nil {
 		return fmt.Errorf("failed to stop tunnel: %w", err)
 	}
 
@@ -75,6 +121,65 @@ func stopDaemon(tunnelType string, port int) error {
 	return nil
 }
 
+// terminateDaemon stops a daemon gracefully over its control socket, falling
+// back to killing the process for daemons without one.
+func terminateDaemon(info *DaemonInfo) error {
+	if err := requestDaemonStop(info.Type, info.Port); err == nil {
+		if waitProcessExit(info.PID, 5*time.Second) {
+			return nil
+		}
+	}
+	return KillProcess(info.PID)
+}
+
+// waitProcessExit polls until the process is gone or the timeout elapses.
+func waitProcessExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !IsProcessRunning(pid) {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
+
+// stopMatching stops every registered daemon matching the type and port
+// patterns plus any --older-than / --subdomain filters.
+func stopMatching(typePattern, portPattern string) error {
+	CleanupStaleDaemons()
+
+	daemons, err := ListAllDaemons()
+	if err != nil {
+		return fmt.Errorf("failed to list daemons: %w", err)
+	}
+
+	var matched []*DaemonInfo
+	for _, d := range daemons {
+		if ok, _ := path.Match(typePattern, d.Type); !ok {
+			continue
+		}
+		if ok, _ := path.Match(portPattern, strconv.Itoa(d.Port)); !ok {
+			continue
+		}
+		if stopOlderThan > 0 && time.Since(d.StartTime) < stopOlderThan {
+			continue
+		}
+		if stopSubdomain != "" && d.Subdomain != stopSubdomain {
+			continue
+		}
+		matched = append(matched, d)
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("\033[90mNo running tunnels matched.\033[0m")
+		return nil
+	}
+
+	stopDaemonList(matched)
+	return nil
+}
+
 func stopAllDaemons() error {
 	CleanupStaleDaemons()
 
@@ -88,6 +193,11 @@ func stopAllDaemons() error {
 		return nil
 	}
 
+	stopDaemonList(daemons)
+	return nil
+}
+
+func stopDaemonList(daemons []*DaemonInfo) {
 	stopped := 0
 	failed := 0
 
@@ -97,7 +207,7 @@ func stopAllDaemons() error {
 			continue
 		}
 
-		if err := KillProcess(d.PID); err != nil {
+		if err := terminateDaemon(d); err != nil {
 			fmt.Printf("\033[31m✗\033[0m Failed to stop %s tunnel on port %d: %v\n", d.Type, d.Port, err)
 			failed++
 			continue
@@ -114,6 +224,4 @@ func stopAllDaemons() error {
 	} else {
 		fmt.Printf("Stopped %d tunnel(s)\n", stopped)
 	}
-
-	return nil
 }
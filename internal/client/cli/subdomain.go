@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"drip/internal/shared/ui"
+	"drip/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var subdomainCmd = &cobra.Command{
+	Use:   "subdomain",
+	Short: "Subdomain utilities",
+}
+
+var subdomainCheckCmd = &cobra.Command{
+	Use:   "check <name>",
+	Short: "Check whether a subdomain is free on the server",
+	Long: `Ask the server whether a subdomain can be registered, before
+starting a tunnel and hitting a mid-flow rejection.
+
+Example:
+  drip subdomain check myapp`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSubdomainCheck,
+}
+
+var reserveTTL string
+
+var subdomainReserveCmd = &cobra.Command{
+	Use:   "reserve <name>",
+	Short: "Claim a subdomain for future use",
+	Long: `Reserve a subdomain on the server without starting a tunnel, so the
+URL can be handed out before the tunnel exists. The claim is tied to your
+auth token and lapses after the TTL unless renewed.
+
+Examples:
+  drip subdomain reserve myapp --ttl 30d
+  drip subdomain reserve demo --ttl 48h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSubdomainReserve,
+}
+
+var subdomainReleaseCmd = &cobra.Command{
+	Use:   "release <name>",
+	Short: "Release a reserved subdomain",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSubdomainRelease,
+}
+
+func init() {
+	subdomainReserveCmd.Flags().StringVar(&reserveTTL, "ttl", "30d", "How long to hold the claim (e.g. 48h, 30d)")
+
+	subdomainCmd.AddCommand(subdomainCheckCmd)
+	subdomainCmd.AddCommand(subdomainReserveCmd)
+	subdomainCmd.AddCommand(subdomainReleaseCmd)
+	rootCmd.AddCommand(subdomainCmd)
+}
+
+func runSubdomainCheck(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	serverAddr, token, err := resolveSubdomainServer()
+	if err != nil {
+		return err
+	}
+
+	status, err := querySubdomainStatus(serverAddr, token, name)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case "available":
+		fmt.Println(ui.Success(fmt.Sprintf("%s is available", name)))
+	case "invalid":
+		fmt.Println(ui.Error(fmt.Sprintf("%s is not a valid subdomain", name)))
+		return fmt.Errorf("subdomain unavailable")
+	case "reserved":
+		fmt.Println(ui.Warning(fmt.Sprintf("%s is reserved", name)))
+		return fmt.Errorf("subdomain unavailable")
+	case "taken":
+		fmt.Println(ui.Warning(fmt.Sprintf("%s is taken by an active tunnel", name)))
+		return fmt.Errorf("subdomain unavailable")
+	case "alias":
+		fmt.Println(ui.Warning(fmt.Sprintf("%s is claimed as an alias", name)))
+		return fmt.Errorf("subdomain unavailable")
+	case "claimed":
+		fmt.Println(ui.Warning(fmt.Sprintf("%s is reserved by another token", name)))
+		return fmt.Errorf("subdomain unavailable")
+	default:
+		fmt.Println(ui.Warning(fmt.Sprintf("%s: %s", name, status)))
+	}
+	return nil
+}
+
+func runSubdomainReserve(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	ttl, err := parseTTL(reserveTTL)
+	if err != nil {
+		return err
+	}
+
+	serverAddr, token, err := resolveSubdomainServer()
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("ttl", ttl.String())
+
+	var result struct {
+		Name      string `json:"name"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	if err := callSubdomainEndpoint(serverAddr, token, "/subdomain/reserve", params, &result); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("%s reserved until %s", result.Name, result.ExpiresAt)))
+	return nil
+}
+
+func runSubdomainRelease(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	serverAddr, token, err := resolveSubdomainServer()
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("name", name)
+
+	var result struct {
+		Name     string `json:"name"`
+		Released bool   `json:"released"`
+	}
+	if err := callSubdomainEndpoint(serverAddr, token, "/subdomain/release", params, &result); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("%s reservation released", result.Name)))
+	return nil
+}
+
+// parseTTL parses a duration string, additionally accepting a "d" suffix for
+// days (e.g. "30d") since time.ParseDuration stops at hours.
+func parseTTL(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid ttl %q (expected e.g. 48h or 30d)", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid ttl %q (expected e.g. 48h or 30d)", s)
+	}
+	return d, nil
+}
+
+// resolveSubdomainServer resolves the server address and token from flags,
+// falling back to the client config file.
+func resolveSubdomainServer() (string, string, error) {
+	serverAddr := serverURL
+	token := authToken
+	if serverAddr == "" || token == "" {
+		if cfg, cfgErr := config.LoadClientConfig(""); cfgErr == nil {
+			if serverAddr == "" {
+				serverAddr = cfg.Server
+			}
+			if token == "" {
+				token = cfg.Token
+			}
+		}
+	}
+	if serverAddr == "" {
+		return "", "", fmt.Errorf("server address unknown, pass --server or run 'drip config init'")
+	}
+	return serverAddr, token, nil
+}
+
+// callSubdomainEndpoint issues an authenticated request against one of the
+// server's /subdomain endpoints and decodes the JSON response into result.
+func callSubdomainEndpoint(serverAddr, token, path string, params url.Values, result interface{}) error {
+	tlsConfig := config.GetClientTLSConfigInsecure()
+	if !insecure {
+		host, _, _ := net.SplitHostPort(serverAddr)
+		tlsConfig = config.GetClientTLSConfig(host)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("https://%s%s?%s", serverAddr, path, params.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("server rejected request: %s", strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// querySubdomainStatus calls the server's /subdomain endpoint over the
+// public TLS port (the listener multiplexes HTTP on the same port).
+func querySubdomainStatus(serverAddr, token, name string) (string, error) {
+	tlsConfig := config.GetClientTLSConfigInsecure()
+	if !insecure {
+		host, _, _ := net.SplitHostPort(serverAddr)
+		tlsConfig = config.GetClientTLSConfig(host)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	params := url.Values{}
+	params.Set("name", name)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/subdomain?%s", serverAddr, params.Encode()), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("server rejected subdomain check: %s", strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse server response: %w", err)
+	}
+	return result.Status, nil
+}
@@ -1,17 +1,30 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"strconv"
+	"strings"
+	"time"
 
+	"drip/internal/client/dbproto"
+	"drip/internal/client/grpcprobe"
 	"drip/internal/client/tcp"
 	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	probeGRPC  bool
+	observeDB  bool
+	dbReadOnly bool
+)
+
 var tcpCmd = &cobra.Command{
-	Use:   "tcp <port>",
+	Use:   "tcp <port>[,<port>...]",
 	Short: "Start TCP tunnel",
 	Long: `Start a TCP tunnel to expose any TCP service.
 
@@ -20,6 +33,10 @@ Example:
   drip tcp 3306                     Tunnel MySQL
   drip tcp 22                       Tunnel SSH
   drip tcp 6379 --subdomain myredis Tunnel Redis with custom subdomain
+  drip tcp 5432,6379,27017          Tunnel several services at once
+
+Multiple comma-separated ports share one control connection; each port
+gets its own public port on the server.
 
 Supported Services:
   - Databases: PostgreSQL (5432), MySQL (3306), Redis (6379), MongoDB (27017)
@@ -39,15 +56,41 @@ func init() {
 	tcpCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
 	tcpCmd.Flags().BoolVarP(&daemonMode, "daemon", "d", false, "Run in background (daemon mode)")
 	tcpCmd.Flags().StringVarP(&localAddress, "address", "a", "127.0.0.1", "Local address to forward to (default: 127.0.0.1)")
+	tcpCmd.Flags().StringVar(&alertBytes, "alert-bytes", "", "Warn when cumulative traffic exceeds this limit (e.g. 5GB)")
+	tcpCmd.Flags().BoolVar(&alertStop, "stop-at-limit", false, "Stop the tunnel instead of warning when --alert-bytes is reached")
+	tcpCmd.Flags().BoolVar(&probeGRPC, "grpc", false, "Probe gRPC reflection and label the tunnel with exposed services")
+	tcpCmd.Flags().BoolVar(&observeDB, "observe-db", false, "Log database connection attempts, usernames, and command rates (Postgres/MySQL/Redis)")
+	tcpCmd.Flags().BoolVar(&dbReadOnly, "read-only", false, "Block write statements/commands at the protocol level (Postgres/MySQL/Redis)")
+	tcpCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum simultaneous connections forwarded to the local service (0 = unlimited)")
+	tcpCmd.Flags().DurationVar(&dialRetry, "dial-retry", 0, "Retry refused local dials for this window before failing (smooths dev-server restarts, e.g. 5s)")
+	tcpCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	tcpCmd.Flags().IntVar(&remotePort, "remote-port", 0, "Fixed public port, pinned to your token across restarts (0 = random)")
+	tcpCmd.Flags().StringVarP(&outputMode, "output", "o", "", "Output mode: json (machine-readable) or none")
+	tcpCmd.Flags().StringVar(&urlFile, "url-file", "", "Write the assigned public URL to this file")
 	tcpCmd.Flags().BoolVar(&daemonMarker, "daemon-child", false, "Internal flag for daemon child process")
 	tcpCmd.Flags().MarkHidden("daemon-child")
 	rootCmd.AddCommand(tcpCmd)
 }
 
 func runTCP(_ *cobra.Command, args []string) error {
-	port, err := strconv.Atoi(args[0])
-	if err != nil || port < 1 || port > 65535 {
-		return fmt.Errorf("invalid port number: %s", args[0])
+	ports, err := parsePortList(args[0])
+	if err != nil {
+		return err
+	}
+	port := ports[0]
+
+	var extras []protocol.ExtraTunnel
+	for _, p := range ports[1:] {
+		extras = append(extras, protocol.ExtraTunnel{LocalPort: p})
+	}
+	if len(extras) > 0 && remotePort != 0 {
+		return fmt.Errorf("--remote-port cannot be combined with multiple ports")
+	}
+
+	if !daemonMarker {
+		if err := CheckExistingDaemon("tcp", port); err != nil {
+			return err
+		}
 	}
 
 	if daemonMode && !daemonMarker {
@@ -59,19 +102,56 @@ func runTCP(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	var dbProtocol string
+	if observeDB || dbReadOnly {
+		flag := "--observe-db"
+		if dbReadOnly {
+			flag = "--read-only"
+		}
+		dbProtocol = dbproto.Detect(port)
+		if dbProtocol == "" {
+			return fmt.Errorf("%s requires a well-known database port (5432, 3306, or 6379), got %d", flag, port)
+		}
+	}
+
+	var services []string
+	if probeGRPC {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		services, err = grpcprobe.ListServices(ctx, net.JoinHostPort(localAddress, strconv.Itoa(port)))
+		cancel()
+		if err != nil {
+			fmt.Println(ui.Warning(fmt.Sprintf("gRPC probe failed: %v", err)))
+		} else {
+			fmt.Println(ui.Success("gRPC services: " + strings.Join(services, ", ")))
+		}
+	}
+
 	connConfig := &tcp.ConnectorConfig{
-		ServerAddr: serverAddr,
-		Token:      token,
-		TunnelType: protocol.TunnelTypeTCP,
-		LocalHost:  localAddress,
-		LocalPort:  port,
-		Subdomain:  subdomain,
-		Insecure:   insecure,
+		ServerAddr:    serverAddr,
+		Token:         token,
+		TunnelType:    protocol.TunnelTypeTCP,
+		LocalHost:     localAddress,
+		LocalPort:     port,
+		Subdomain:     subdomain,
+		Insecure:      insecure,
+		Services:      services,
+		DBProtocol:    dbProtocol,
+		DBReadOnly:    dbReadOnly,
+		MaxConcurrent: maxConcurrent,
+		DialRetry:     dialRetry,
+		Transport:     transportMode,
+		RemotePort:    remotePort,
+		ExtraTunnels:  extras,
+	}
+
+	if dbReadOnly {
+		fmt.Println(ui.Warning(fmt.Sprintf("Read-only guard enabled (%s): write commands will be blocked", dbProtocol)))
 	}
 
 	var daemon *DaemonInfo
 	if daemonMarker {
 		daemon = newDaemonInfo("tcp", port, subdomain, serverAddr)
+		daemon.Services = services
 	}
 
 	return runTunnelWithUI(connConfig, daemon)
@@ -3,8 +3,14 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"drip/internal/client/tcp"
+	"drip/internal/client/webhook"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
 	"drip/pkg/config"
 )
 
@@ -18,6 +24,12 @@ func buildDaemonArgs(tunnelType string, args []string, subdomain string, localAd
 	if localAddress != "127.0.0.1" {
 		daemonArgs = append(daemonArgs, "--address", localAddress)
 	}
+	for _, backend := range localBackends {
+		daemonArgs = append(daemonArgs, "--backend", backend)
+	}
+	if stickyMode != "" {
+		daemonArgs = append(daemonArgs, "--sticky", stickyMode)
+	}
 	if serverURL != "" {
 		daemonArgs = append(daemonArgs, "--server", serverURL)
 	}
@@ -30,10 +42,273 @@ func buildDaemonArgs(tunnelType string, args []string, subdomain string, localAd
 	if verbose {
 		daemonArgs = append(daemonArgs, "--verbose")
 	}
+	if alertBytes != "" {
+		daemonArgs = append(daemonArgs, "--alert-bytes", alertBytes)
+	}
+	if alertStop {
+		daemonArgs = append(daemonArgs, "--stop-at-limit")
+	}
+	if urlFile != "" {
+		daemonArgs = append(daemonArgs, "--url-file", urlFile)
+	}
+	if probeGRPC && tunnelType == "tcp" {
+		daemonArgs = append(daemonArgs, "--grpc")
+	}
+	if observeDB && tunnelType == "tcp" {
+		daemonArgs = append(daemonArgs, "--observe-db")
+	}
+	if dbReadOnly && tunnelType == "tcp" {
+		daemonArgs = append(daemonArgs, "--read-only")
+	}
+	if tunnelAlias != "" {
+		daemonArgs = append(daemonArgs, "--alias", tunnelAlias)
+	}
+	if customDomain != "" && (tunnelType == "http" || tunnelType == "https") {
+		daemonArgs = append(daemonArgs, "--domain", customDomain)
+	}
+	if webUI && (tunnelType == "http" || tunnelType == "https") {
+		daemonArgs = append(daemonArgs, "--ui")
+	}
+	if inspectLive && (tunnelType == "http" || tunnelType == "https") {
+		daemonArgs = append(daemonArgs, "--inspect")
+	}
+	for _, rule := range redirectRules {
+		daemonArgs = append(daemonArgs, "--redirect", rule)
+	}
+	for _, rule := range overrideRules {
+		daemonArgs = append(daemonArgs, "--override", rule)
+	}
+	for _, also := range alsoTunnels {
+		daemonArgs = append(daemonArgs, "--also", also)
+	}
+	if trailingSlash {
+		daemonArgs = append(daemonArgs, "--trailing-slash")
+	}
+	if webhookProvider != "" {
+		daemonArgs = append(daemonArgs, "--verify-webhook", webhookProvider)
+		if webhookSecret != "" {
+			daemonArgs = append(daemonArgs, "--webhook-secret", webhookSecret)
+		}
+		if webhookReject {
+			daemonArgs = append(daemonArgs, "--reject-invalid")
+		}
+	}
+	if webhookDedupe > 0 {
+		daemonArgs = append(daemonArgs, "--dedupe-webhooks", webhookDedupe.String())
+	}
+	if maxConcurrent > 0 {
+		daemonArgs = append(daemonArgs, "--max-concurrent", strconv.Itoa(maxConcurrent))
+	}
+	if dialRetry > 0 {
+		daemonArgs = append(daemonArgs, "--dial-retry", dialRetry.String())
+	}
+	if unixSocket != "" {
+		daemonArgs = append(daemonArgs, "--unix", unixSocket)
+	}
+	if transportMode != "" {
+		daemonArgs = append(daemonArgs, "--transport", transportMode)
+	}
+	if nameStyle != "" && (tunnelType == "http" || tunnelType == "https") {
+		daemonArgs = append(daemonArgs, "--name-style", nameStyle)
+	}
+	if remotePort > 0 && (tunnelType == "tcp" || tunnelType == "udp" || tunnelType == "socks") {
+		daemonArgs = append(daemonArgs, "--remote-port", strconv.Itoa(remotePort))
+	}
+	if tunnelType == "socks" {
+		daemonArgs = append(daemonArgs, "--user", socksUser, "--pass", socksPass)
+	}
 
 	return daemonArgs
 }
 
+// resolveLocalTarget validates the port/--unix combination for HTTP tunnel
+// commands: exactly one of the two must be given. With --unix the returned
+// port is 0 and only identifies the daemon.
+func resolveLocalTarget(args []string) (int, error) {
+	if unixSocket != "" {
+		if len(args) > 0 {
+			return 0, fmt.Errorf("cannot use both a port argument and --unix")
+		}
+		if len(localBackends) > 0 {
+			return 0, fmt.Errorf("--backend cannot be combined with --unix")
+		}
+		return 0, nil
+	}
+	if len(args) != 1 {
+		return 0, fmt.Errorf("port argument required (or use --unix)")
+	}
+	port, err := strconv.Atoi(args[0])
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port number: %s", args[0])
+	}
+	return port, nil
+}
+
+// parseOverrideRules parses repeated --override values of the form
+// "path->status[ body]". The path may end in "/*" to match a prefix; the
+// optional body follows the status after a space.
+func parseOverrideRules(rules []string) ([]tcp.ResponseOverride, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	parsed := make([]tcp.ResponseOverride, 0, len(rules))
+	for _, rule := range rules {
+		path, rest, ok := strings.Cut(rule, "->")
+		path, rest = strings.TrimSpace(path), strings.TrimSpace(rest)
+		if !ok || path == "" || rest == "" {
+			return nil, fmt.Errorf("invalid override rule %q (expected 'path->status[ body]')", rule)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid override rule %q (path must start with /)", rule)
+		}
+		statusStr, body, _ := strings.Cut(rest, " ")
+		status, err := strconv.Atoi(statusStr)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid override rule %q (status must be 100-599)", rule)
+		}
+
+		ov := tcp.ResponseOverride{
+			Path:        path,
+			Status:      status,
+			Body:        strings.TrimSpace(body),
+			ContentType: "text/plain; charset=utf-8",
+		}
+		if strings.HasSuffix(ov.Path, "/*") {
+			ov.Path = strings.TrimSuffix(ov.Path, "*")
+			ov.Prefix = true
+		}
+		if strings.HasPrefix(ov.Body, "{") || strings.HasPrefix(ov.Body, "[") {
+			ov.ContentType = "application/json"
+		}
+		parsed = append(parsed, ov)
+	}
+	return parsed, nil
+}
+
+// parseRedirectRules parses repeated --redirect values of the form
+// "/old->/new". The source may end in "/*" to match a path prefix.
+func parseRedirectRules(rules []string) ([]protocol.RedirectRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	parsed := make([]protocol.RedirectRule, 0, len(rules))
+	for _, rule := range rules {
+		from, to, ok := strings.Cut(rule, "->")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid redirect rule %q (expected 'from->to')", rule)
+		}
+		if !strings.HasPrefix(from, "/") || !strings.HasPrefix(to, "/") {
+			return nil, fmt.Errorf("invalid redirect rule %q (paths must start with /)", rule)
+		}
+		parsed = append(parsed, protocol.RedirectRule{From: from, To: to})
+	}
+	return parsed, nil
+}
+
+// parsePortList parses a comma-separated port list like "5432,6379,27017".
+// The first port is the primary tunnel; duplicates are rejected.
+func parsePortList(value string) ([]int, error) {
+	parts := strings.Split(value, ",")
+	ports := make([]int, 0, len(parts))
+	seen := make(map[int]bool, len(parts))
+	for _, part := range parts {
+		port, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid port number: %s", part)
+		}
+		if seen[port] {
+			return nil, fmt.Errorf("duplicate port: %d", port)
+		}
+		seen[port] = true
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// parseExtraTunnels parses repeated --also values of the form
+// "subdomain:port" into extra tunnel registrations.
+func parseExtraTunnels(values []string) ([]protocol.ExtraTunnel, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	extras := make([]protocol.ExtraTunnel, 0, len(values))
+	for _, value := range values {
+		sub, portStr, ok := strings.Cut(value, ":")
+		if !ok || sub == "" {
+			return nil, fmt.Errorf("invalid --also value %q (expected 'subdomain:port')", value)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid --also port in %q", value)
+		}
+		extras = append(extras, protocol.ExtraTunnel{Subdomain: sub, LocalPort: port})
+	}
+	return extras, nil
+}
+
+// announceExtraTunnels prints the public URL of each extra subdomain once
+// the primary tunnel is connected.
+func announceExtraTunnels(extras []protocol.ExtraTunnel) {
+	if len(extras) == 0 {
+		return
+	}
+	tunnelReadyHook = func(url string) {
+		rest := url
+		scheme := ""
+		if i := strings.Index(rest, "://"); i != -1 {
+			scheme, rest = rest[:i+3], rest[i+3:]
+		}
+		if i := strings.Index(rest, "."); i != -1 {
+			rest = rest[i:]
+		}
+		for _, extra := range extras {
+			fmt.Println(ui.Success(fmt.Sprintf("Also serving %s -> localhost:%d",
+				ui.URL(scheme+extra.Subdomain+rest), extra.LocalPort)))
+		}
+	}
+}
+
+// validateWebhookFlags resolves the webhook secret (flag or environment)
+// and checks the provider before a tunnel starts.
+func validateWebhookFlags() error {
+	if webhookProvider == "" {
+		return nil
+	}
+	if webhookSecret == "" {
+		webhookSecret = os.Getenv("DRIP_WEBHOOK_SECRET")
+	}
+	_, err := webhook.NewVerifier(webhookProvider, webhookSecret, webhookReject)
+	return err
+}
+
+// parseByteSize parses a human-readable size like "5GB" or "500MB" (binary
+// multiples) or a raw byte count into bytes.
+func parseByteSize(s string) (int64, error) {
+	str := strings.TrimSpace(strings.ToUpper(s))
+
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(str, "TB"):
+		multiplier, str = 1<<40, strings.TrimSuffix(str, "TB")
+	case strings.HasSuffix(str, "GB"):
+		multiplier, str = 1<<30, strings.TrimSuffix(str, "GB")
+	case strings.HasSuffix(str, "MB"):
+		multiplier, str = 1<<20, strings.TrimSuffix(str, "MB")
+	case strings.HasSuffix(str, "KB"):
+		multiplier, str = 1<<10, strings.TrimSuffix(str, "KB")
+	case strings.HasSuffix(str, "B"):
+		str = strings.TrimSuffix(str, "B")
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	return int64(value * float64(multiplier)), nil
+}
+
 func resolveServerAddrAndToken(tunnelType string, port int) (string, string, error) {
 	if serverURL != "" {
 		return serverURL, authToken, nil
@@ -47,11 +322,25 @@ Please run 'drip config init' first, or use flags:
   drip %s %d --server SERVER:PORT --token TOKEN`, tunnelType, port)
 	}
 
-	if cfg.Server == "" {
+	addr := selectServer(cfg)
+	if addr == "" {
 		return "", "", fmt.Errorf("server address is required")
 	}
 
-	return cfg.Server, cfg.Token, nil
+	return addr, cfg.Token, nil
+}
+
+// buildLocalTargets combines the primary local address with any extra
+// backends into the host:port list used by the connector's load balancer.
+// Returns nil when no extra backends are configured.
+func buildLocalTargets(localAddress string, port int, backends []string) []string {
+	if len(backends) == 0 {
+		return nil
+	}
+	targets := make([]string, 0, len(backends)+1)
+	targets = append(targets, fmt.Sprintf("%s:%d", localAddress, port))
+	targets = append(targets, backends...)
+	return targets
 }
 
 func newDaemonInfo(tunnelType string, port int, subdomain string, serverAddr string) *DaemonInfo {
@@ -8,9 +8,13 @@ import (
 	"syscall"
 	"time"
 
+	"drip/internal/client/capture"
+	"drip/internal/client/inspector"
 	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
 	"drip/internal/shared/ui"
 	"drip/internal/shared/utils"
+	"drip/pkg/config"
 	"go.uber.org/zap"
 )
 
@@ -19,7 +23,24 @@ const (
 	reconnectInterval    = 3 * time.Second
 )
 
+// tunnelReadyHook, when set, is called with the public URL after each
+// successful connect. Convenience commands (drip rdp/vnc) use it to emit
+// connection artifacts once the public endpoint is known.
+var tunnelReadyHook func(url string)
+
 func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) error {
+	if err := validateOutputMode(); err != nil {
+		return err
+	}
+	// With --output json/none the styled display is suppressed so scripts
+	// can parse stdout (or just read --url-file).
+	quiet := machineOutput()
+
+	// On an interactive terminal the live view runs on the alternate screen
+	// (full-frame dashboard with a scrolling request log); pipes and daemon
+	// log files keep the plain line output.
+	useDashboard := !quiet && ui.IsTerminal()
+
 	if err := utils.InitLogger(verbose); err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
@@ -27,16 +48,67 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 
 	logger := utils.GetLogger()
 
+	// Daemons answer live queries (drip stats <type> <port>) over a local
+	// control socket for the life of the process.
+	var ctl *controlServer
+	ctlOps := make(chan string, 1)
+	if daemonInfo != nil {
+		if ctl = startControlServer(daemonInfo); ctl != nil {
+			defer ctl.Close()
+			ctl.SetOps(
+				func() {
+					select {
+					case ctlOps <- "stop":
+					default:
+					}
+				},
+				func() {
+					select {
+					case ctlOps <- "reload":
+					default:
+					}
+				},
+			)
+		}
+	}
+
+	var alerts *alerter
+	if cfg, err := config.LoadClientConfig(""); err == nil && cfg.Alerts != nil {
+		alerts = newAlerter(cfg.Alerts, fmt.Sprintf("%s :%d", connConfig.TunnelType, connConfig.LocalPort))
+	}
+
+	var trafficLimit int64
+	if alertBytes != "" {
+		var err error
+		trafficLimit, err = parseByteSize(alertBytes)
+		if err != nil {
+			return fmt.Errorf("invalid --alert-bytes value: %w", err)
+		}
+	}
+	var trafficBase int64 // bytes transferred by previous sessions
+	limitAlerted := false
+	limitStop := make(chan struct{})
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
+	netStop := make(chan struct{})
+	defer close(netStop)
+	netEvents := watchNetworkChanges(netStop)
+
 	reconnectAttempts := 0
+	var downSince time.Time
 	for {
 		connector := tcp.NewTunnelClient(connConfig, logger)
 
-		fmt.Println(ui.RenderConnecting(connConfig.ServerAddr, reconnectAttempts, maxReconnectAttempts))
+		if !quiet {
+			fmt.Println(ui.RenderConnecting(connConfig.ServerAddr, reconnectAttempts, maxReconnectAttempts))
+		}
 
 		if err := connector.Connect(); err != nil {
+			if hint := tcp.DiagnoseConnectError(connConfig.ServerAddr, err); hint != "" && !quiet {
+				fmt.Println(ui.Warning(hint))
+			}
 			if isNonRetryableError(err) {
 				return fmt.Errorf("failed to connect: %w", err)
 			}
@@ -45,12 +117,24 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 			if reconnectAttempts >= maxReconnectAttempts {
 				return fmt.Errorf("failed to connect after %d attempts: %w", maxReconnectAttempts, err)
 			}
-			fmt.Println(ui.RenderConnectionFailed(err))
-			fmt.Println(ui.RenderRetrying(reconnectInterval))
+			if !quiet {
+				fmt.Println(ui.RenderConnectionFailed(err))
+			}
+			if next, ok := nextFailoverServer(); ok {
+				connConfig.ServerAddr = next
+				if !quiet {
+					fmt.Println(ui.Muted(fmt.Sprintf("Failing over to %s", next)))
+				}
+			}
+			if !quiet {
+				fmt.Println(ui.RenderRetrying(reconnectInterval))
+			}
 
 			select {
 			case <-quit:
-				fmt.Println(ui.RenderShuttingDown())
+				if !quiet {
+					fmt.Println(ui.RenderShuttingDown())
+				}
 				return nil
 			case <-time.After(reconnectInterval):
 				continue
@@ -58,6 +142,12 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 		}
 
 		reconnectAttempts = 0
+		if !downSince.IsZero() {
+			if daemonInfo != nil {
+				daemonInfo.Downtime += time.Since(downSince)
+			}
+			downSince = time.Time{}
+		}
 		if assignedSubdomain := connector.GetSubdomain(); assignedSubdomain != "" {
 			connConfig.Subdomain = assignedSubdomain
 			if daemonInfo != nil {
@@ -71,32 +161,151 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 				logger.Warn("Failed to save daemon info", zap.Error(err))
 			}
 		}
+		if ctl != nil {
+			ctl.SetSource(connector.GetStats)
+		}
 
 		displayAddr := connConfig.LocalHost
 		if displayAddr == "127.0.0.1" {
 			displayAddr = "localhost"
 		}
 
+		localAddr := fmt.Sprintf("%s:%d", displayAddr, connConfig.LocalPort)
+		if connConfig.LocalSocket != "" {
+			localAddr = connConfig.LocalSocket
+		}
+
 		status := &ui.TunnelStatus{
 			Type:      string(connConfig.TunnelType),
 			URL:       connector.GetURL(),
-			LocalAddr: fmt.Sprintf("%s:%d", displayAddr, connConfig.LocalPort),
+			LocalAddr: localAddr,
+		}
+
+		var dash *ui.Dashboard
+		if useDashboard {
+			dash = ui.NewDashboard()
+		}
+
+		writeURLFile(connector.GetURL())
+		switch outputMode {
+		case "json":
+			printJSON(tunnelOutput{
+				URL:       connector.GetURL(),
+				Subdomain: connector.GetSubdomain(),
+				Type:      string(connConfig.TunnelType),
+				Local:     localAddr,
+				Server:    connConfig.ServerAddr,
+			})
+		case "none":
+		default:
+			if dash != nil {
+				dash.SetStatus(status)
+				dash.SetState("online")
+				dash.Start()
+			} else {
+				fmt.Print(ui.RenderTunnelConnected(status))
+			}
 		}
 
-		fmt.Print(ui.RenderTunnelConnected(status))
+		if tunnelReadyHook != nil {
+			tunnelReadyHook(connector.GetURL())
+		}
 
 		latencyCh := make(chan time.Duration, 1)
 		connector.SetLatencyCallback(func(latency time.Duration) {
+			if alerts != nil {
+				alerts.ObserveLatency(latency)
+			}
 			select {
 			case latencyCh <- latency:
 			default:
 			}
 		})
 
+		// Server-side events (quota warnings, admin notices, tunnel
+		// lifecycle) are drained inside the render goroutine so printed
+		// lines don't garble the live stats display.
+		eventCh := make(chan protocol.Event, 8)
+		connector.SetEventCallback(func(ev protocol.Event) {
+			select {
+			case eventCh <- ev:
+			default:
+			}
+		})
+
+		// With --inspect, proxied exchanges are printed one per line under
+		// the stats display as they complete.
+		var requestCh chan *capture.Request
+		if inspectLive {
+			requestCh = make(chan *capture.Request, 32)
+			connector.SetRequestCallback(func(r *capture.Request) {
+				select {
+				case requestCh <- r:
+				default:
+				}
+			})
+		}
+
 		stopDisplay := make(chan struct{})
 		disconnected := make(chan struct{})
 
-		go func() {
+		// HTTP tunnels capture recent exchanges for drip inspect; a snapshot
+		// is persisted periodically so other processes can read it.
+		if connConfig.TunnelType == protocol.TunnelTypeHTTP || connConfig.TunnelType == protocol.TunnelTypeHTTPS {
+			capBuf := capture.NewBuffer(512, 4<<20)
+			connector.SetCaptureBuffer(capBuf)
+			capPath := captureFilePath(string(connConfig.TunnelType), connConfig.LocalPort)
+
+			// The web inspector (--ui) serves the captured exchanges with
+			// replay against the local service.
+			if webUI {
+				replayURL := ""
+				if connConfig.LocalSocket == "" {
+					scheme := "http"
+					if connConfig.TunnelType == protocol.TunnelTypeHTTPS {
+						scheme = "https"
+					}
+					replayURL = fmt.Sprintf("%s://%s:%d", scheme, connConfig.LocalHost, connConfig.LocalPort)
+				}
+				insp := inspector.New(capBuf, replayURL, logger)
+				if inspURL, err := insp.Start(); err != nil {
+					if dash != nil {
+						dash.AppendLog(" " + ui.Warning(fmt.Sprintf("Web inspector unavailable: %v", err)))
+					} else if !quiet {
+						fmt.Println(ui.Warning(fmt.Sprintf("Web inspector unavailable: %v", err)))
+					}
+				} else {
+					if dash != nil {
+						dash.AppendLog(" " + ui.Success(fmt.Sprintf("Web inspector on %s", ui.URL(inspURL))))
+					} else if !quiet {
+						fmt.Println(ui.Success(fmt.Sprintf("Web inspector on %s", ui.URL(inspURL))))
+					}
+					go func() {
+						<-stopDisplay
+						insp.Stop()
+					}()
+				}
+			}
+
+			go func() {
+				snapshotTicker := time.NewTicker(5 * time.Second)
+				defer snapshotTicker.Stop()
+
+				for {
+					select {
+					case <-snapshotTicker.C:
+						writeCaptureSnapshot(capPath, capBuf)
+					case <-stopDisplay:
+						writeCaptureSnapshot(capPath, capBuf)
+						return
+					}
+				}
+			}()
+		}
+
+		// In machine-output mode the loop still drives alerts and the
+		// traffic limit, it just never draws the display.
+		renderLoop := func() {
 			renderTicker := time.NewTicker(1 * time.Second)
 			defer renderTicker.Stop()
 
@@ -107,6 +316,44 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 				select {
 				case latency := <-latencyCh:
 					lastLatency = latency
+				case r := <-requestCh:
+					if quiet {
+						continue
+					}
+					if dash != nil {
+						dash.AppendLog(" " + formatRequestLine(r))
+						continue
+					}
+					if lastRenderedLines > 0 {
+						fmt.Print(clearLines(lastRenderedLines))
+						lastRenderedLines = 0
+					}
+					fmt.Println(formatRequestLine(r))
+				case ev := <-eventCh:
+					// Broadcast events carry a subdomain when they concern
+					// one tunnel; ignore other tunnels' events.
+					if quiet || (ev.Subdomain != "" && ev.Subdomain != connector.GetSubdomain()) {
+						continue
+					}
+					if dash != nil {
+						switch ev.Type {
+						case protocol.EventQuotaWarning, protocol.EventAdminNotice, protocol.EventAnomaly:
+							dash.AppendLog(" " + ui.Warning(ev.Message))
+						default:
+							dash.AppendLog(" " + ui.Muted(ev.Message))
+						}
+						continue
+					}
+					if lastRenderedLines > 0 {
+						fmt.Print(clearLines(lastRenderedLines))
+						lastRenderedLines = 0
+					}
+					switch ev.Type {
+					case protocol.EventQuotaWarning, protocol.EventAdminNotice, protocol.EventAnomaly:
+						fmt.Println(ui.Warning(ev.Message))
+					default:
+						fmt.Println(ui.Muted(ev.Message))
+					}
 				case <-renderTicker.C:
 					stats := connector.GetStats()
 					if stats == nil {
@@ -116,13 +363,33 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 					stats.UpdateSpeed()
 					snapshot := stats.GetSnapshot()
 
+					if alerts != nil {
+						alerts.ObserveErrorRate(snapshot.TotalRequests, snapshot.ServerErrors)
+					}
+
+					if trafficLimit > 0 && !limitAlerted && trafficBase+snapshot.TotalBytes >= trafficLimit {
+						limitAlerted = true
+						if dash != nil {
+							dash.AppendLog(" " + ui.Warning(fmt.Sprintf("Traffic limit reached (%s)", alertBytes)))
+						} else if !quiet {
+							fmt.Println()
+							fmt.Println(ui.Warning(fmt.Sprintf("Traffic limit reached (%s)", alertBytes)))
+						}
+						if daemonInfo != nil || (alerts != nil && alerts.cfg.Notify) {
+							notifyDesktop("drip", fmt.Sprintf("Tunnel traffic limit reached (%s)", alertBytes))
+						}
+						if alertStop {
+							close(limitStop)
+						}
+					}
+
 					status.Latency = lastLatency
 					status.BytesIn = snapshot.TotalBytesIn
 					status.BytesOut = snapshot.TotalBytesOut
 					status.SpeedIn = float64(snapshot.SpeedIn)
 					status.SpeedOut = float64(snapshot.SpeedOut)
 
-					if status.Type == "tcp" {
+					if status.Type == "tcp" || status.Type == "udp" {
 						if snapshot.SpeedIn == 0 && snapshot.SpeedOut == 0 {
 							status.TotalRequest = 0
 						} else {
@@ -132,6 +399,16 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 						status.TotalRequest = snapshot.TotalRequests
 					}
 
+					if quiet {
+						continue
+					}
+
+					if dash != nil {
+						dash.SetStatus(status)
+						dash.Render()
+						continue
+					}
+
 					statsView := ui.RenderTunnelStats(status)
 					if lastRenderedLines > 0 {
 						fmt.Print(clearLines(lastRenderedLines))
@@ -143,18 +420,140 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 					return
 				}
 			}
-		}()
+		}
+		go renderLoop()
 
 		go func() {
 			connector.Wait()
 			close(disconnected)
 		}()
 
+		persistSession := func() {
+			if st := connector.GetStats(); st != nil {
+				if ctl != nil {
+					ctl.EndSession(st.GetSnapshot())
+				}
+				if err := RecordSessionStats(string(connConfig.TunnelType), connConfig.LocalPort, st.GetSnapshot()); err != nil {
+					logger.Warn("Failed to persist lifetime stats", zap.Error(err))
+				}
+			}
+		}
+
+		// Drop any network event that fired while we were reconnecting; the
+		// fresh connection already reflects the new network state.
+		select {
+		case <-netEvents:
+		default:
+		}
+
 		select {
+		case reason := <-netEvents:
+			close(stopDisplay)
+			if dash != nil {
+				dash.Stop()
+			}
+			if !quiet {
+				fmt.Println()
+				fmt.Println(ui.Warning(fmt.Sprintf("Reconnecting (%s)...", reason)))
+			}
+
+			persistSession()
+			if st := connector.GetStats(); st != nil {
+				trafficBase += st.GetTotalBytes()
+			}
+			connector.Close()
+
+			// Proactive reconnects don't count against the retry budget.
+			continue
+		case op := <-ctlOps:
+			close(stopDisplay)
+			if dash != nil {
+				dash.Stop()
+			}
+			if op == "reload" {
+				if !quiet {
+					fmt.Println()
+					fmt.Println(ui.Muted("Reloading tunnel (control request)..."))
+				}
+
+				persistSession()
+				if st := connector.GetStats(); st != nil {
+					trafficBase += st.GetTotalBytes()
+				}
+				connector.Close()
+
+				// Requested reconnects don't count against the retry budget.
+				continue
+			}
+
+			if !quiet {
+				fmt.Println()
+				fmt.Println(ui.RenderShuttingDown())
+			}
+
+			done := make(chan struct{})
+			go func() {
+				connector.Close()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				if !quiet {
+					fmt.Println(ui.Warning("Force closing (timeout)..."))
+				}
+			}
+
+			persistSession()
+			if daemonInfo != nil {
+				RemoveDaemonInfo(daemonInfo.Type, daemonInfo.Port)
+			}
+			if !quiet {
+				fmt.Println(ui.Success("Tunnel closed (control request)"))
+			}
+			return nil
+		case <-limitStop:
+			close(stopDisplay)
+			if dash != nil {
+				dash.Stop()
+			}
+			if !quiet {
+				fmt.Println()
+				fmt.Println(ui.RenderShuttingDown())
+			}
+
+			done := make(chan struct{})
+			go func() {
+				connector.Close()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				if !quiet {
+					fmt.Println(ui.Warning("Force closing (timeout)..."))
+				}
+			}
+
+			persistSession()
+			if daemonInfo != nil {
+				RemoveDaemonInfo(daemonInfo.Type, daemonInfo.Port)
+			}
+			if !quiet {
+				fmt.Println(ui.Success("Tunnel closed (traffic limit reached)"))
+			}
+			return nil
 		case <-quit:
 			close(stopDisplay)
-			fmt.Println()
-			fmt.Println(ui.RenderShuttingDown())
+			if dash != nil {
+				dash.Stop()
+			}
+			if !quiet {
+				fmt.Println()
+				fmt.Println(ui.RenderShuttingDown())
+			}
 
 			// Close with timeout (wait for ongoing requests to complete)
 			done := make(chan struct{})
@@ -167,27 +566,58 @@ func runTunnelWithUI(connConfig *tcp.ConnectorConfig, daemonInfo *DaemonInfo) er
 			case <-done:
 				// Closed successfully
 			case <-time.After(2 * time.Second):
-				fmt.Println(ui.Warning("Force closing (timeout)..."))
+				if !quiet {
+					fmt.Println(ui.Warning("Force closing (timeout)..."))
+				}
 			}
 
+			persistSession()
 			if daemonInfo != nil {
 				RemoveDaemonInfo(daemonInfo.Type, daemonInfo.Port)
 			}
-			fmt.Println(ui.Success("Tunnel closed"))
+			if !quiet {
+				fmt.Println(ui.Success("Tunnel closed"))
+			}
 			return nil
 		case <-disconnected:
 			close(stopDisplay)
-			fmt.Println()
-			fmt.Println(ui.RenderConnectionLost())
+			if dash != nil {
+				dash.Stop()
+			}
+			if !quiet {
+				fmt.Println()
+				fmt.Println(ui.RenderConnectionLost())
+			}
+
+			downSince = time.Now()
+			persistSession()
+			if st := connector.GetStats(); st != nil {
+				trafficBase += st.GetTotalBytes()
+			}
+			if alerts != nil {
+				alerts.ObserveDrop()
+			}
+			if daemonInfo != nil {
+				daemonInfo.Reconnects++
+				daemonInfo.LastDrop = downSince
+				if err := SaveDaemonInfo(daemonInfo); err != nil {
+					logger.Warn("Failed to save daemon info", zap.Error(err))
+				}
+			}
+
 			reconnectAttempts++
 			if reconnectAttempts >= maxReconnectAttempts {
 				return fmt.Errorf("connection lost after %d reconnect attempts", maxReconnectAttempts)
 			}
-			fmt.Println(ui.RenderRetrying(reconnectInterval))
+			if !quiet {
+				fmt.Println(ui.RenderRetrying(reconnectInterval))
+			}
 
 			select {
 			case <-quit:
-				fmt.Println(ui.RenderShuttingDown())
+				if !quiet {
+					fmt.Println(ui.RenderShuttingDown())
+				}
 				return nil
 			case <-time.After(reconnectInterval):
 				continue
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+
+	"github.com/spf13/cobra"
+)
+
+var udpCmd = &cobra.Command{
+	Use:   "udp <port>",
+	Short: "Start UDP tunnel",
+	Long: `Start a UDP tunnel to expose any UDP service.
+
+Example:
+  drip udp 53                       Tunnel a DNS server
+  drip udp 27015                    Tunnel a game server
+  drip udp 4433 --subdomain myquic  Tunnel a QUIC app with custom subdomain
+
+Datagrams are relayed over the tunnel's TLS connection with per-visitor
+flows, so replies reach the right client. Flows idle for 90 seconds are
+dropped.
+
+Configuration:
+  First time: Run 'drip config init' to save server and token
+  Subsequent: Just run 'drip udp <port>'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUDP,
+}
+
+func init() {
+	udpCmd.Flags().StringVarP(&subdomain, "subdomain", "n", "", "Custom subdomain (optional)")
+	udpCmd.Flags().BoolVarP(&daemonMode, "daemon", "d", false, "Run in background (daemon mode)")
+	udpCmd.Flags().StringVarP(&localAddress, "address", "a", "127.0.0.1", "Local address to forward to (default: 127.0.0.1)")
+	udpCmd.Flags().StringVar(&alertBytes, "alert-bytes", "", "Warn when cumulative traffic exceeds this limit (e.g. 5GB)")
+	udpCmd.Flags().BoolVar(&alertStop, "stop-at-limit", false, "Stop the tunnel instead of warning when --alert-bytes is reached")
+	udpCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Maximum simultaneous flows forwarded to the local service (0 = unlimited)")
+	udpCmd.Flags().StringVar(&transportMode, "transport", "", "Control channel transport: tls (default) or quic (server needs --quic-port)")
+	udpCmd.Flags().IntVar(&remotePort, "remote-port", 0, "Fixed public port, pinned to your token across restarts (0 = random)")
+	udpCmd.Flags().BoolVar(&daemonMarker, "daemon-child", false, "Internal flag for daemon child process")
+	udpCmd.Flags().MarkHidden("daemon-child")
+	rootCmd.AddCommand(udpCmd)
+}
+
+func runUDP(_ *cobra.Command, args []string) error {
+	port, err := strconv.Atoi(args[0])
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port number: %s", args[0])
+	}
+
+	if !daemonMarker {
+		if err := CheckExistingDaemon("udp", port); err != nil {
+			return err
+		}
+	}
+
+	if daemonMode && !daemonMarker {
+		return StartDaemon("udp", port, buildDaemonArgs("udp", args, subdomain, localAddress))
+	}
+
+	serverAddr, token, err := resolveServerAddrAndToken("udp", port)
+	if err != nil {
+		return err
+	}
+
+	connConfig := &tcp.ConnectorConfig{
+		ServerAddr:    serverAddr,
+		Token:         token,
+		TunnelType:    protocol.TunnelTypeUDP,
+		LocalHost:     localAddress,
+		LocalPort:     port,
+		Subdomain:     subdomain,
+		Insecure:      insecure,
+		MaxConcurrent: maxConcurrent,
+		Transport:     transportMode,
+		RemotePort:    remotePort,
+	}
+
+	var daemon *DaemonInfo
+	if daemonMarker {
+		daemon = newDaemonInfo("udp", port, subdomain, serverAddr)
+	}
+
+	return runTunnelWithUI(connConfig, daemon)
+}
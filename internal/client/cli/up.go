@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/ui"
+	"drip/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var upConfigPath string
+
+var upCmd = &cobra.Command{
+	Use:   "up [name...]",
+	Short: "Start the tunnels defined in the project's .drip.yml or the client config",
+	Long: `Start all tunnels defined by the project config file, in the
+background. The config is discovered by walking up from the current
+directory (like .nvmrc), so 'drip up' in a project always starts the same
+tunnels with the same subdomains.
+
+Example .drip.yml:
+
+  tunnels:
+    - type: http
+      port: 3000
+      subdomain: myapp
+    - type: tcp
+      port: 5432
+
+Named tunnels defined in the client config (~/.drip/config.yaml) run in
+this one process instead, with a combined status display:
+
+  tunnels:
+    web:
+      type: http
+      port: 3000
+      subdomain: myapp
+    db:
+      type: tcp
+      port: 5432
+
+Example:
+  drip up                     Start tunnels from the nearest .drip.yml,
+                              or all named tunnels when there is none
+  drip up web db              Start only the named tunnels
+  drip up -f ./ops/drip.yml   Use an explicit config file`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runUp,
+}
+
+func init() {
+	upCmd.Flags().StringVarP(&upConfigPath, "file", "f", "", "Project config file (default: nearest .drip.yml)")
+	rootCmd.AddCommand(upCmd)
+}
+
+func runUp(_ *cobra.Command, args []string) error {
+	// Names always select from the client config's tunnels: section.
+	if len(args) > 0 {
+		cfg, err := config.LoadClientConfig("")
+		if err != nil {
+			return err
+		}
+		return runNamedUp(cfg, args)
+	}
+
+	path := upConfigPath
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		path = config.FindProjectConfig(cwd)
+		if path == "" {
+			// No project file: fall back to the client config's named
+			// tunnels, if any are defined.
+			if cfg, err := config.LoadClientConfig(""); err == nil && len(cfg.Tunnels) > 0 {
+				return runNamedUp(cfg, nil)
+			}
+			return fmt.Errorf("no %s found in this directory or any parent, and no tunnels defined in the client config", config.ProjectConfigName)
+		}
+	}
+
+	project, err := config.LoadProjectConfig(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Muted("Using " + path))
+
+	started := 0
+	for _, t := range project.Tunnels {
+		label := fmt.Sprintf("%s :%d", t.Type, t.Port)
+
+		if err := CheckExistingDaemon(t.Type, t.Port); err != nil {
+			fmt.Println(ui.Muted(fmt.Sprintf("Skipping %s: already running", label)))
+			continue
+		}
+
+		if err := StartDaemon(t.Type, t.Port, projectTunnelArgs(t)); err != nil {
+			fmt.Println(ui.Error(fmt.Sprintf("Failed to start %s: %v", label, err)))
+			continue
+		}
+		started++
+	}
+
+	if started == 0 {
+		fmt.Println(ui.Warning("No new tunnels started"))
+		return nil
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("%d tunnel(s) running — see 'drip list'", started)))
+	return nil
+}
+
+// runNamedUp starts the client config's named tunnels in this process with
+// a combined status display. With no names, all defined tunnels start.
+func runNamedUp(cfg *config.ClientConfig, names []string) error {
+	if len(cfg.Tunnels) == 0 {
+		return fmt.Errorf("no tunnels defined in the client config (add a tunnels: section)")
+	}
+
+	if len(names) == 0 {
+		for name := range cfg.Tunnels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	serverAddr := serverURL
+	token := authToken
+	if serverAddr == "" {
+		serverAddr = selectServer(cfg)
+		if serverAddr == "" {
+			return fmt.Errorf("server address is required")
+		}
+		token = cfg.Token
+	}
+
+	tunnels := make([]*upTunnel, 0, len(names))
+	for _, name := range names {
+		t, ok := cfg.Tunnels[name]
+		if !ok {
+			return fmt.Errorf("tunnel %q is not defined in the client config", name)
+		}
+
+		address := t.Address
+		if address == "" {
+			address = "127.0.0.1"
+		}
+
+		var tType protocol.TunnelType
+		switch t.Type {
+		case "http":
+			tType = protocol.TunnelTypeHTTP
+		case "https":
+			tType = protocol.TunnelTypeHTTPS
+		case "tcp":
+			tType = protocol.TunnelTypeTCP
+		}
+
+		tunnels = append(tunnels, &upTunnel{
+			name: name,
+			config: &tcp.ConnectorConfig{
+				ServerAddr: serverAddr,
+				Token:      token,
+				TunnelType: tType,
+				LocalHost:  address,
+				LocalPort:  t.Port,
+				Subdomain:  t.Subdomain,
+				Insecure:   insecure,
+			},
+		})
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Muted(fmt.Sprintf("Starting %d tunnel(s) from the client config", len(tunnels))))
+
+	return runUpTunnels(tunnels)
+}
+
+// projectTunnelArgs builds the daemon child argument list for one project
+// tunnel entry.
+func projectTunnelArgs(t config.ProjectTunnel) []string {
+	args := []string{t.Type, strconv.Itoa(t.Port), "--daemon-child"}
+	if t.Subdomain != "" {
+		args = append(args, "--subdomain", t.Subdomain)
+	}
+	if t.Address != "" && t.Address != "127.0.0.1" {
+		args = append(args, "--address", t.Address)
+	}
+	if serverURL != "" {
+		args = append(args, "--server", serverURL)
+	}
+	if authToken != "" {
+		args = append(args, "--token", authToken)
+	}
+	if insecure {
+		args = append(args, "--insecure")
+	}
+	return args
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"drip/internal/client/tcp"
+	"drip/internal/shared/ui"
+	"drip/internal/shared/utils"
+
+	"go.uber.org/zap"
+)
+
+// upTunnel is one named tunnel running inside a single 'drip up' process.
+// The worker goroutine owns the connector; the render loop reads the
+// snapshot.
+type upTunnel struct {
+	name   string
+	config *tcp.ConnectorConfig
+
+	mu        sync.Mutex
+	connector tcp.TunnelClient
+	url       string
+	state     upState
+	lastErr   error
+}
+
+type upState int
+
+const (
+	upConnecting upState = iota
+	upOnline
+	upRetrying
+	upFailed
+	upClosed
+)
+
+func (t *upTunnel) setState(state upState, err error) {
+	t.mu.Lock()
+	t.state = state
+	t.lastErr = err
+	t.mu.Unlock()
+}
+
+func (t *upTunnel) setOnline(connector tcp.TunnelClient) {
+	t.mu.Lock()
+	t.connector = connector
+	t.url = connector.GetURL()
+	t.state = upOnline
+	t.lastErr = nil
+	t.mu.Unlock()
+}
+
+func (t *upTunnel) snapshot() (upState, string, error, tcp.TunnelClient) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state, t.url, t.lastErr, t.connector
+}
+
+// runUpTunnels runs all named tunnels in this process, each with its own
+// reconnect loop, under one combined status display. It returns when the
+// user interrupts or every tunnel has given up.
+func runUpTunnels(tunnels []*upTunnel) error {
+	if err := utils.InitLogger(verbose); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer utils.Sync()
+
+	logger := utils.GetLogger()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, t := range tunnels {
+		wg.Add(1)
+		go func(t *upTunnel) {
+			defer wg.Done()
+			runUpTunnel(t, logger, stop)
+		}(t)
+	}
+
+	allDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(allDone)
+	}()
+
+	renderTicker := time.NewTicker(1 * time.Second)
+	defer renderTicker.Stop()
+
+	lastRenderedLines := 0
+	render := func() {
+		block := renderUpStatus(tunnels)
+		if lastRenderedLines > 0 {
+			fmt.Print(clearLines(lastRenderedLines))
+		}
+		fmt.Print(block)
+		lastRenderedLines = countRenderedLines(block)
+	}
+	render()
+
+	for {
+		select {
+		case <-renderTicker.C:
+			render()
+		case <-quit:
+			close(stop)
+			fmt.Println()
+			fmt.Println(ui.RenderShuttingDown())
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				fmt.Println(ui.Warning("Force closing (timeout)..."))
+			}
+
+			fmt.Println(ui.Success("Tunnels closed"))
+			return nil
+		case <-allDone:
+			render()
+			fmt.Println()
+			return fmt.Errorf("all tunnels stopped")
+		}
+	}
+}
+
+// runUpTunnel is the per-tunnel connect/reconnect loop, a slimmed-down
+// runTunnelWithUI without its own display.
+func runUpTunnel(t *upTunnel, logger *zap.Logger, stop <-chan struct{}) {
+	attempts := 0
+	for {
+		connector := tcp.NewTunnelClient(t.config, logger)
+		t.setState(upConnecting, nil)
+
+		if err := connector.Connect(); err != nil {
+			if isNonRetryableError(err) {
+				t.setState(upFailed, err)
+				return
+			}
+			attempts++
+			if attempts >= maxReconnectAttempts {
+				t.setState(upFailed, err)
+				return
+			}
+			t.setState(upRetrying, err)
+			select {
+			case <-stop:
+				t.setState(upClosed, nil)
+				return
+			case <-time.After(reconnectInterval):
+				continue
+			}
+		}
+
+		attempts = 0
+		if sub := connector.GetSubdomain(); sub != "" {
+			t.config.Subdomain = sub
+		}
+		t.setOnline(connector)
+
+		disconnected := make(chan struct{})
+		go func() {
+			connector.Wait()
+			close(disconnected)
+		}()
+
+		select {
+		case <-stop:
+			connector.Close()
+			t.setState(upClosed, nil)
+			return
+		case <-disconnected:
+			attempts++
+			if attempts >= maxReconnectAttempts {
+				t.setState(upFailed, fmt.Errorf("connection lost after %d reconnect attempts", maxReconnectAttempts))
+				return
+			}
+			t.setState(upRetrying, nil)
+			select {
+			case <-stop:
+				t.setState(upClosed, nil)
+				return
+			case <-time.After(reconnectInterval):
+			}
+		}
+	}
+}
+
+// renderUpStatus renders one line per tunnel: name, type, local target, and
+// either the public URL with live counters or the current state.
+func renderUpStatus(tunnels []*upTunnel) string {
+	var b strings.Builder
+	b.WriteString("\n")
+
+	for _, t := range tunnels {
+		local := fmt.Sprintf("%s:%d", t.config.LocalHost, t.config.LocalPort)
+		b.WriteString(fmt.Sprintf("  %-14s %-6s %-21s ", t.name, t.config.TunnelType, local))
+
+		state, url, lastErr, connector := t.snapshot()
+		switch state {
+		case upOnline:
+			b.WriteString(ui.URL(url))
+			if connector != nil {
+				if stats := connector.GetStats(); stats != nil {
+					stats.UpdateSpeed()
+					s := stats.GetSnapshot()
+					b.WriteString(ui.Muted(fmt.Sprintf("  %d req  ↓ %s  ↑ %s",
+						s.TotalRequests, ui.FormatBytes(s.TotalBytesIn), ui.FormatBytes(s.TotalBytesOut))))
+				}
+			}
+		case upFailed:
+			if lastErr != nil {
+				b.WriteString(ui.Error(fmt.Sprintf("failed: %v", lastErr)))
+			} else {
+				b.WriteString(ui.Error("failed"))
+			}
+		case upRetrying:
+			b.WriteString(ui.Warning("reconnecting..."))
+		case upClosed:
+			b.WriteString(ui.Muted("closed"))
+		default:
+			b.WriteString(ui.Muted("connecting..."))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
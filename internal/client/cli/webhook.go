@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"drip/internal/client/webhook"
+	"drip/internal/shared/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	webhookSendTo     string
+	webhookSendURL    string
+	webhookSendSecret string
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Webhook testing utilities",
+}
+
+var webhookSendCmd = &cobra.Command{
+	Use:   "send <provider:event>",
+	Short: "Send a mock webhook through a tunnel",
+	Long: `Send a realistic mock webhook payload through a running tunnel's
+public URL, so handlers can be tested without configuring the real provider.
+
+When a secret is given (--secret or DRIP_WEBHOOK_SECRET) the payload is
+signed the same way the provider would sign it.
+
+Events:
+  github:ping, github:push, github:pull_request, github:issues
+  stripe:<event type>  (e.g. stripe:payment_intent.succeeded)
+  slack:url_verification, slack:event_callback
+
+Examples:
+  drip webhook send github:push --to /hooks/github
+  drip webhook send stripe:invoice.paid --to /stripe --secret whsec_123
+  drip webhook send slack:event_callback --url https://myapp.drip.dev`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWebhookSend,
+}
+
+func init() {
+	webhookSendCmd.Flags().StringVar(&webhookSendTo, "to", "/", "Path to deliver the webhook to")
+	webhookSendCmd.Flags().StringVar(&webhookSendURL, "url", "", "Target base URL (default: the running HTTP tunnel)")
+	webhookSendCmd.Flags().StringVar(&webhookSendSecret, "secret", "", "Signing secret (or set DRIP_WEBHOOK_SECRET)")
+	webhookCmd.AddCommand(webhookSendCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+func runWebhookSend(_ *cobra.Command, args []string) error {
+	event, err := webhook.NewMockEvent(args[0])
+	if err != nil {
+		return err
+	}
+
+	secret := webhookSendSecret
+	if secret == "" {
+		secret = os.Getenv("DRIP_WEBHOOK_SECRET")
+	}
+	if secret != "" {
+		event.Sign(secret)
+	}
+
+	baseURL := webhookSendURL
+	if baseURL == "" {
+		baseURL, err = findTunnelURL()
+		if err != nil {
+			return err
+		}
+	}
+
+	target := strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(webhookSendTo, "/")
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(event.Body))
+	if err != nil {
+		return fmt.Errorf("invalid target URL: %w", err)
+	}
+	for key, values := range event.Header {
+		req.Header[key] = values
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	lines := []string{
+		ui.KeyValue("Event", fmt.Sprintf("%s:%s", event.Provider, event.Event)),
+		ui.KeyValue("Target", target),
+		ui.KeyValue("Status", resp.Status),
+		ui.KeyValue("Duration", fmt.Sprintf("%dms", time.Since(start).Milliseconds())),
+	}
+	if secret == "" {
+		lines = append(lines, "", ui.Muted("Unsigned delivery (no secret configured)"))
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		fmt.Println(ui.SuccessBox("Webhook Delivered", lines...))
+	} else {
+		fmt.Println(ui.WarningBox("Webhook Delivered (non-2xx)", lines...))
+	}
+	return nil
+}
+
+// findTunnelURL returns the public URL of the single running HTTP tunnel,
+// or an error telling the user to pass --url when that's ambiguous.
+func findTunnelURL() (string, error) {
+	daemons, err := ListAllDaemons()
+	if err != nil {
+		return "", fmt.Errorf("failed to list tunnels: %w", err)
+	}
+
+	var urls []string
+	for _, d := range daemons {
+		if d.Type != "http" && d.Type != "https" {
+			continue
+		}
+		if d.URL == "" || !IsProcessRunning(d.PID) {
+			continue
+		}
+		urls = append(urls, d.URL)
+	}
+
+	switch len(urls) {
+	case 0:
+		return "", fmt.Errorf("no running HTTP tunnel found; start one or pass --url")
+	case 1:
+		return urls[0], nil
+	default:
+		return "", fmt.Errorf("multiple tunnels running (%s); pass --url to pick one", strings.Join(urls, ", "))
+	}
+}
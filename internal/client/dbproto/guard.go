@@ -0,0 +1,559 @@
+package dbproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// ErrWriteBlocked terminates a guarded connection that cannot be answered
+// with an in-protocol error (e.g. a blocked prepared statement).
+var ErrWriteBlocked = errors.New("write command blocked by read-only tunnel")
+
+// Guard filters the client-to-server stream of a database tunnel, blocking
+// write statements/commands at the protocol level so a shared database stays
+// read-only without touching DB users.
+type Guard struct {
+	protocol string
+	logger   *zap.Logger
+	blocked  atomic.Int64
+}
+
+// NewGuard creates a read-only guard for one of the supported protocols.
+func NewGuard(protocol string, logger *zap.Logger) *Guard {
+	return &Guard{
+		protocol: protocol,
+		logger:   logger,
+	}
+}
+
+// NewConn returns per-connection filter state.
+func (g *Guard) NewConn() *GuardConn {
+	gc := &GuardConn{guard: g}
+	switch g.protocol {
+	case ProtocolPostgres:
+		gc.filter = &postgresGuard{}
+	case ProtocolMySQL:
+		gc.filter = &mysqlGuard{}
+	case ProtocolRedis:
+		gc.filter = newRedisGuard()
+	}
+	return gc
+}
+
+func (g *Guard) logBlocked(detail string) {
+	n := g.blocked.Add(1)
+	g.logger.Info("Blocked write on read-only tunnel",
+		zap.String("protocol", g.protocol),
+		zap.String("command", detail),
+		zap.Int64("total_blocked", n),
+	)
+}
+
+// GuardConn filters one connection's client-to-server bytes.
+type GuardConn struct {
+	guard  *Guard
+	filter guardFilter
+}
+
+// guardFilter consumes client-to-server bytes and splits them into bytes
+// cleared for forwarding and in-protocol error replies for the client.
+type guardFilter interface {
+	// process returns the bytes to forward to the database, any error reply
+	// to send back to the public client, and kill=true when the connection
+	// must be terminated (unfilterable or unanswerable traffic). blocked
+	// names the rejected command for logging, "" when nothing was blocked.
+	process(in []byte) (forward, reply []byte, blocked string, kill bool)
+}
+
+// Process filters a chunk of the client stream.
+func (c *GuardConn) Process(in []byte) (forward, reply []byte, kill bool) {
+	if c.filter == nil {
+		return in, nil, false
+	}
+	forward, reply, blocked, kill := c.filter.process(in)
+	if blocked != "" {
+		c.guard.logBlocked(blocked)
+	}
+	return forward, reply, kill
+}
+
+// sqlWriteKeywords are statement words that make SQL non-read-only. Matching
+// any of them anywhere in the statement blocks it; over-blocking is the safe
+// direction for a guard.
+var sqlWriteKeywords = map[string]bool{
+	"insert": true, "update": true, "delete": true, "merge": true,
+	"replace": true, "upsert": true, "copy": true, "import": true,
+	"create": true, "alter": true, "drop": true, "truncate": true,
+	"rename": true, "grant": true, "revoke": true, "reindex": true,
+	"vacuum": true, "cluster": true, "call": true, "do": true,
+	"lock": true, "listen": true, "notify": true,
+}
+
+// sqlIsReadOnly reports whether a statement contains no write keywords.
+func sqlIsReadOnly(query string) bool {
+	word := strings.Builder{}
+	check := func() bool {
+		defer word.Reset()
+		return !sqlWriteKeywords[strings.ToLower(word.String())]
+	}
+	for _, r := range query {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' {
+			word.WriteRune(r)
+			continue
+		}
+		if word.Len() > 0 && !check() {
+			return false
+		}
+	}
+	return word.Len() == 0 || check()
+}
+
+// firstSQLWord returns the statement's leading keyword for logging.
+func firstSQLWord(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "?"
+	}
+	word := strings.ToUpper(fields[0])
+	if len(word) > 16 {
+		word = word[:16]
+	}
+	return word
+}
+
+// postgresGuard filters the Postgres frontend protocol. Simple queries ('Q')
+// with write keywords are answered with an ErrorResponse and dropped;
+// blocked prepared statements ('P') terminate the connection since the
+// extended protocol cannot be answered mid-pipeline. SSL/GSS encryption
+// requests are refused so the stream stays inspectable.
+type postgresGuard struct {
+	buf     []byte
+	started bool
+	skip    int // remaining bytes of a message being streamed through
+}
+
+const pgMaxQuery = 256 << 10
+
+func (p *postgresGuard) process(in []byte) (forward, reply []byte, blocked string, kill bool) {
+	for {
+		if p.skip > 0 {
+			n := len(in)
+			if n > p.skip {
+				n = p.skip
+			}
+			forward = append(forward, in[:n]...)
+			p.skip -= n
+			in = in[n:]
+			if p.skip > 0 {
+				return forward, reply, blocked, false
+			}
+		}
+
+		p.buf = append(p.buf, in...)
+		in = nil
+
+		if !p.started {
+			if len(p.buf) < 8 {
+				return forward, reply, blocked, false
+			}
+			msgLen := int(binary.BigEndian.Uint32(p.buf))
+			code := binary.BigEndian.Uint32(p.buf[4:8])
+			if code == pgSSLRequest || code == pgGSSEncRequest {
+				// Refuse encryption so the stream stays filterable; the
+				// client falls back to plaintext (the tunnel itself is TLS).
+				if msgLen != 8 {
+					return forward, reply, blocked, true
+				}
+				reply = append(reply, 'N')
+				p.buf = append([]byte(nil), p.buf[8:]...)
+				continue
+			}
+			if msgLen < 8 || msgLen > pgMaxStartup {
+				return forward, reply, blocked, true
+			}
+			if len(p.buf) < msgLen {
+				return forward, reply, blocked, false
+			}
+			forward = append(forward, p.buf[:msgLen]...)
+			p.buf = append([]byte(nil), p.buf[msgLen:]...)
+			p.started = true
+			continue
+		}
+
+		if len(p.buf) < 5 {
+			return forward, reply, blocked, false
+		}
+		typ := p.buf[0]
+		msgLen := int(binary.BigEndian.Uint32(p.buf[1:5]))
+		if msgLen < 4 {
+			return forward, reply, blocked, true
+		}
+		total := 1 + msgLen
+
+		if typ != 'Q' && typ != 'P' {
+			// Stream other messages through untouched.
+			n := len(p.buf)
+			if n > total {
+				n = total
+			}
+			forward = append(forward, p.buf[:n]...)
+			p.skip = total - n
+			p.buf = append([]byte(nil), p.buf[n:]...)
+			continue
+		}
+
+		if total > pgMaxQuery {
+			return forward, reply, blocked, true
+		}
+		if len(p.buf) < total {
+			return forward, reply, blocked, false
+		}
+
+		query := pgQueryText(typ, p.buf[5:total])
+		if sqlIsReadOnly(query) {
+			forward = append(forward, p.buf[:total]...)
+			p.buf = append([]byte(nil), p.buf[total:]...)
+			continue
+		}
+
+		blocked = firstSQLWord(query)
+		reply = append(reply, pgErrorResponse("read-only tunnel: statement blocked")...)
+		if typ == 'P' {
+			// Extended protocol: cannot answer a single Parse cleanly.
+			return forward, reply, blocked, true
+		}
+		reply = append(reply, 'Z', 0, 0, 0, 5, 'I') // ReadyForQuery (idle)
+		p.buf = append([]byte(nil), p.buf[total:]...)
+	}
+}
+
+// pgQueryText extracts the SQL text from a Query or Parse message body.
+func pgQueryText(typ byte, body []byte) string {
+	if typ == 'P' {
+		// Parse: statement name, then the query, both null-terminated.
+		if idx := bytes.IndexByte(body, 0); idx >= 0 {
+			body = body[idx+1:]
+		}
+	}
+	return cString(body)
+}
+
+// pgErrorResponse encodes an ErrorResponse with SQLSTATE 25006
+// (read_only_sql_transaction).
+func pgErrorResponse(message string) []byte {
+	var fields bytes.Buffer
+	for _, f := range []struct {
+		code byte
+		val  string
+	}{
+		{'S', "ERROR"},
+		{'V', "ERROR"},
+		{'C', "25006"},
+		{'M', message},
+	} {
+		fields.WriteByte(f.code)
+		fields.WriteString(f.val)
+		fields.WriteByte(0)
+	}
+	fields.WriteByte(0)
+
+	msg := make([]byte, 5, 5+fields.Len())
+	msg[0] = 'E'
+	binary.BigEndian.PutUint32(msg[1:5], uint32(4+fields.Len()))
+	return append(msg, fields.Bytes()...)
+}
+
+// mysqlGuard filters the MySQL client protocol: COM_QUERY and
+// COM_STMT_PREPARE payloads with write keywords are answered with an ERR
+// packet (ER_OPTION_PREVENTS_STATEMENT) and dropped.
+type mysqlGuard struct {
+	buf    []byte
+	authed bool
+	skip   int
+}
+
+const (
+	mysqlComQuery       = 0x03
+	mysqlComStmtPrepare = 0x16
+	mysqlMaxQuery       = 1 << 20
+)
+
+func (m *mysqlGuard) process(in []byte) (forward, reply []byte, blocked string, kill bool) {
+	for {
+		if m.skip > 0 {
+			n := len(in)
+			if n > m.skip {
+				n = m.skip
+			}
+			forward = append(forward, in[:n]...)
+			m.skip -= n
+			in = in[n:]
+			if m.skip > 0 {
+				return forward, reply, blocked, false
+			}
+		}
+
+		m.buf = append(m.buf, in...)
+		in = nil
+
+		if len(m.buf) < 4 {
+			return forward, reply, blocked, false
+		}
+		payloadLen := int(m.buf[0]) | int(m.buf[1])<<8 | int(m.buf[2])<<16
+		seq := m.buf[3]
+		total := 4 + payloadLen
+
+		if !m.authed {
+			if payloadLen > mysqlMaxHandshake {
+				return forward, reply, blocked, true
+			}
+			if len(m.buf) < total {
+				return forward, reply, blocked, false
+			}
+			payload := m.buf[4:total]
+			if len(payload) >= 2 {
+				caps := uint32(payload[0]) | uint32(payload[1])<<8
+				if caps&mysqlClientSSL != 0 && payloadLen <= 36 {
+					// TLS would make the stream unfilterable.
+					return forward, reply, blocked, true
+				}
+			}
+			forward = append(forward, m.buf[:total]...)
+			m.buf = append([]byte(nil), m.buf[total:]...)
+			m.authed = true
+			continue
+		}
+
+		isQuery := seq == 0 && payloadLen > 0 && len(m.buf) >= 5 &&
+			(m.buf[4] == mysqlComQuery || m.buf[4] == mysqlComStmtPrepare)
+
+		if !isQuery {
+			if seq == 0 && len(m.buf) < 5 {
+				return forward, reply, blocked, false
+			}
+			n := len(m.buf)
+			if n > total {
+				n = total
+			}
+			forward = append(forward, m.buf[:n]...)
+			m.skip = total - n
+			m.buf = append([]byte(nil), m.buf[n:]...)
+			continue
+		}
+
+		if payloadLen > mysqlMaxQuery {
+			return forward, reply, blocked, true
+		}
+		if len(m.buf) < total {
+			return forward, reply, blocked, false
+		}
+
+		query := string(m.buf[5:total])
+		if sqlIsReadOnly(query) {
+			forward = append(forward, m.buf[:total]...)
+			m.buf = append([]byte(nil), m.buf[total:]...)
+			continue
+		}
+
+		blocked = firstSQLWord(query)
+		reply = append(reply, mysqlErrPacket("read-only tunnel: statement blocked")...)
+		m.buf = append([]byte(nil), m.buf[total:]...)
+	}
+}
+
+// mysqlErrPacket encodes an ERR packet (sequence 1, answering a command)
+// with error 1290, ER_OPTION_PREVENTS_STATEMENT.
+func mysqlErrPacket(message string) []byte {
+	payload := make([]byte, 0, 9+len(message))
+	payload = append(payload, 0xFF)
+	payload = binary.LittleEndian.AppendUint16(payload, 1290)
+	payload = append(payload, '#')
+	payload = append(payload, "HY000"...)
+	payload = append(payload, message...)
+
+	packet := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), 1}
+	return append(packet, payload...)
+}
+
+// redisReadCommands are the commands a read-only tunnel lets through.
+var redisReadCommands = map[string]bool{
+	"GET": true, "MGET": true, "GETRANGE": true, "STRLEN": true,
+	"EXISTS": true, "TYPE": true, "TTL": true, "PTTL": true,
+	"EXPIRETIME": true, "PEXPIRETIME": true, "KEYS": true, "SCAN": true,
+	"RANDOMKEY": true, "DBSIZE": true, "DUMP": true, "OBJECT": true,
+	"MEMORY": true, "BITCOUNT": true, "GETBIT": true, "BITPOS": true,
+	"HGET": true, "HGETALL": true, "HMGET": true, "HKEYS": true,
+	"HVALS": true, "HLEN": true, "HEXISTS": true, "HSCAN": true,
+	"HSTRLEN": true, "HRANDFIELD": true,
+	"LRANGE": true, "LLEN": true, "LINDEX": true, "LPOS": true,
+	"SMEMBERS": true, "SISMEMBER": true, "SMISMEMBER": true,
+	"SCARD": true, "SSCAN": true, "SRANDMEMBER": true, "SINTERCARD": true,
+	"ZRANGE": true, "ZRANGEBYSCORE": true, "ZRANGEBYLEX": true,
+	"ZREVRANGE": true, "ZSCORE": true, "ZMSCORE": true, "ZCARD": true,
+	"ZCOUNT": true, "ZLEXCOUNT": true, "ZRANK": true, "ZREVRANK": true,
+	"ZSCAN": true, "ZRANDMEMBER": true,
+	"XRANGE": true, "XREVRANGE": true, "XREAD": true, "XLEN": true,
+	"XINFO": true, "GEOPOS": true, "GEODIST": true, "GEOSEARCH": true,
+	"PFCOUNT": true, "LCS": true,
+	"SUBSCRIBE": true, "UNSUBSCRIBE": true, "PSUBSCRIBE": true,
+	"PUNSUBSCRIBE": true,
+	"PING":         true, "ECHO": true, "AUTH": true, "SELECT": true,
+	"HELLO": true, "TIME": true, "INFO": true, "COMMAND": true,
+	"CLIENT": true, "RESET": true, "QUIT": true,
+}
+
+const redisReadOnlyError = "-READONLY You can't write against a read-only tunnel\r\n"
+
+// redisGuard filters RESP commands: the command name decides whether the
+// whole multibulk is forwarded or swallowed and answered with -READONLY.
+type redisGuard struct {
+	buf      []byte
+	skip     int // payload bytes of the current argument still to route
+	args     int // arguments remaining in the current command
+	argIdx   int
+	pend     []byte // held bytes of the current command awaiting a decision
+	passing  bool
+	dropping bool
+	wantName int // length of the command-name payload, -1 when not expected
+}
+
+func newRedisGuard() *redisGuard {
+	return &redisGuard{wantName: -1}
+}
+
+func (r *redisGuard) process(in []byte) (forward, reply []byte, blocked string, kill bool) {
+	for {
+		if r.skip > 0 {
+			n := len(in)
+			if n > r.skip {
+				n = r.skip
+			}
+			if r.passing {
+				forward = append(forward, in[:n]...)
+			}
+			r.skip -= n
+			in = in[n:]
+			if r.skip > 0 {
+				return forward, reply, blocked, false
+			}
+		}
+
+		r.buf = append(r.buf, in...)
+		in = nil
+
+		if r.wantName >= 0 {
+			need := r.wantName + 2
+			if len(r.buf) < need {
+				return forward, reply, blocked, false
+			}
+			name := strings.ToUpper(string(r.buf[:r.wantName]))
+			r.pend = append(r.pend, r.buf[:need]...)
+			r.buf = append([]byte(nil), r.buf[need:]...)
+			r.wantName = -1
+			r.argIdx++
+			r.args--
+
+			if redisReadCommands[name] {
+				r.passing = true
+				forward = append(forward, r.pend...)
+			} else {
+				r.dropping = true
+				blocked = name
+				reply = append(reply, redisReadOnlyError...)
+			}
+			r.pend = nil
+			continue
+		}
+
+		idx := bytes.Index(r.buf, crlf)
+		if idx < 0 {
+			if len(r.buf) > redisMaxLine {
+				return forward, reply, blocked, true
+			}
+			return forward, reply, blocked, false
+		}
+		line := r.buf[:idx]
+		consumed := r.buf[:idx+2]
+
+		if r.args == 0 {
+			// Between commands: expect a multibulk header or inline command.
+			r.passing, r.dropping = false, false
+			if len(line) == 0 {
+				r.buf = append([]byte(nil), r.buf[idx+2:]...)
+				continue
+			}
+			if line[0] != '*' {
+				// Inline command: decide on its first word.
+				name := strings.ToUpper(firstSQLWord(string(line)))
+				if redisReadCommands[name] {
+					forward = append(forward, consumed...)
+				} else {
+					blocked = name
+					reply = append(reply, redisReadOnlyError...)
+				}
+				r.buf = append([]byte(nil), r.buf[idx+2:]...)
+				continue
+			}
+			n := parseRESPInt(line[1:])
+			if n < 0 {
+				return forward, reply, blocked, true
+			}
+			r.args, r.argIdx = n, 0
+			r.pend = append(r.pend[:0], consumed...)
+			r.buf = append([]byte(nil), r.buf[idx+2:]...)
+			continue
+		}
+
+		// Expect a bulk string header for the next argument.
+		if len(line) == 0 || line[0] != '$' {
+			return forward, reply, blocked, true
+		}
+		n := parseRESPInt(line[1:])
+		if n < 0 {
+			return forward, reply, blocked, true
+		}
+
+		if r.argIdx == 0 {
+			// Command name: hold it (bounded by redisMaxLine) for a decision.
+			if n > redisMaxLine {
+				return forward, reply, blocked, true
+			}
+			r.pend = append(r.pend, consumed...)
+			r.buf = append([]byte(nil), r.buf[idx+2:]...)
+			r.wantName = n
+			continue
+		}
+
+		// Later arguments stream according to the decision.
+		if r.passing {
+			forward = append(forward, consumed...)
+		}
+		r.buf = append([]byte(nil), r.buf[idx+2:]...)
+		r.skip = n + 2
+		r.argIdx++
+		r.args--
+	}
+}
+
+// parseRESPInt parses a RESP integer, returning -1 on malformed input.
+func parseRESPInt(b []byte) int {
+	n := 0
+	if len(b) == 0 {
+		return -1
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return -1
+		}
+		n = n*10 + int(c-'0')
+		if n > 1<<28 {
+			return -1
+		}
+	}
+	return n
+}
@@ -0,0 +1,143 @@
+// Package dbproto adds lightweight protocol awareness for database tunnels.
+// It watches the client-to-server byte stream of Postgres, MySQL, and Redis
+// connections to log connection attempts, usernames, and command rates —
+// enough to make an exposed database observable without capturing payloads.
+package dbproto
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Supported database protocols.
+const (
+	ProtocolPostgres = "postgres"
+	ProtocolMySQL    = "mysql"
+	ProtocolRedis    = "redis"
+)
+
+// wellKnownPorts maps default database ports to their protocol.
+var wellKnownPorts = map[int]string{
+	5432: ProtocolPostgres,
+	3306: ProtocolMySQL,
+	6379: ProtocolRedis,
+}
+
+// Detect returns the database protocol conventionally served on port, or ""
+// when the port is not a well-known database port.
+func Detect(port int) string {
+	return wellKnownPorts[port]
+}
+
+// statsInterval is how often the observer logs the command rate.
+const statsInterval = 10 * time.Second
+
+// Observer aggregates connection and command counts across all public
+// connections of one tunnel.
+type Observer struct {
+	protocol string
+	logger   *zap.Logger
+
+	connects atomic.Int64
+	commands atomic.Int64
+}
+
+// NewObserver creates an observer for one of the supported protocols.
+func NewObserver(protocol string, logger *zap.Logger) *Observer {
+	return &Observer{
+		protocol: protocol,
+		logger:   logger,
+	}
+}
+
+// Protocol returns the observed database protocol.
+func (o *Observer) Protocol() string {
+	return o.protocol
+}
+
+// Run periodically logs the observed command rate until ctx is done.
+func (o *Observer) Run(ctx context.Context) {
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	last := int64(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			total := o.commands.Load()
+			delta := total - last
+			last = total
+			if delta == 0 {
+				continue
+			}
+			o.logger.Info("Database activity",
+				zap.String("protocol", o.protocol),
+				zap.Float64("commands_per_second", float64(delta)/statsInterval.Seconds()),
+				zap.Int64("total_commands", total),
+			)
+		}
+	}
+}
+
+// ObserveConn logs a connection attempt and returns a per-connection parser
+// for the client-to-server byte stream.
+func (o *Observer) ObserveConn() *ConnObserver {
+	n := o.connects.Add(1)
+	o.logger.Info("Database connection attempt",
+		zap.String("protocol", o.protocol),
+		zap.Int64("total_connections", n),
+	)
+
+	co := &ConnObserver{observer: o}
+	switch o.protocol {
+	case ProtocolPostgres:
+		co.parser = &postgresParser{}
+	case ProtocolMySQL:
+		co.parser = &mysqlParser{}
+	case ProtocolRedis:
+		co.parser = newRedisParser()
+	}
+	return co
+}
+
+// ConnObserver follows a single connection's client-to-server stream.
+type ConnObserver struct {
+	observer *Observer
+	parser   clientParser
+	done     bool
+}
+
+// clientParser consumes client-to-server bytes and reports parsed events.
+type clientParser interface {
+	// feed consumes a chunk of the client stream. It returns the username
+	// when it becomes known ("" until then), the number of commands seen in
+	// this chunk, and stop=true when the stream can no longer be parsed
+	// (malformed data or a TLS upgrade making it opaque).
+	feed(p []byte) (user string, commands int, stop bool)
+}
+
+// Observe processes a chunk of client-to-server bytes.
+func (c *ConnObserver) Observe(p []byte) {
+	if c.parser == nil || c.done {
+		return
+	}
+
+	user, commands, stop := c.parser.feed(p)
+	if commands > 0 {
+		c.observer.commands.Add(int64(commands))
+	}
+	if user != "" {
+		c.observer.logger.Info("Database client identified",
+			zap.String("protocol", c.observer.protocol),
+			zap.String("user", user),
+		)
+	}
+	if stop {
+		c.done = true
+	}
+}
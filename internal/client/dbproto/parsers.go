@@ -0,0 +1,295 @@
+package dbproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+var crlf = []byte("\r\n")
+
+// consumeSkip discards bytes the parser decided not to inspect (message
+// bodies). It returns the remaining chunk and whether more input is needed.
+func consumeSkip(skip *int, data []byte) ([]byte, bool) {
+	n := len(data)
+	if n > *skip {
+		n = *skip
+	}
+	*skip -= n
+	return data[n:], *skip > 0
+}
+
+// Postgres wire protocol constants.
+const (
+	pgProtocolV3    = 196608
+	pgSSLRequest    = 80877103
+	pgGSSEncRequest = 80877104
+	pgMaxStartup    = 16 << 10
+)
+
+// postgresParser follows the Postgres frontend protocol: a startup message
+// carrying the username, then typed messages of which 'Q' (simple query)
+// and 'P' (parse) count as commands.
+type postgresParser struct {
+	buf     []byte
+	started bool
+	skip    int
+}
+
+func (p *postgresParser) feed(data []byte) (string, int, bool) {
+	var user string
+	var commands int
+
+	for {
+		if p.skip > 0 {
+			var more bool
+			data, more = consumeSkip(&p.skip, data)
+			if more {
+				return user, commands, false
+			}
+		}
+
+		p.buf = append(p.buf, data...)
+		data = nil
+
+		if !p.started {
+			if len(p.buf) < 8 {
+				return user, commands, false
+			}
+			msgLen := int(binary.BigEndian.Uint32(p.buf))
+			code := binary.BigEndian.Uint32(p.buf[4:8])
+			if code == pgSSLRequest || code == pgGSSEncRequest {
+				return user, commands, true // stream may turn opaque
+			}
+			if msgLen < 8 || msgLen > pgMaxStartup {
+				return user, commands, true
+			}
+			if len(p.buf) < msgLen {
+				return user, commands, false
+			}
+			if code == pgProtocolV3 {
+				user = startupParam(p.buf[8:msgLen], "user")
+			}
+			p.buf = append([]byte(nil), p.buf[msgLen:]...)
+			p.started = true
+			continue
+		}
+
+		// Regular message: 1-byte type + 4-byte length (includes itself).
+		if len(p.buf) < 5 {
+			return user, commands, false
+		}
+		typ := p.buf[0]
+		msgLen := int(binary.BigEndian.Uint32(p.buf[1:5]))
+		if msgLen < 4 {
+			return user, commands, true
+		}
+		if typ == 'Q' || typ == 'P' {
+			commands++
+		}
+		total := 1 + msgLen
+		if len(p.buf) >= total {
+			p.buf = append([]byte(nil), p.buf[total:]...)
+		} else {
+			p.skip = total - len(p.buf)
+			p.buf = p.buf[:0]
+		}
+	}
+}
+
+// startupParam extracts one key from the null-separated key/value pairs of a
+// Postgres startup message.
+func startupParam(params []byte, key string) string {
+	fields := bytes.Split(params, []byte{0})
+	for i := 0; i+1 < len(fields); i += 2 {
+		if string(fields[i]) == key {
+			return string(fields[i+1])
+		}
+	}
+	return ""
+}
+
+// MySQL capability flags (client-to-server handshake response).
+const (
+	mysqlClientProtocol41 = 0x0200
+	mysqlClientSSL        = 0x0800
+	mysqlMaxHandshake     = 16 << 10
+)
+
+// mysqlParser follows the MySQL client protocol: the first client packet is
+// the handshake response carrying the username; afterwards every packet with
+// sequence 0 starts a new command.
+type mysqlParser struct {
+	buf    []byte
+	authed bool
+	skip   int
+}
+
+func (m *mysqlParser) feed(data []byte) (string, int, bool) {
+	var user string
+	var commands int
+
+	for {
+		if m.skip > 0 {
+			var more bool
+			data, more = consumeSkip(&m.skip, data)
+			if more {
+				return user, commands, false
+			}
+		}
+
+		m.buf = append(m.buf, data...)
+		data = nil
+
+		// Packet header: 3-byte little-endian length + 1-byte sequence.
+		if len(m.buf) < 4 {
+			return user, commands, false
+		}
+		payloadLen := int(m.buf[0]) | int(m.buf[1])<<8 | int(m.buf[2])<<16
+		seq := m.buf[3]
+		total := 4 + payloadLen
+
+		if !m.authed {
+			if payloadLen > mysqlMaxHandshake {
+				return user, commands, true
+			}
+			if len(m.buf) < total {
+				return user, commands, false
+			}
+			payload := m.buf[4:total]
+			if len(payload) >= 2 {
+				caps := uint32(payload[0]) | uint32(payload[1])<<8
+				if caps&mysqlClientSSL != 0 && payloadLen <= 36 {
+					return user, commands, true // SSLRequest; stream turns opaque
+				}
+				// Protocol 4.1 layout: caps(4) max-packet(4) charset(1)
+				// reserved(23), then the null-terminated username.
+				if caps&mysqlClientProtocol41 != 0 && len(payload) > 32 {
+					user = cString(payload[32:])
+				}
+			}
+			m.authed = true
+			m.buf = append([]byte(nil), m.buf[total:]...)
+			continue
+		}
+
+		if seq == 0 {
+			commands++
+		}
+		if len(m.buf) >= total {
+			m.buf = append([]byte(nil), m.buf[total:]...)
+		} else {
+			m.skip = total - len(m.buf)
+			m.buf = m.buf[:0]
+		}
+	}
+}
+
+// cString returns the bytes up to the first null as a string.
+func cString(b []byte) string {
+	if idx := bytes.IndexByte(b, 0); idx >= 0 {
+		b = b[:idx]
+	}
+	return string(b)
+}
+
+// redisParser follows RESP: each "*N" multibulk (or inline line) from the
+// client is one command. The first two arguments are inspected so AUTH can
+// surface the username; larger payloads are skipped unread.
+type redisParser struct {
+	buf         []byte
+	skip        int
+	args        int // bulk arguments remaining in the current command
+	argc        int // total arguments of the current command
+	argIdx      int
+	cmd         string
+	wantPayload int // length of the next bulk payload to inspect, -1 for none
+}
+
+const redisMaxLine = 4096
+
+func newRedisParser() *redisParser {
+	return &redisParser{wantPayload: -1}
+}
+
+func (r *redisParser) feed(data []byte) (string, int, bool) {
+	var user string
+	var commands int
+
+	for {
+		if r.skip > 0 {
+			var more bool
+			data, more = consumeSkip(&r.skip, data)
+			if more {
+				return user, commands, false
+			}
+		}
+
+		r.buf = append(r.buf, data...)
+		data = nil
+
+		if r.wantPayload >= 0 {
+			need := r.wantPayload + 2 // payload + CRLF
+			if len(r.buf) < need {
+				return user, commands, false
+			}
+			arg := string(r.buf[:r.wantPayload])
+			r.buf = append([]byte(nil), r.buf[need:]...)
+			r.wantPayload = -1
+
+			if r.argIdx == 0 {
+				r.cmd = strings.ToUpper(arg)
+			} else if r.argIdx == 1 && r.cmd == "AUTH" && r.argc == 3 {
+				// AUTH <username> <password> (ACL-style)
+				user = arg
+			}
+			r.argIdx++
+			r.args--
+			continue
+		}
+
+		idx := bytes.Index(r.buf, crlf)
+		if idx < 0 {
+			if len(r.buf) > redisMaxLine {
+				return user, commands, true
+			}
+			return user, commands, false
+		}
+		line := r.buf[:idx]
+		r.buf = append([]byte(nil), r.buf[idx+2:]...)
+
+		if r.args == 0 {
+			if len(line) == 0 {
+				continue
+			}
+			if line[0] == '*' {
+				n, err := strconv.Atoi(string(line[1:]))
+				if err != nil || n < 0 {
+					return user, commands, true
+				}
+				commands++
+				r.args, r.argc, r.argIdx, r.cmd = n, n, 0, ""
+			} else {
+				commands++ // inline command
+			}
+			continue
+		}
+
+		// Expect a bulk string header for the next argument.
+		if len(line) == 0 || line[0] != '$' {
+			return user, commands, true
+		}
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil || n < 0 {
+			return user, commands, true
+		}
+		if r.argIdx < 2 && n <= 128 {
+			r.wantPayload = n
+		} else {
+			r.skip = n + 2
+			r.argIdx++
+			r.args--
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package grpcprobe discovers the gRPC services a local port exposes through
+// the standard reflection API. It speaks just enough HTTP/2 and protobuf wire
+// format for the single list-services call, avoiding a full gRPC dependency.
+package grpcprobe
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+)
+
+const reflectionPath = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
+
+// maxResponseSize bounds the reflection response we're willing to parse.
+const maxResponseSize = 1 << 20
+
+// ListServices asks the reflection service on addr (host:port) for its
+// exposed service names. It returns an error when the port does not speak
+// gRPC or has reflection disabled. The reflection service itself is omitted
+// from the result.
+func ListServices(ctx context.Context, addr string) ([]string, error) {
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	// ServerReflectionRequest{list_services: "*"} — field 7, length-delimited.
+	msg := []byte{0x3a, 0x01, '*'}
+	body := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(body[1:5], uint32(len(msg)))
+	body = append(body, msg...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+addr+reflectionPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("not a gRPC endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/grpc") {
+		return nil, fmt.Errorf("not a gRPC endpoint (content-type %q)", ct)
+	}
+
+	payload, err := readGRPCMessage(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reflection unavailable: %w", err)
+	}
+
+	services, err := parseListServices(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("reflection returned no services")
+	}
+	return services, nil
+}
+
+// readGRPCMessage reads one length-prefixed gRPC message from the body.
+func readGRPCMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	if prefix[0] != 0 {
+		return nil, fmt.Errorf("compressed response not supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxResponseSize {
+		return nil, fmt.Errorf("response too large (%d bytes)", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// parseListServices extracts service names from a ServerReflectionResponse:
+// list_services_response (field 6) holds repeated ServiceResponse (field 1),
+// each with a name (field 1).
+func parseListServices(msg []byte) ([]string, error) {
+	var services []string
+	err := walkFields(msg, func(num, wire int, data []byte) error {
+		if num != 6 || wire != 2 {
+			return nil
+		}
+		return walkFields(data, func(num, wire int, data []byte) error {
+			if num != 1 || wire != 2 {
+				return nil
+			}
+			return walkFields(data, func(num, wire int, data []byte) error {
+				if num == 1 && wire == 2 {
+					name := string(data)
+					if !strings.HasPrefix(name, "grpc.reflection.") {
+						services = append(services, name)
+					}
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("malformed reflection response: %w", err)
+	}
+	return services, nil
+}
+
+// walkFields iterates the top-level fields of a protobuf wire-format message,
+// calling fn with each field number, wire type, and raw payload.
+func walkFields(buf []byte, fn func(num, wire int, data []byte) error) error {
+	for len(buf) > 0 {
+		tag, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return fmt.Errorf("invalid field tag")
+		}
+		buf = buf[n:]
+
+		num, wire := int(tag>>3), int(tag&7)
+		var data []byte
+		switch wire {
+		case 0: // varint
+			_, n := binary.Uvarint(buf)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint")
+			}
+			data, buf = buf[:n], buf[n:]
+		case 1: // 64-bit
+			if len(buf) < 8 {
+				return fmt.Errorf("truncated fixed64")
+			}
+			data, buf = buf[:8], buf[8:]
+		case 2: // length-delimited
+			size, n := binary.Uvarint(buf)
+			if n <= 0 || uint64(len(buf)-n) < size {
+				return fmt.Errorf("truncated field")
+			}
+			data, buf = buf[n:n+int(size)], buf[n+int(size):]
+		case 5: // 32-bit
+			if len(buf) < 4 {
+				return fmt.Errorf("truncated fixed32")
+			}
+			data, buf = buf[:4], buf[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d", wire)
+		}
+
+		if err := fn(num, wire, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
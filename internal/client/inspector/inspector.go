@@ -0,0 +1,239 @@
+package inspector
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"drip/internal/client/capture"
+	"drip/internal/shared/httputil"
+
+	json "github.com/goccy/go-json"
+	"go.uber.org/zap"
+)
+
+// DefaultAddr is where the inspector tries to listen first; when the port
+// is taken (another tunnel's inspector, usually) an ephemeral port is used
+// instead.
+const DefaultAddr = "127.0.0.1:4040"
+
+const replayTimeout = 30 * time.Second
+
+// Server serves the local web inspector: a UI listing every captured HTTP
+// exchange through the tunnel (headers, bodies, timing) with replay of any
+// request against the local service.
+type Server struct {
+	buf       *capture.Buffer
+	replayURL string // scheme://host:port of the local service; "" disables replay
+	client    *http.Client
+	logger    *zap.Logger
+	listener  net.Listener
+	httpSrv   *http.Server
+}
+
+// New creates an inspector over the given capture buffer. replayURL is the
+// base URL of the local service replayed requests are sent to; pass ""
+// (e.g. for unix socket tunnels) to disable replay.
+func New(buf *capture.Buffer, replayURL string, logger *zap.Logger) *Server {
+	return &Server{
+		buf:       buf,
+		replayURL: strings.TrimSuffix(replayURL, "/"),
+		client: &http.Client{
+			Timeout: replayTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Start begins serving the inspector UI and returns its URL. DefaultAddr
+// is tried first; when taken, an ephemeral loopback port is used.
+func (s *Server) Start() (string, error) {
+	ln, err := net.Listen("tcp", DefaultAddr)
+	if err != nil {
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return "", fmt.Errorf("failed to start inspector: %w", err)
+		}
+	}
+	s.listener = ln
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.serveIndex)
+	mux.HandleFunc("/api/requests", s.serveList)
+	mux.HandleFunc("/api/requests/", s.serveEntry)
+
+	s.httpSrv = &http.Server{Handler: mux}
+	go func() {
+		if err := s.httpSrv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.logger.Debug("Inspector server stopped", zap.Error(err))
+		}
+	}()
+
+	return fmt.Sprintf("http://%s", ln.Addr().String()), nil
+}
+
+// Stop shuts the inspector down.
+func (s *Server) Stop() {
+	if s.httpSrv != nil {
+		_ = s.httpSrv.Close()
+	}
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(indexHTML))
+}
+
+// listEntry is the lightweight shape returned by the list endpoint; bodies
+// are only sent when one entry is fetched.
+type listEntry struct {
+	ID       uint64 `json:"id"`
+	Time     string `json:"time"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Status   int    `json:"status"`
+	Duration string `json:"duration"`
+	Size     int64  `json:"size"`
+	Replayed bool   `json:"replayed,omitempty"`
+}
+
+func (s *Server) serveList(w http.ResponseWriter, r *http.Request) {
+	limit := 200
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	entries := s.buf.List(capture.Filter{Limit: limit})
+	list := make([]listEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, listEntry{
+			ID:       e.ID,
+			Time:     e.Time.Format("15:04:05"),
+			Method:   e.Method,
+			Path:     e.Path,
+			Status:   e.Status,
+			Duration: e.Duration.Truncate(time.Millisecond).String(),
+			Size:     e.ResponseSize,
+			Replayed: e.Replayed,
+		})
+	}
+	writeJSON(w, list)
+}
+
+func (s *Server) serveEntry(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/requests/")
+	idStr, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid request id", http.StatusBadRequest)
+		return
+	}
+
+	entry, ok := s.buf.Get(id)
+	if !ok {
+		http.Error(w, "request no longer buffered", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, entry)
+	case action == "replay" && r.Method == http.MethodPost:
+		s.serveReplay(w, entry)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// serveReplay re-sends a captured request to the local service and records
+// the outcome as a new capture entry.
+func (s *Server) serveReplay(w http.ResponseWriter, entry *capture.Request) {
+	if s.replayURL == "" {
+		http.Error(w, "replay is not available for this tunnel", http.StatusNotImplemented)
+		return
+	}
+
+	req, err := http.NewRequest(entry.Method, s.replayURL+entry.Path, bytes.NewReader(entry.ReqBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	httputil.CopyHeaders(req.Header, entry.ReqHeader)
+	httputil.CleanHopByHopHeaders(req.Header)
+	req.ContentLength = int64(len(entry.ReqBody))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("local service unavailable: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	rec := &capture.BodyRecorder{}
+	n, _ := copyToRecorder(rec, resp)
+
+	replayed := &capture.Request{
+		Method:       entry.Method,
+		Path:         entry.Path,
+		Host:         entry.Host,
+		Status:       resp.StatusCode,
+		Duration:     time.Since(start),
+		ContentType:  resp.Header.Get("Content-Type"),
+		RequestSize:  int64(len(entry.ReqBody)),
+		ResponseSize: n,
+		ReqHeader:    entry.ReqHeader.Clone(),
+		RespHeader:   resp.Header.Clone(),
+		ReqBody:      entry.ReqBody,
+		RespBody:     rec.Bytes(),
+		Replayed:     true,
+	}
+	s.buf.Add(replayed)
+
+	s.logger.Info("Replayed request",
+		zap.String("method", entry.Method),
+		zap.String("path", entry.Path),
+		zap.Int("status", resp.StatusCode),
+	)
+	writeJSON(w, replayed)
+}
+
+// copyToRecorder drains a response body through a bounded recorder,
+// returning the full body length.
+func copyToRecorder(rec *capture.BodyRecorder, resp *http.Response) (int64, error) {
+	var total int64
+	buf := make([]byte, 32*1024)
+	for {
+		nr, err := resp.Body.Read(buf)
+		if nr > 0 {
+			total += int64(nr)
+			_, _ = rec.Write(buf[:nr])
+		}
+		if err != nil {
+			return total, nil
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(data)
+}
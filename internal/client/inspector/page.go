@@ -0,0 +1,112 @@
+package inspector
+
+// indexHTML is the single-page inspector UI. It polls the list endpoint,
+// shows one exchange's headers and bodies on selection, and replays the
+// selected request against the local service.
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8" />
+<title>Drip Inspector</title>
+<style>
+  body { font-family: -apple-system, 'Segoe UI', sans-serif; margin: 0; display: flex; height: 100vh; }
+  #list { width: 46%; overflow-y: auto; border-right: 1px solid #ddd; }
+  #detail { flex: 1; overflow-y: auto; padding: 16px; }
+  h1 { font-size: 16px; padding: 12px 16px; margin: 0; border-bottom: 1px solid #ddd; }
+  table { width: 100%; border-collapse: collapse; font-size: 13px; }
+  td, th { padding: 6px 10px; text-align: left; white-space: nowrap; }
+  th { color: #888; font-weight: normal; border-bottom: 1px solid #eee; }
+  tbody tr { cursor: pointer; }
+  tbody tr:hover, tr.selected { background: #f0f6ff; }
+  .ok { color: #1a7f37; } .warn { color: #9a6700; } .err { color: #cf222e; }
+  .replayed { color: #8250df; }
+  pre { background: #f6f8fa; padding: 10px; overflow-x: auto; font-size: 12px; white-space: pre-wrap; word-break: break-all; }
+  button { padding: 6px 14px; cursor: pointer; }
+  .muted { color: #888; }
+</style>
+</head>
+<body>
+<div id="list">
+  <h1>Drip Inspector</h1>
+  <table>
+    <thead><tr><th>Time</th><th>Method</th><th>Path</th><th>Status</th><th>Duration</th></tr></thead>
+    <tbody id="rows"></tbody>
+  </table>
+</div>
+<div id="detail"><p class="muted">Select a request to see headers, bodies and timing.</p></div>
+<script>
+let selected = null;
+
+function statusClass(s) {
+  if (s >= 500) return 'err';
+  if (s >= 400) return 'warn';
+  return 'ok';
+}
+
+function esc(s) {
+  return String(s).replace(/&/g, '&amp;').replace(/</g, '&lt;');
+}
+
+function decodeBody(b64) {
+  if (!b64) return '';
+  try { return atob(b64); } catch (e) { return '(binary body)'; }
+}
+
+function headerBlock(h) {
+  if (!h) return '(none)';
+  return Object.keys(h).sort().map(k => h[k].map(v => k + ': ' + v).join('\n')).join('\n');
+}
+
+async function refresh() {
+  const res = await fetch('/api/requests');
+  const list = await res.json();
+  const rows = document.getElementById('rows');
+  rows.innerHTML = '';
+  for (const r of list) {
+    const tr = document.createElement('tr');
+    if (r.id === selected) tr.className = 'selected';
+    tr.innerHTML = '<td class="muted">' + r.time + '</td>' +
+      '<td>' + esc(r.method) + (r.replayed ? ' <span class="replayed">↻</span>' : '') + '</td>' +
+      '<td>' + esc(r.path) + '</td>' +
+      '<td class="' + statusClass(r.status) + '">' + r.status + '</td>' +
+      '<td class="muted">' + r.duration + '</td>';
+    tr.onclick = () => show(r.id);
+    rows.appendChild(tr);
+  }
+}
+
+async function show(id) {
+  selected = id;
+  const res = await fetch('/api/requests/' + id);
+  if (!res.ok) return;
+  const r = await res.json();
+  document.getElementById('detail').innerHTML =
+    '<h2>' + esc(r.method) + ' ' + esc(r.path) +
+    ' <span class="' + statusClass(r.status) + '">' + r.status + '</span></h2>' +
+    '<p class="muted">' + new Date(r.time).toLocaleTimeString() + ' · ' +
+    (r.duration / 1e6).toFixed(1) + ' ms' + (r.replayed ? ' · replayed' : '') + '</p>' +
+    '<button onclick="replay(' + r.id + ')">Replay</button>' +
+    '<h3>Request headers</h3><pre>' + esc(headerBlock(r.req_header)) + '</pre>' +
+    '<h3>Request body</h3><pre>' + (esc(decodeBody(r.req_body)) || '(empty)') + '</pre>' +
+    '<h3>Response headers</h3><pre>' + esc(headerBlock(r.resp_header)) + '</pre>' +
+    '<h3>Response body</h3><pre>' + (esc(decodeBody(r.resp_body)) || '(empty)') + '</pre>';
+  refresh();
+}
+
+async function replay(id) {
+  const res = await fetch('/api/requests/' + id + '/replay', { method: 'POST' });
+  if (res.ok) {
+    const r = await res.json();
+    await refresh();
+    show(r.id);
+  } else {
+    alert(await res.text());
+  }
+}
+
+refresh();
+setInterval(refresh, 2000);
+</script>
+</body>
+</html>
+`
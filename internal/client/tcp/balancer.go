@@ -0,0 +1,122 @@
+package tcp
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Sticky session modes for multi-backend tunnels.
+const (
+	StickyModeNone   = ""
+	StickyModeCookie = "cookie"
+	StickyModeIP     = "ip"
+)
+
+// stickyCookieName is the cookie used to pin a visitor to a backend.
+const stickyCookieName = "drip_backend"
+
+// loadBalancer distributes tunneled requests across multiple local backends.
+// Without a sticky mode it round-robins; with "cookie" or "ip" stickiness the
+// same visitor keeps hitting the same backend so session-dependent apps
+// (without shared session stores) keep working through the tunnel.
+type loadBalancer struct {
+	targets []string // host:port entries
+	mode    string
+	next    atomic.Uint64
+}
+
+// newLoadBalancer creates a balancer over the given host:port targets.
+// Returns nil if fewer than two targets are configured.
+func newLoadBalancer(targets []string, mode string) *loadBalancer {
+	if len(targets) < 2 {
+		return nil
+	}
+	return &loadBalancer{
+		targets: targets,
+		mode:    mode,
+	}
+}
+
+// PickHTTP selects a backend for an HTTP request. setCookie reports whether
+// the caller should attach a sticky cookie to the response (cookie mode only,
+// when the request did not already carry one).
+func (b *loadBalancer) PickHTTP(req *http.Request) (target string, setCookie bool) {
+	switch b.mode {
+	case StickyModeCookie:
+		if c, err := req.Cookie(stickyCookieName); err == nil {
+			if idx, ok := b.cookieIndex(c.Value); ok {
+				return b.targets[idx], false
+			}
+		}
+		return b.targets[b.roundRobin()], true
+
+	case StickyModeIP:
+		ip := clientIP(req)
+		if ip != "" {
+			return b.targets[b.hashIndex(ip)], false
+		}
+		return b.targets[b.roundRobin()], false
+
+	default:
+		return b.targets[b.roundRobin()], false
+	}
+}
+
+// Pick selects a backend without request context (round-robin only).
+func (b *loadBalancer) Pick() string {
+	return b.targets[b.roundRobin()]
+}
+
+// CookieValue returns the sticky cookie value for a target.
+func (b *loadBalancer) CookieValue(target string) string {
+	h := fnv.New32a()
+	h.Write([]byte(target))
+	return hexDigits(h.Sum32())
+}
+
+func (b *loadBalancer) cookieIndex(value string) (int, bool) {
+	for i, t := range b.targets {
+		if b.CookieValue(t) == value {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (b *loadBalancer) roundRobin() int {
+	// Reduce before converting: int(counter) goes negative on 32-bit
+	// platforms once the counter's top bit is set.
+	return int((b.next.Add(1) - 1) % uint64(len(b.targets)))
+}
+
+func (b *loadBalancer) hashIndex(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(b.targets)))
+}
+
+// clientIP extracts the visitor IP from forwarding headers set by the server.
+func clientIP(req *http.Request) string {
+	if xff := req.Header.Get("X-Forwarded-For"); xff != "" {
+		if idx := strings.Index(xff, ","); idx != -1 {
+			return strings.TrimSpace(xff[:idx])
+		}
+		return strings.TrimSpace(xff)
+	}
+	if rip := req.Header.Get("X-Real-Ip"); rip != "" {
+		return strings.TrimSpace(rip)
+	}
+	return ""
+}
+
+func hexDigits(v uint32) string {
+	const digits = "0123456789abcdef"
+	buf := make([]byte, 8)
+	for i := 7; i >= 0; i-- {
+		buf[i] = digits[v&0xf]
+		v >>= 4
+	}
+	return string(buf)
+}
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"time"
 
+	"drip/internal/client/capture"
+	"drip/internal/shared/clock"
 	"drip/internal/shared/protocol"
 	"drip/internal/shared/stats"
 
@@ -12,6 +14,14 @@ import (
 
 type LatencyCallback func(latency time.Duration)
 
+// EventCallback receives server-side events (tunnel lifecycle, quota
+// warnings, admin notices) pushed over the control channel.
+type EventCallback func(ev protocol.Event)
+
+// RequestCallback receives one entry per proxied HTTP exchange as it
+// completes, used for the live request log (--inspect).
+type RequestCallback func(r *capture.Request)
+
 type ConnectorConfig struct {
 	ServerAddr string
 	Token      string
@@ -21,9 +31,117 @@ type ConnectorConfig struct {
 	Subdomain  string
 	Insecure   bool
 
+	// Transport selects how the control channel reaches the server: "tls"
+	// (the default, TCP with TLS 1.3) or "quic", which runs the channel
+	// over a single QUIC stream. The server only answers QUIC when started
+	// with --quic-port.
+	Transport string
+
+	// RemotePort requests a fixed public port for TCP/UDP tunnels. The
+	// server pins it to this token, so restarts get the same port back;
+	// a port pinned by someone else is a registration error.
+	RemotePort int
+
+	// Alias optionally claims a stable name (resolved server-side) that
+	// always points at this token's current tunnel, even across restarts
+	// with a fresh random subdomain.
+	Alias string
+
+	// CustomDomain routes a hostname CNAMEd at the drip server (e.g.
+	// app.example.com) to this tunnel, with on-demand certificate issuance
+	// on AutoTLS servers. HTTP/HTTPS only.
+	CustomDomain string
+
+	// LocalTargets optionally lists additional host:port backends. When two
+	// or more are set the client balances requests across them; StickyMode
+	// ("cookie" or "ip") pins each visitor to one backend.
+	LocalTargets []string
+	StickyMode   string
+
+	// DBProtocol enables protocol-aware observation for database tunnels
+	// ("postgres", "mysql", or "redis"): connection attempts, client
+	// usernames, and command rates are logged without capturing payloads.
+	DBProtocol string
+
+	// DBReadOnly blocks write statements/commands at the protocol level,
+	// turning the tunneled database read-only for remote clients. Requires
+	// DBProtocol.
+	DBReadOnly bool
+
+	// Services optionally labels the tunnel with the service names the local
+	// port exposes (e.g. from gRPC reflection); shown in drip list and the
+	// server's /stats endpoint.
+	Services []string
+
 	PoolSize int
 	PoolMin  int
 	PoolMax  int
+
+	// ExtraTunnels registers additional subdomains over the same control
+	// connection, each routed to its own local port by matching the
+	// request's Host. HTTP/HTTPS only.
+	ExtraTunnels []protocol.ExtraTunnel
+
+	// Redirects are server-side redirect rules evaluated before requests
+	// reach the tunnel; ForceTrailingSlash redirects extensionless paths
+	// to path + "/". Useful for patching URL issues in shared demos.
+	Redirects          []protocol.RedirectRule
+	ForceTrailingSlash bool
+
+	// Overrides answer matching request paths with canned responses instead
+	// of forwarding them to the local service. HTTP/HTTPS only.
+	Overrides []ResponseOverride
+
+	// WebhookProvider enables signature verification for incoming webhooks
+	// ("stripe", "github", or "slack"). Captured requests are tagged with the
+	// outcome; WebhookReject refuses invalid signatures with 401 before they
+	// reach the local app.
+	WebhookProvider string
+	WebhookSecret   string
+	WebhookReject   bool
+
+	// DialRetry, when positive, retries refused local dials with small
+	// backoff for this window before failing the request. Hot-reload tools
+	// (nodemon, air) close the port briefly between restarts; retrying
+	// smooths those over instead of answering 502 for every in-flight
+	// request.
+	DialRetry time.Duration
+
+	// MaxConcurrent bounds simultaneous in-flight requests (or TCP
+	// connections) forwarded to the local service. Excess requests queue
+	// briefly, then are refused with 503, so a fragile dev server isn't
+	// flattened when its URL leaks to a crawler. 0 means unlimited.
+	MaxConcurrent int
+
+	// WebhookDedupe, when positive, suppresses duplicate webhook deliveries
+	// (same provider delivery ID header) seen within the window: duplicates
+	// are acknowledged with 200 without reaching the local app. Protects
+	// handlers from redelivery storms after reconnects.
+	WebhookDedupe time.Duration
+
+	// LocalSocket forwards traffic to a unix domain socket instead of a
+	// local TCP port. When set it takes precedence over LocalHost/LocalPort
+	// and --backend balancing does not apply.
+	LocalSocket string
+
+	// PipeStdio connects visitor bytes to this process's stdin/stdout
+	// instead of dialing a local port. One visitor is served at a time;
+	// used by drip pipe. TCP tunnels only.
+	PipeStdio bool
+
+	// NameStyle asks the server to pick the generated subdomain in a given
+	// style ("words" for names like quiet-otter-42). Ignored when Subdomain
+	// is set.
+	NameStyle string
+
+	// SocksUser and SocksPass are the credentials the public SOCKS5
+	// endpoint requires from visitors. SOCKS tunnels only.
+	SocksUser string
+	SocksPass string
+
+	// Clock drives the heartbeat and session-scaling timers. Nil uses the
+	// system clock; integration tests inject a fake to fast-forward.
+	Clock clock.Clock
 }
 
 type TunnelClient interface {
@@ -33,8 +151,11 @@ type TunnelClient interface {
 	GetURL() string
 	GetSubdomain() string
 	SetLatencyCallback(cb LatencyCallback)
+	SetEventCallback(cb EventCallback)
+	SetRequestCallback(cb RequestCallback)
 	GetLatency() time.Duration
 	GetStats() *stats.TrafficStats
+	SetCaptureBuffer(buf *capture.Buffer)
 	IsClosed() bool
 }
 
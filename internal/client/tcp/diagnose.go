@@ -0,0 +1,69 @@
+package tcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// diagnoseProbeTimeout bounds the plaintext probe used to spot captive
+// portals.
+const diagnoseProbeTimeout = 3 * time.Second
+
+// DiagnoseConnectError inspects a failed connect and, when the failure
+// matches a known environment problem (NXDOMAIN, captive-portal HTTP
+// interception), returns a specific hint for the user instead of leaving
+// them with a generic TLS error. Returns "" when no specific cause is
+// recognized.
+func DiagnoseConnectError(serverAddr string, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return fmt.Sprintf("The server hostname %q does not resolve (NXDOMAIN). Check the --server address and your DNS configuration.", dnsErr.Name)
+		}
+		return fmt.Sprintf("DNS lookup for %q failed: %v. Check your network's DNS configuration.", dnsErr.Name, dnsErr.Err)
+	}
+
+	msg := err.Error()
+	if strings.Contains(msg, "first record does not look like a TLS handshake") ||
+		strings.Contains(msg, "handshake failure") ||
+		strings.Contains(msg, "certificate") ||
+		strings.Contains(msg, "EOF") {
+		if looksLikeCaptivePortal(serverAddr) {
+			return "Something on this network answers HTTP where the server speaks TLS — likely a captive portal or intercepting proxy. Open a browser page to complete the portal login and retry."
+		}
+	}
+
+	return ""
+}
+
+// looksLikeCaptivePortal sends a plaintext HTTP request to the server port
+// and reports whether something answered with HTTP. The real server only
+// speaks TLS on this port, so an HTTP answer means interception.
+func looksLikeCaptivePortal(serverAddr string) bool {
+	conn, err := net.DialTimeout("tcp", serverAddr, diagnoseProbeTimeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(diagnoseProbeTimeout))
+
+	host := serverAddr
+	if h, _, err := net.SplitHostPort(serverAddr); err == nil {
+		host = h
+	}
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\nConnection: close\r\n\r\n", host)
+
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return false
+	}
+	return string(buf) == "HTTP/"
+}
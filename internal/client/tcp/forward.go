@@ -0,0 +1,197 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"go.uber.org/zap"
+
+	"drip/internal/shared/constants"
+	"drip/internal/shared/netutil"
+	"drip/internal/shared/pool"
+	"drip/internal/shared/protocol"
+	"drip/pkg/config"
+)
+
+// ForwardConfig configures a reverse/local-forward client (drip forward):
+// a local listener whose connections are piped into an existing tunnel on
+// the server, without needing the tunnel's public TCP port.
+type ForwardConfig struct {
+	ServerAddr string
+	Token      string
+	Subdomain  string // tunnel (or alias) to attach to
+	LocalHost  string // local bind address, defaults to 127.0.0.1
+	LocalPort  int    // local port to listen on
+	Insecure   bool
+}
+
+// ForwardClient accepts local connections and forwards each over its own
+// TLS connection to the server, which pipes it into the target tunnel.
+type ForwardClient struct {
+	cfg       *ForwardConfig
+	tlsConfig *tls.Config
+	listener  net.Listener
+	logger    *zap.Logger
+
+	stopCh chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewForwardClient creates a forward client. Call Start to begin listening.
+func NewForwardClient(cfg *ForwardConfig, logger *zap.Logger) *ForwardClient {
+	var tlsConfig *tls.Config
+	if cfg.Insecure {
+		tlsConfig = config.GetClientTLSConfigInsecure()
+	} else {
+		host, _, _ := net.SplitHostPort(cfg.ServerAddr)
+		tlsConfig = config.GetClientTLSConfig(host)
+	}
+
+	if cfg.LocalHost == "" {
+		cfg.LocalHost = "127.0.0.1"
+	}
+
+	return &ForwardClient{
+		cfg:       cfg,
+		tlsConfig: tlsConfig,
+		logger:    logger,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start opens the local listener and serves connections in the background.
+func (f *ForwardClient) Start() error {
+	addr := net.JoinHostPort(f.cfg.LocalHost, fmt.Sprintf("%d", f.cfg.LocalPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	f.listener = listener
+
+	f.wg.Add(1)
+	go f.acceptLoop()
+
+	return nil
+}
+
+// Addr returns the local listen address.
+func (f *ForwardClient) Addr() string {
+	if f.listener == nil {
+		return ""
+	}
+	return f.listener.Addr().String()
+}
+
+// Stop closes the listener and waits for in-flight connections to finish.
+func (f *ForwardClient) Stop() {
+	f.once.Do(func() {
+		close(f.stopCh)
+		if f.listener != nil {
+			f.listener.Close()
+		}
+	})
+	f.wg.Wait()
+}
+
+func (f *ForwardClient) acceptLoop() {
+	defer f.wg.Done()
+
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			select {
+			case <-f.stopCh:
+				return
+			default:
+				f.logger.Debug("Forward accept failed", zap.Error(err))
+				return
+			}
+		}
+
+		f.wg.Add(1)
+		go f.handleConn(conn)
+	}
+}
+
+func (f *ForwardClient) handleConn(local net.Conn) {
+	defer f.wg.Done()
+	defer local.Close()
+
+	remote, err := f.dialForward()
+	if err != nil {
+		f.logger.Warn("Forward connection failed", zap.Error(err))
+		return
+	}
+	defer remote.Close()
+
+	_ = netutil.PipeWithCallbacksAndBufferSize(
+		nil,
+		local,
+		remote,
+		pool.SizeLarge,
+		nil,
+		nil,
+	)
+}
+
+// dialForward connects to the server, attaches to the target tunnel, and
+// returns the connection ready for raw piping.
+func (f *ForwardClient) dialForward() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", f.cfg.ServerAddr, f.tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	state := conn.ConnectionState()
+	if state.Version != tls.VersionTLS13 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("server not using TLS 1.3 (version: 0x%04x)", state.Version)
+	}
+
+	req := protocol.ForwardRequest{
+		Token:     f.cfg.Token,
+		Subdomain: f.cfg.Subdomain,
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if err := protocol.WriteFrame(conn, protocol.NewFrame(protocol.FrameTypeForward, payload)); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to send forward request: %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(constants.RequestTimeout))
+	ack, err := protocol.ReadFrame(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to read forward ack: %w", err)
+	}
+	defer ack.Release()
+	_ = conn.SetReadDeadline(time.Time{})
+
+	if ack.Type != protocol.FrameTypeForwardAck {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected forward ack frame: %s", ack.Type)
+	}
+
+	var resp protocol.ForwardResponse
+	if err := json.Unmarshal(ack.Payload, &resp); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to parse forward response: %w", err)
+	}
+	if !resp.Accepted {
+		_ = conn.Close()
+		return nil, fmt.Errorf("forward rejected: %s", resp.Message)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,64 @@
+package tcp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"drip/internal/shared/httputil"
+)
+
+// ResponseOverride answers matching requests directly from the client with a
+// canned response instead of forwarding them to the local service. Handy
+// when part of the stack isn't running during a demo (e.g. a payment
+// callback that should just get 200).
+type ResponseOverride struct {
+	// Path to match exactly; when Prefix is set it matches any path
+	// underneath it instead.
+	Path   string
+	Prefix bool
+
+	Status      int
+	ContentType string
+	Body        string
+}
+
+// match reports whether the request path is covered by this override.
+func (o *ResponseOverride) match(path string) bool {
+	if o.Prefix {
+		return strings.HasPrefix(path, o.Path)
+	}
+	return path == o.Path
+}
+
+// write sends the canned response on the stream.
+func (o *ResponseOverride) write(w io.Writer) {
+	resp := &http.Response{
+		StatusCode:    o.Status,
+		Status:        fmt.Sprintf("%d %s", o.Status, http.StatusText(o.Status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        httputil.GetHeader(),
+		Body:          io.NopCloser(strings.NewReader(o.Body)),
+		ContentLength: int64(len(o.Body)),
+		Close:         true,
+	}
+	resp.Header.Set("Content-Type", o.ContentType)
+	resp.Header.Set("Content-Length", strconv.Itoa(len(o.Body)))
+	_ = resp.Write(w)
+	_ = resp.Body.Close()
+	httputil.PutHeader(resp.Header)
+}
+
+// matchOverride returns the first override covering path, or nil.
+func (c *PoolClient) matchOverride(path string) *ResponseOverride {
+	for i := range c.overrides {
+		if c.overrides[i].match(path) {
+			return &c.overrides[i]
+		}
+	}
+	return nil
+}
@@ -2,9 +2,10 @@ package tcp
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"runtime"
@@ -16,7 +17,12 @@ import (
 	"github.com/hashicorp/yamux"
 	"go.uber.org/zap"
 
+	"drip/internal/client/capture"
+	"drip/internal/client/dbproto"
+	"drip/internal/client/webhook"
+	"drip/internal/shared/clock"
 	"drip/internal/shared/constants"
+	"drip/internal/shared/netutil"
 	"drip/internal/shared/protocol"
 	"drip/internal/shared/stats"
 	"drip/pkg/config"
@@ -26,11 +32,30 @@ import (
 type PoolClient struct {
 	serverAddr string
 	tlsConfig  *tls.Config
+	transport  string
 	token      string
 	tunnelType protocol.TunnelType
 	localHost  string
 	localPort  int
+	remotePort int
 	subdomain  string
+	nameStyle  string
+	instanceID string
+	services   []string
+	alias        string
+	customDomain string
+	redirects    []protocol.RedirectRule
+	forceSlash bool
+	extras     []protocol.ExtraTunnel
+	extraPorts map[string]int
+
+	tcpMultiPort bool // TCP tunnel with extras: streams carry a local-port header
+
+	socksUser string // SOCKS5 credentials enforced by the server's public endpoint
+	socksPass string
+
+	pipeStdio bool       // serve visitors from stdin/stdout (drip pipe)
+	pipeMu    sync.Mutex // held by the visitor currently owning stdio
 
 	assignedURL string
 	tunnelID    string
@@ -46,6 +71,28 @@ type PoolClient struct {
 	latencyCallback atomic.Value // LatencyCallback
 	latencyNanos    atomic.Int64
 
+	eventCallback atomic.Value // EventCallback
+
+	requestCallback atomic.Value // RequestCallback
+
+	capture atomic.Value // *capture.Buffer, nil when capture is disabled
+
+	webhook *webhook.Verifier // nil when webhook verification is disabled
+	deduper *webhook.Deduper  // nil when webhook deduplication is disabled
+
+	concSem chan struct{} // nil when --max-concurrent is unset
+
+	dialRetry time.Duration // 0 when --dial-retry is unset
+
+	localSocket string // unix socket path; overrides localHost/localPort when set
+
+	clock clock.Clock // heartbeat and scaler timers; tests inject a fake
+
+	overrides []ResponseOverride // empty when --override is unset
+
+	dbObserver *dbproto.Observer // nil when database observation is disabled
+	dbGuard    *dbproto.Guard    // nil when read-only guard mode is disabled
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -57,6 +104,8 @@ type PoolClient struct {
 
 	primary *sessionHandle
 
+	balancer *loadBalancer
+
 	mu           sync.RWMutex
 	dataSessions map[string]*sessionHandle
 	desiredTotal int
@@ -65,6 +114,15 @@ type PoolClient struct {
 	logger *zap.Logger
 }
 
+// newInstanceID generates a random identifier for this client instance.
+// The server uses it to recognize registration retries from the same
+// process and re-attach instead of rejecting the subdomain as taken.
+func newInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // NewPoolClient creates a new pool client.
 func NewPoolClient(cfg *ConnectorConfig, logger *zap.Logger) *PoolClient {
 	var tlsConfig *tls.Config
@@ -111,11 +169,24 @@ func NewPoolClient(cfg *ConnectorConfig, logger *zap.Logger) *PoolClient {
 	c := &PoolClient{
 		serverAddr:      cfg.ServerAddr,
 		tlsConfig:       tlsConfig,
+		transport:       cfg.Transport,
 		token:           cfg.Token,
 		tunnelType:      tunnelType,
 		localHost:       localHost,
 		localPort:       cfg.LocalPort,
+		remotePort:      cfg.RemotePort,
 		subdomain:       cfg.Subdomain,
+		nameStyle:       cfg.NameStyle,
+		instanceID:      newInstanceID(),
+		services:        cfg.Services,
+		alias:           cfg.Alias,
+		customDomain:    cfg.CustomDomain,
+		redirects:       cfg.Redirects,
+		forceSlash:      cfg.ForceTrailingSlash,
+		extras:          cfg.ExtraTunnels,
+		socksUser:       cfg.SocksUser,
+		socksPass:       cfg.SocksPass,
+		pipeStdio:       cfg.PipeStdio,
 		minSessions:     minSessions,
 		maxSessions:     maxSessions,
 		initialSessions: initialSessions,
@@ -125,31 +196,95 @@ func NewPoolClient(cfg *ConnectorConfig, logger *zap.Logger) *PoolClient {
 		stopCh:          make(chan struct{}),
 		doneCh:          make(chan struct{}),
 		dataSessions:    make(map[string]*sessionHandle),
+		dialRetry:       cfg.DialRetry,
+		localSocket:     cfg.LocalSocket,
+		clock:           cfg.Clock,
+		overrides:       cfg.Overrides,
+		balancer:        newLoadBalancer(cfg.LocalTargets, cfg.StickyMode),
 		logger:          logger,
 	}
 
+	if c.clock == nil {
+		c.clock = clock.System
+	}
+
 	if tunnelType == protocol.TunnelTypeHTTP || tunnelType == protocol.TunnelTypeHTTPS {
-		c.httpClient = newLocalHTTPClient(tunnelType)
+		c.httpClient = newLocalHTTPClient(tunnelType, cfg.DialRetry, cfg.LocalSocket)
+	}
+
+	if len(cfg.ExtraTunnels) > 0 {
+		switch tunnelType {
+		case protocol.TunnelTypeHTTP, protocol.TunnelTypeHTTPS:
+			// HTTP extras are routed in-band by the Host header.
+			c.extraPorts = make(map[string]int, len(cfg.ExtraTunnels))
+			for _, extra := range cfg.ExtraTunnels {
+				c.extraPorts[extra.Subdomain] = extra.LocalPort
+			}
+		case protocol.TunnelTypeTCP:
+			// TCP extras have no in-band routing; the server prefixes every
+			// stream with the local port instead.
+			c.tcpMultiPort = true
+		}
+	}
+
+	if cfg.DBProtocol != "" && tunnelType == protocol.TunnelTypeTCP {
+		c.dbObserver = dbproto.NewObserver(cfg.DBProtocol, logger)
+		go c.dbObserver.Run(ctx)
+		if cfg.DBReadOnly {
+			c.dbGuard = dbproto.NewGuard(cfg.DBProtocol, logger)
+		}
+	}
+
+	if cfg.WebhookProvider != "" {
+		verifier, err := webhook.NewVerifier(cfg.WebhookProvider, cfg.WebhookSecret, cfg.WebhookReject)
+		if err != nil {
+			// The CLI validates provider and secret before building the
+			// config; log and continue without verification just in case.
+			logger.Warn("Webhook verification disabled", zap.Error(err))
+		} else {
+			c.webhook = verifier
+		}
+	}
+
+	if cfg.WebhookDedupe > 0 {
+		c.deduper = webhook.NewDeduper(cfg.WebhookDedupe)
+	}
+
+	if cfg.MaxConcurrent > 0 {
+		c.concSem = make(chan struct{}, cfg.MaxConcurrent)
 	}
 
 	c.latencyCallback.Store(LatencyCallback(func(time.Duration) {}))
+	c.eventCallback.Store(EventCallback(func(protocol.Event) {}))
+	c.requestCallback.Store(RequestCallback(func(*capture.Request) {}))
 	return c
 }
 
 // Connect establishes the primary connection and starts background workers.
 func (c *PoolClient) Connect() error {
-	primaryConn, err := c.dialTLS()
+	primaryConn, err := c.dialControl()
 	if err != nil {
 		return err
 	}
 
 	maxData := max(c.maxSessions-1, 0)
 	req := protocol.RegisterRequest{
-		Token:           c.token,
-		CustomSubdomain: c.subdomain,
-		TunnelType:      c.tunnelType,
-		LocalPort:       c.localPort,
-		ConnectionType:  "primary",
+		Token:              c.token,
+		CustomSubdomain:    c.subdomain,
+		NameStyle:          c.nameStyle,
+		TunnelType:         c.tunnelType,
+		LocalPort:          c.localPort,
+		RemotePort:         c.remotePort,
+		InstanceID:         c.instanceID,
+		Services:           c.services,
+		Alias:              c.alias,
+		CustomDomain:       c.customDomain,
+		SocksUser:          c.socksUser,
+		SocksPass:          c.socksPass,
+		Redirects:          c.redirects,
+		ForceTrailingSlash: c.forceSlash,
+		ExtraTunnels:       c.extras,
+		ConnectionType:     "primary",
 		PoolCapabilities: &protocol.PoolCapabilities{
 			MaxDataConns: maxData,
 			Version:      1,
@@ -176,6 +311,48 @@ func (c *PoolClient) Connect() error {
 	defer ack.Release()
 	_ = primaryConn.SetReadDeadline(time.Time{})
 
+	if ack.Type == protocol.FrameTypePowChallenge {
+		// Anonymous servers may gate registration behind a proof-of-work.
+		// Solve the challenge and retry once on a fresh connection; the
+		// usual ack handling below applies to the retry.
+		var challenge protocol.PowChallenge
+		perr := json.Unmarshal(ack.Payload, &challenge)
+		_ = primaryConn.Close()
+		if perr != nil {
+			return fmt.Errorf("failed to parse pow challenge: %w", perr)
+		}
+
+		c.logger.Info("Solving registration proof-of-work",
+			zap.Int("difficulty", challenge.Difficulty))
+		req.PowChallenge = challenge.Challenge
+		req.PowNonce = protocol.SolvePow(challenge.Challenge, challenge.Difficulty)
+
+		primaryConn, err = c.dialControl()
+		if err != nil {
+			return err
+		}
+
+		retryPayload, err := json.Marshal(req)
+		if err != nil {
+			_ = primaryConn.Close()
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		if err := protocol.WriteFrame(primaryConn, protocol.NewFrame(protocol.FrameTypeRegister, retryPayload)); err != nil {
+			_ = primaryConn.Close()
+			return fmt.Errorf("failed to send registration: %w", err)
+		}
+
+		_ = primaryConn.SetReadDeadline(time.Now().Add(constants.RequestTimeout))
+		retryAck, err := protocol.ReadFrame(primaryConn)
+		if err != nil {
+			_ = primaryConn.Close()
+			return fmt.Errorf("failed to read register ack: %w", err)
+		}
+		defer retryAck.Release()
+		_ = primaryConn.SetReadDeadline(time.Time{})
+		ack = retryAck
+	}
+
 	if ack.Type == protocol.FrameTypeError {
 		var errMsg protocol.ErrorMessage
 		if e := json.Unmarshal(ack.Payload, &errMsg); e == nil {
@@ -201,13 +378,11 @@ func (c *PoolClient) Connect() error {
 	if resp.SupportsDataConn && resp.TunnelID != "" {
 		c.tunnelID = resp.TunnelID
 	}
+	for _, extraURL := range resp.ExtraURLs {
+		c.logger.Info("Extra tunnel registered", zap.String("url", extraURL))
+	}
 
-	yamuxCfg := yamux.DefaultConfig()
-	yamuxCfg.EnableKeepAlive = false
-	yamuxCfg.LogOutput = io.Discard
-	yamuxCfg.AcceptBacklog = constants.YamuxAcceptBacklog
-
-	session, err := yamux.Server(primaryConn, yamuxCfg)
+	session, err := yamux.Server(primaryConn, netutil.YamuxConfig())
 	if err != nil {
 		_ = primaryConn.Close()
 		return fmt.Errorf("failed to init yamux session: %w", err)
@@ -236,6 +411,9 @@ func (c *PoolClient) Connect() error {
 	c.wg.Add(1)
 	go c.pingLoop(primary)
 
+	c.wg.Add(1)
+	go c.eventLoop(primary)
+
 	if c.tunnelID != "" {
 		c.mu.Lock()
 		c.desiredTotal = c.initialSessions
@@ -255,28 +433,15 @@ func (c *PoolClient) Connect() error {
 	return nil
 }
 
-func (c *PoolClient) dialTLS() (net.Conn, error) {
-	dialer := &net.Dialer{Timeout: 10 * time.Second}
-	conn, err := tls.DialWithDialer(dialer, "tcp", c.serverAddr, c.tlsConfig)
+// dialControl resolves the configured transport and dials one control
+// connection over it. Registration, heartbeat, and stats logic are
+// transport-agnostic from here on.
+func (c *PoolClient) dialControl() (net.Conn, error) {
+	transport, err := newTransport(c.transport, c.tlsConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %w", err)
-	}
-
-	state := conn.ConnectionState()
-	if state.Version != tls.VersionTLS13 {
-		_ = conn.Close()
-		return nil, fmt.Errorf("server not using TLS 1.3 (version: 0x%04x)", state.Version)
-	}
-
-	if tcpConn, ok := conn.NetConn().(*net.TCPConn); ok {
-		_ = tcpConn.SetNoDelay(true)
-		_ = tcpConn.SetKeepAlive(true)
-		_ = tcpConn.SetKeepAlivePeriod(30 * time.Second)
-		_ = tcpConn.SetReadBuffer(256 * 1024)
-		_ = tcpConn.SetWriteBuffer(256 * 1024)
+		return nil, err
 	}
-
-	return conn, nil
+	return transport.Dial(c.serverAddr)
 }
 
 func (c *PoolClient) acceptLoop(h *sessionHandle, isPrimary bool) {
@@ -336,7 +501,7 @@ func (c *PoolClient) pingLoop(h *sessionHandle) {
 
 	const maxConsecutiveFailures = 3
 
-	ticker := time.NewTicker(constants.HeartbeatInterval)
+	ticker := c.clock.NewTicker(constants.HeartbeatInterval)
 	defer ticker.Stop()
 
 	consecutiveFailures := 0
@@ -345,7 +510,7 @@ func (c *PoolClient) pingLoop(h *sessionHandle) {
 		select {
 		case <-c.stopCh:
 			return
-		case <-ticker.C:
+		case <-ticker.Chan():
 		}
 
 		if h.session == nil || h.session.IsClosed() {
@@ -442,9 +607,93 @@ func (c *PoolClient) GetLatency() time.Duration          { return time.Duration(
 func (c *PoolClient) GetStats() *stats.TrafficStats      { return c.stats }
 func (c *PoolClient) IsClosed() bool                     { return c.closed.Load() }
 
+// SetCaptureBuffer attaches a request capture buffer; nil disables capture.
+func (c *PoolClient) SetCaptureBuffer(buf *capture.Buffer) { c.capture.Store(buf) }
+
+// captureBuffer returns the attached capture buffer, or nil.
+func (c *PoolClient) captureBuffer() *capture.Buffer {
+	if v := c.capture.Load(); v != nil {
+		return v.(*capture.Buffer)
+	}
+	return nil
+}
+
 func (c *PoolClient) SetLatencyCallback(cb LatencyCallback) {
 	if cb == nil {
 		cb = func(time.Duration) {}
 	}
 	c.latencyCallback.Store(cb)
 }
+
+func (c *PoolClient) SetEventCallback(cb EventCallback) {
+	if cb == nil {
+		cb = func(protocol.Event) {}
+	}
+	c.eventCallback.Store(cb)
+}
+
+func (c *PoolClient) SetRequestCallback(cb RequestCallback) {
+	if cb == nil {
+		cb = func(*capture.Request) {}
+	}
+	c.requestCallback.Store(cb)
+}
+
+// recordCapture stores one proxied exchange in the capture buffer (when
+// attached) and hands it to the per-request callback.
+func (c *PoolClient) recordCapture(r *capture.Request) {
+	if buf := c.captureBuffer(); buf != nil {
+		buf.Add(r)
+	}
+	if cb, ok := c.requestCallback.Load().(RequestCallback); ok && cb != nil {
+		cb(r)
+	}
+}
+
+// eventLoop opens a subscription stream on the primary session and feeds
+// server-pushed events to the registered callback. Events are best-effort:
+// if the stream cannot be opened or dies, the tunnel keeps working without
+// them.
+func (c *PoolClient) eventLoop(h *sessionHandle) {
+	defer c.wg.Done()
+
+	stream, err := h.session.Open()
+	if err != nil {
+		c.logger.Debug("Failed to open event stream", zap.Error(err))
+		return
+	}
+	defer stream.Close()
+
+	if err := protocol.WriteFrame(stream, protocol.NewFrame(protocol.FrameTypeEventSub, nil)); err != nil {
+		c.logger.Debug("Failed to subscribe to events", zap.Error(err))
+		return
+	}
+
+	go func() {
+		<-c.stopCh
+		_ = stream.Close()
+	}()
+
+	for {
+		frame, err := protocol.ReadFrame(stream)
+		if err != nil {
+			return
+		}
+		if frame.Type != protocol.FrameTypeEvent {
+			frame.Release()
+			continue
+		}
+
+		var ev protocol.Event
+		unmarshalErr := json.Unmarshal(frame.Payload, &ev)
+		frame.Release()
+		if unmarshalErr != nil {
+			c.logger.Debug("Failed to parse event", zap.Error(unmarshalErr))
+			continue
+		}
+
+		if cb, ok := c.eventCallback.Load().(EventCallback); ok && cb != nil {
+			cb(ev)
+		}
+	}
+}
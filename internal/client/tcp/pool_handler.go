@@ -2,14 +2,24 @@ package tcp
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"drip/internal/client/capture"
+	"drip/internal/client/dbproto"
+	"drip/internal/client/webhook"
 	"drip/internal/shared/httputil"
 	"drip/internal/shared/netutil"
 	"drip/internal/shared/pool"
@@ -29,13 +39,159 @@ func (c *PoolClient) handleStream(h *sessionHandle, stream net.Conn) {
 	switch c.tunnelType {
 	case protocol.TunnelTypeHTTP, protocol.TunnelTypeHTTPS:
 		c.handleHTTPStream(stream)
+	case protocol.TunnelTypeUDP:
+		c.handleUDPStream(stream)
+	case protocol.TunnelTypeSOCKS:
+		c.handleSOCKSStream(stream)
 	default:
 		c.handleTCPStream(stream)
 	}
 }
 
+// localAddr returns the default local backend address.
+func (c *PoolClient) localAddr() string {
+	return net.JoinHostPort(c.localHost, fmt.Sprintf("%d", c.localPort))
+}
+
+// backendFor selects the local backend for an HTTP request. setCookie reports
+// whether a sticky cookie should be attached to the response.
+func (c *PoolClient) backendFor(req *http.Request) (addr string, setCookie bool) {
+	if addr, ok := c.extraBackend(req.Host); ok {
+		return addr, false
+	}
+	if c.balancer == nil {
+		return c.localAddr(), false
+	}
+	return c.balancer.PickHTTP(req)
+}
+
+// extraBackend maps the request's Host to the local port of an extra
+// subdomain, when this client serves several subdomains over one connection.
+func (c *PoolClient) extraBackend(host string) (string, bool) {
+	if len(c.extraPorts) == 0 {
+		return "", false
+	}
+	if i := strings.Index(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	sub, _, _ := strings.Cut(host, ".")
+	port, ok := c.extraPorts[sub]
+	if !ok {
+		return "", false
+	}
+	return net.JoinHostPort(c.localHost, strconv.Itoa(port)), true
+}
+
+// dialLocalRetry dials the local service, retrying refused connections with
+// small backoff until the window elapses. Hot-reload tools close the port
+// briefly between restarts; retrying smooths those over instead of failing
+// every in-flight request.
+func dialLocalRetry(ctx context.Context, network, addr string, window time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   3 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	conn, err := dialer.DialContext(ctx, network, addr)
+	if err == nil || window <= 0 || !isConnRefused(err) {
+		return conn, err
+	}
+
+	deadline := time.Now().Add(window)
+	backoff := 50 * time.Millisecond
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+
+		conn, err = dialer.DialContext(ctx, network, addr)
+		if err == nil || !isConnRefused(err) {
+			return conn, err
+		}
+		if backoff < 500*time.Millisecond {
+			backoff *= 2
+		}
+	}
+	return nil, err
+}
+
+func isConnRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// dialLocal connects to the local backend. A configured unix socket takes
+// precedence over the TCP address.
+func (c *PoolClient) dialLocal(ctx context.Context, addr string) (net.Conn, error) {
+	if c.localSocket != "" {
+		return dialLocalRetry(ctx, "unix", c.localSocket, c.dialRetry)
+	}
+	return dialLocalRetry(ctx, "tcp", addr, c.dialRetry)
+}
+
+// concQueueTimeout is how long a request waits for a free --max-concurrent
+// slot before being refused.
+const concQueueTimeout = 5 * time.Second
+
+// acquireSlot reserves an in-flight slot when --max-concurrent is set,
+// queueing up to concQueueTimeout for one to free up.
+func (c *PoolClient) acquireSlot() bool {
+	if c.concSem == nil {
+		return true
+	}
+
+	select {
+	case c.concSem <- struct{}{}:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(concQueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case c.concSem <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+func (c *PoolClient) releaseSlot() {
+	if c.concSem != nil {
+		<-c.concSem
+	}
+}
+
 func (c *PoolClient) handleTCPStream(stream net.Conn) {
-	localConn, err := net.DialTimeout("tcp", net.JoinHostPort(c.localHost, fmt.Sprintf("%d", c.localPort)), 10*time.Second)
+	if c.pipeStdio {
+		c.handlePipeStream(stream)
+		return
+	}
+
+	if !c.acquireSlot() {
+		return
+	}
+	defer c.releaseSlot()
+
+	target := c.localAddr()
+	if c.tcpMultiPort {
+		// Multi-port tunnels prefix each stream with the destination local
+		// port; without it we cannot tell which service the visitor wants.
+		port, err := readPortHeader(stream)
+		if err != nil {
+			c.logger.Debug("Read port header failed", zap.Error(err))
+			return
+		}
+		target = net.JoinHostPort(c.localHost, strconv.Itoa(port))
+	} else if c.balancer != nil {
+		target = c.balancer.Pick()
+	}
+
+	localConn, err := c.dialLocal(c.ctx, target)
 	if err != nil {
 		c.logger.Debug("Dial local failed", zap.Error(err))
 		return
@@ -50,6 +206,81 @@ func (c *PoolClient) handleTCPStream(stream net.Conn) {
 		_ = tcpConn.SetWriteBuffer(256 * 1024)
 	}
 
+	src := net.Conn(stream)
+	if c.dbGuard != nil {
+		src = &guardedStream{Conn: stream, guard: c.dbGuard.NewConn()}
+	}
+	if c.dbObserver != nil {
+		src = &observedStream{Conn: src, observer: c.dbObserver.ObserveConn()}
+	}
+
+	_ = netutil.PipeWithCallbacksAndBufferSize(
+		c.ctx,
+		src,
+		localConn,
+		pool.SizeLarge,
+		func(n int64) { c.stats.AddBytesIn(n) },
+		func(n int64) { c.stats.AddBytesOut(n) },
+	)
+}
+
+// handlePipeStream connects one visitor to this process's stdin/stdout for
+// drip pipe. Visitors are served strictly one at a time; while one holds
+// the pipe, newcomers are disconnected. As with netcat, a departed
+// visitor's pending stdin read may consume one chunk before its write
+// fails.
+func (c *PoolClient) handlePipeStream(stream net.Conn) {
+	if !c.pipeMu.TryLock() {
+		return
+	}
+	defer c.pipeMu.Unlock()
+
+	go func() {
+		n, _ := io.Copy(stream, os.Stdin)
+		c.stats.AddBytesOut(n)
+	}()
+
+	n, _ := io.Copy(os.Stdout, stream)
+	c.stats.AddBytesIn(n)
+}
+
+// handleSOCKSStream serves one SOCKS CONNECT relayed by the server: the
+// stream starts with the target as a length-prefixed host:port, we answer
+// with one status byte once the dial settles, then pipe.
+func (c *PoolClient) handleSOCKSStream(stream net.Conn) {
+	if !c.acquireSlot() {
+		return
+	}
+	defer c.releaseSlot()
+
+	_ = stream.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var n [1]byte
+	if _, err := io.ReadFull(stream, n[:]); err != nil {
+		c.logger.Debug("Read SOCKS target failed", zap.Error(err))
+		return
+	}
+	target := make([]byte, int(n[0]))
+	if _, err := io.ReadFull(stream, target); err != nil {
+		c.logger.Debug("Read SOCKS target failed", zap.Error(err))
+		return
+	}
+	_ = stream.SetReadDeadline(time.Time{})
+
+	localConn, err := c.dialLocal(c.ctx, string(target))
+	if err != nil {
+		c.logger.Debug("Dial SOCKS target failed",
+			zap.String("target", string(target)),
+			zap.Error(err),
+		)
+		_, _ = stream.Write([]byte{1})
+		return
+	}
+	defer localConn.Close()
+
+	if _, err := stream.Write([]byte{0}); err != nil {
+		return
+	}
+
 	_ = netutil.PipeWithCallbacksAndBufferSize(
 		c.ctx,
 		stream,
@@ -60,6 +291,77 @@ func (c *PoolClient) handleTCPStream(stream net.Conn) {
 	)
 }
 
+// readPortHeader reads the 2-byte big-endian local port the server writes at
+// the start of every stream on a multi-port TCP tunnel.
+func readPortHeader(stream net.Conn) (int, error) {
+	var header [2]byte
+	_ = stream.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, err := io.ReadFull(stream, header[:])
+	_ = stream.SetReadDeadline(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(header[:])), nil
+}
+
+// udpStreamIdleTimeout mirrors the server's per-flow idle timeout: when the
+// local service stays silent this long the relay stream is torn down.
+const udpStreamIdleTimeout = 90 * time.Second
+
+// handleUDPStream relays length-prefixed datagrams between one visitor's
+// stream and a local UDP socket.
+func (c *PoolClient) handleUDPStream(stream net.Conn) {
+	if !c.acquireSlot() {
+		return
+	}
+	defer c.releaseSlot()
+
+	target := c.localAddr()
+	if c.balancer != nil {
+		target = c.balancer.Pick()
+	}
+
+	conn, err := net.Dial("udp", target)
+	if err != nil {
+		c.logger.Debug("Dial local UDP failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, protocol.MaxDatagramSize)
+		for {
+			_ = conn.SetReadDeadline(time.Now().Add(udpStreamIdleTimeout))
+			n, err := conn.Read(buf)
+			if err != nil {
+				stream.Close()
+				return
+			}
+			if err := protocol.WriteDatagram(stream, buf[:n]); err != nil {
+				return
+			}
+			c.stats.AddBytesOut(int64(n))
+		}
+	}()
+
+	buf := make([]byte, protocol.MaxDatagramSize)
+	for {
+		n, err := protocol.ReadDatagram(stream, buf)
+		if err != nil {
+			break
+		}
+		if _, err := conn.Write(buf[:n]); err != nil {
+			break
+		}
+		c.stats.AddBytesIn(int64(n))
+	}
+
+	conn.Close()
+	<-done
+}
+
 func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 	_ = stream.SetReadDeadline(time.Now().Add(30 * time.Second))
 
@@ -69,6 +371,17 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 	)
 
 	br := bufio.NewReaderSize(cc, 32*1024)
+
+	// An SNI-routed connection arrives as raw TLS instead of a parsed
+	// request; 0x16 (handshake record) can never start an HTTP method.
+	if c.tunnelType == protocol.TunnelTypeHTTPS {
+		if b, err := br.Peek(1); err == nil && b[0] == 0x16 {
+			_ = stream.SetReadDeadline(time.Time{})
+			c.handleTLSPassthrough(&bufferedConn{Conn: cc, reader: br})
+			return
+		}
+	}
+
 	req, err := http.ReadRequest(br)
 	if err != nil {
 		return
@@ -77,7 +390,13 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 
 	_ = stream.SetReadDeadline(time.Time{})
 
-	if httputil.IsWebSocketUpgrade(req) {
+	if !c.acquireSlot() {
+		httputil.WriteProxyError(cc, http.StatusServiceUnavailable, "Too many concurrent requests")
+		return
+	}
+	defer c.releaseSlot()
+
+	if httputil.IsConnectionUpgrade(req) {
 		c.handleWebSocketUpgrade(&bufferedConn{Conn: cc, reader: br}, req)
 		return
 	}
@@ -85,12 +404,85 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 	ctx, cancel := context.WithCancel(c.ctx)
 	defer cancel()
 
+	// Each yamux stream carries exactly one request, so for bodyless
+	// requests nothing more arrives on the stream until we respond. A read
+	// here only returns once the server closes the stream (public client
+	// disconnected); cancel the local call so backend work isn't wasted.
+	if req.ContentLength == 0 {
+		go func() {
+			var b [1]byte
+			_, _ = cc.Read(b[:])
+			cancel()
+		}()
+	}
+
 	scheme := "http"
 	if c.tunnelType == protocol.TunnelTypeHTTPS {
 		scheme = "https"
 	}
 
-	targetURL := fmt.Sprintf("%s://%s:%d%s", scheme, c.localHost, c.localPort, req.URL.RequestURI())
+	// Tee a bounded prefix of each body so the web inspector can show and
+	// replay the exchange.
+	reqRec := &capture.BodyRecorder{}
+	respRec := &capture.BodyRecorder{}
+	req.Body = io.NopCloser(io.TeeReader(req.Body, reqRec))
+
+	var verification string
+	if c.webhook != nil {
+		verification = c.verifyWebhook(req)
+		if verification == webhook.ResultInvalid && c.webhook.Reject {
+			httputil.WriteProxyError(cc, http.StatusUnauthorized, "Invalid webhook signature")
+			c.recordCapture(&capture.Request{
+				Method:       req.Method,
+				Path:         req.URL.RequestURI(),
+				Host:         req.Host,
+				Status:       http.StatusUnauthorized,
+				RequestSize:  req.ContentLength,
+				ReqHeader:    req.Header.Clone(),
+				ReqBody:      reqRec.Bytes(),
+				Verification: verification,
+			})
+			return
+		}
+	}
+
+	// Serve configured overrides without touching the local service.
+	if ov := c.matchOverride(req.URL.Path); ov != nil {
+		ov.write(cc)
+		c.recordCapture(&capture.Request{
+			Method:       req.Method,
+			Path:         req.URL.RequestURI(),
+			Host:         req.Host,
+			Status:       ov.Status,
+			ContentType:  ov.ContentType,
+			RequestSize:  req.ContentLength,
+			ResponseSize: int64(len(ov.Body)),
+			ReqHeader:    req.Header.Clone(),
+		})
+		return
+	}
+
+	// Acknowledge duplicate deliveries with 200 so the provider stops
+	// retrying, but never let them reach the local handler twice.
+	if c.deduper != nil {
+		if id := webhook.DeliveryID(req); id != "" && c.deduper.Duplicate(id) {
+			httputil.WriteProxyError(cc, http.StatusOK, "Duplicate delivery suppressed")
+			c.recordCapture(&capture.Request{
+				Method:       req.Method,
+				Path:         req.URL.RequestURI(),
+				Host:         req.Host,
+				Status:       http.StatusOK,
+				RequestSize:  req.ContentLength,
+				ReqHeader:    req.Header.Clone(),
+				Verification: webhook.ResultDuplicate,
+			})
+			return
+		}
+	}
+
+	backendAddr, setCookie := c.backendFor(req)
+
+	targetURL := fmt.Sprintf("%s://%s%s", scheme, backendAddr, req.URL.RequestURI())
 	outReq, err := http.NewRequestWithContext(ctx, req.Method, targetURL, req.Body)
 	if err != nil {
 		httputil.WriteProxyError(cc, http.StatusBadGateway, "Bad Gateway")
@@ -104,9 +496,14 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 
 	outReq.Header.Del("Accept-Encoding")
 
-	targetHost := c.localHost
-	if c.localPort != 80 && c.localPort != 443 {
-		targetHost = fmt.Sprintf("%s:%d", c.localHost, c.localPort)
+	targetHost := backendAddr
+	if c.balancer == nil && (c.localPort == 80 || c.localPort == 443) {
+		targetHost = c.localHost
+	}
+	if c.localSocket != "" {
+		// The dialer ignores the address for unix sockets; keep the Host
+		// header free of the placeholder port.
+		targetHost = c.localHost
 	}
 	outReq.Host = targetHost
 	outReq.Header.Set("Host", targetHost)
@@ -115,6 +512,7 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 	}
 	outReq.Header.Set("X-Forwarded-Proto", "https")
 
+	reqStart := time.Now()
 	resp, err := c.httpClient.Do(outReq)
 	if err != nil {
 		httputil.WriteProxyError(cc, http.StatusBadGateway, "Local service unavailable")
@@ -122,6 +520,38 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= 500 {
+		c.stats.AddServerError()
+	}
+
+	// The entry is completed and recorded once the response body has
+	// streamed through, so the recorded body prefix is available.
+	capEntry := &capture.Request{
+		Time:         reqStart,
+		Method:       req.Method,
+		Path:         req.URL.RequestURI(),
+		Host:         origHost,
+		Status:       resp.StatusCode,
+		Duration:     time.Since(reqStart),
+		ContentType:  resp.Header.Get("Content-Type"),
+		RequestSize:  req.ContentLength,
+		ResponseSize: resp.ContentLength,
+		ReqHeader:    req.Header.Clone(),
+		RespHeader:   resp.Header.Clone(),
+		ReqBody:      reqRec.Bytes(),
+		Verification: verification,
+	}
+
+	if setCookie && c.balancer != nil {
+		cookie := &http.Cookie{
+			Name:     stickyCookieName,
+			Value:    c.balancer.CookieValue(backendAddr),
+			Path:     "/",
+			HttpOnly: true,
+		}
+		resp.Header.Add("Set-Cookie", cookie.String())
+	}
+
 	_ = stream.SetWriteDeadline(time.Now().Add(30 * time.Second))
 	if err := writeResponseHeader(cc, resp); err != nil {
 		return
@@ -140,6 +570,7 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 	for {
 		nr, er := resp.Body.Read(buf)
 		if nr > 0 {
+			_, _ = respRec.Write(buf[:nr])
 			_ = stream.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			nw, ew := cc.Write(buf[:nr])
 			if ew != nil || nr != nw {
@@ -151,11 +582,57 @@ func (c *PoolClient) handleHTTPStream(stream net.Conn) {
 		}
 	}
 	close(done)
+
+	capEntry.RespBody = respRec.Bytes()
+	c.recordCapture(capEntry)
+}
+
+// handleTLSPassthrough pipes an SNI-routed connection straight to the local
+// HTTPS server, leaving TLS end-to-end between the public client and the
+// local service.
+func (c *PoolClient) handleTLSPassthrough(src net.Conn) {
+	target := c.localAddr()
+	if c.balancer != nil {
+		target = c.balancer.Pick()
+	}
+
+	localConn, err := c.dialLocal(c.ctx, target)
+	if err != nil {
+		c.logger.Debug("Dial local failed", zap.Error(err))
+		return
+	}
+	defer localConn.Close()
+
+	_ = netutil.PipeWithCallbacksAndBufferSize(
+		c.ctx,
+		src,
+		localConn,
+		pool.SizeLarge,
+		nil,
+		nil,
+	)
+}
+
+// verifyWebhook buffers the request body (bounded), checks the provider
+// signature, and replaces req.Body so the request can still be forwarded.
+// Oversized bodies are treated as invalid rather than buffered in full.
+func (c *PoolClient) verifyWebhook(req *http.Request) string {
+	body, err := io.ReadAll(io.LimitReader(req.Body, webhook.MaxBodySize+1))
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || len(body) > webhook.MaxBodySize {
+		return webhook.ResultInvalid
+	}
+
+	if err := c.webhook.Verify(req, body); err != nil {
+		c.logger.Debug("Webhook signature rejected", zap.Error(err))
+		return webhook.ResultInvalid
+	}
+	return webhook.ResultVerified
 }
 
 func (c *PoolClient) handleWebSocketUpgrade(cc net.Conn, req *http.Request) {
-	targetAddr := net.JoinHostPort(c.localHost, fmt.Sprintf("%d", c.localPort))
-	localConn, err := net.DialTimeout("tcp", targetAddr, 10*time.Second)
+	targetAddr, _ := c.backendFor(req)
+	localConn, err := c.dialLocal(c.ctx, targetAddr)
 	if err != nil {
 		httputil.WriteProxyError(cc, http.StatusBadGateway, "WebSocket backend unavailable")
 		return
@@ -208,6 +685,63 @@ func (c *PoolClient) handleWebSocketUpgrade(cc net.Conn, req *http.Request) {
 	}
 }
 
+// guardedStream runs the public-client side of a TCP tunnel through a
+// read-only database guard: reads return only the bytes cleared for
+// forwarding, while in-protocol error replies go straight back to the
+// public client.
+type guardedStream struct {
+	net.Conn
+	guard   *dbproto.GuardConn
+	pending []byte
+	readErr error
+}
+
+func (s *guardedStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.readErr != nil {
+			return 0, s.readErr
+		}
+
+		buf := make([]byte, 32*1024)
+		n, err := s.Conn.Read(buf)
+		if n > 0 {
+			forward, reply, kill := s.guard.Process(buf[:n])
+			if len(reply) > 0 {
+				if _, werr := s.Conn.Write(reply); werr != nil && err == nil {
+					err = werr
+				}
+			}
+			if kill && err == nil {
+				err = dbproto.ErrWriteBlocked
+			}
+			s.pending = forward
+		}
+		s.readErr = err
+		if len(s.pending) == 0 && err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// observedStream feeds the public-client side of a TCP tunnel through a
+// database protocol observer as it is read.
+type observedStream struct {
+	net.Conn
+	observer *dbproto.ConnObserver
+}
+
+func (s *observedStream) Read(p []byte) (int, error) {
+	n, err := s.Conn.Read(p)
+	if n > 0 {
+		s.observer.Observe(p[:n])
+	}
+	return n, err
+}
+
 type bufferedConn struct {
 	net.Conn
 	reader *bufio.Reader
@@ -217,7 +751,7 @@ func (c *bufferedConn) Read(p []byte) (int, error) {
 	return c.reader.Read(p)
 }
 
-func newLocalHTTPClient(tunnelType protocol.TunnelType) *http.Client {
+func newLocalHTTPClient(tunnelType protocol.TunnelType, dialRetry time.Duration, localSocket string) *http.Client {
 	var tlsConfig *tls.Config
 	if tunnelType == protocol.TunnelTypeHTTPS {
 		tlsConfig = &tls.Config{InsecureSkipVerify: true}
@@ -236,10 +770,12 @@ func newLocalHTTPClient(tunnelType protocol.TunnelType) *http.Client {
 			ExpectContinueTimeout: 500 * time.Millisecond,
 			WriteBufferSize:       32 * 1024,
 			ReadBufferSize:        32 * 1024,
-			DialContext: (&net.Dialer{
-				Timeout:   3 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}).DialContext,
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				if localSocket != "" {
+					return dialLocalRetry(ctx, "unix", localSocket, dialRetry)
+				}
+				return dialLocalRetry(ctx, network, addr, dialRetry)
+			},
 		},
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
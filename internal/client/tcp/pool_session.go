@@ -2,7 +2,6 @@ package tcp
 
 import (
 	"fmt"
-	"io"
 	"net"
 	"sync/atomic"
 	"time"
@@ -11,7 +10,7 @@ import (
 	"github.com/hashicorp/yamux"
 	"go.uber.org/zap"
 
-	"drip/internal/shared/constants"
+	"drip/internal/shared/netutil"
 	"drip/internal/shared/protocol"
 )
 
@@ -50,14 +49,14 @@ func (c *PoolClient) scalerLoop() {
 		scaleDownLoad      = 0.3
 	)
 
-	ticker := time.NewTicker(checkInterval)
+	ticker := c.clock.NewTicker(checkInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-c.stopCh:
 			return
-		case <-ticker.C:
+		case <-ticker.Chan():
 		}
 
 		c.mu.Lock()
@@ -134,7 +133,7 @@ func (c *PoolClient) addDataSession() error {
 		return fmt.Errorf("server does not support data connections")
 	}
 
-	conn, err := c.dialTLS()
+	conn, err := c.dialControl()
 	if err != nil {
 		return err
 	}
@@ -191,12 +190,7 @@ func (c *PoolClient) addDataSession() error {
 		return fmt.Errorf("data connection rejected: %s", resp.Message)
 	}
 
-	yamuxCfg := yamux.DefaultConfig()
-	yamuxCfg.EnableKeepAlive = false
-	yamuxCfg.LogOutput = io.Discard
-	yamuxCfg.AcceptBacklog = constants.YamuxAcceptBacklog
-
-	session, err := yamux.Server(conn, yamuxCfg)
+	session, err := yamux.Server(conn, netutil.YamuxConfig())
 	if err != nil {
 		_ = conn.Close()
 		return fmt.Errorf("failed to init yamux session: %w", err)
@@ -0,0 +1,65 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Transport dials the control channel to the server. Registration,
+// heartbeats, and stats all run over the returned conn, so a transport
+// only implements dialing to inherit the rest of the connector unchanged;
+// liveness runs over yamux pings regardless of transport.
+type Transport interface {
+	// Name identifies the transport in logs and errors ("tls", "quic").
+	Name() string
+	// Dial establishes one control connection to serverAddr.
+	Dial(serverAddr string) (net.Conn, error)
+}
+
+// newTransport resolves a --transport value to an implementation: TLS
+// over TCP by default, or the control channel on a QUIC stream.
+func newTransport(name string, tlsConfig *tls.Config) (Transport, error) {
+	switch name {
+	case "", "tls":
+		return &tlsTransport{config: tlsConfig}, nil
+	case "quic":
+		return &quicTransport{config: tlsConfig}, nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q (supported: tls, quic)", name)
+	}
+}
+
+// tlsTransport is the default control transport: TCP with TLS 1.3 and
+// keepalive/buffer tuning on the underlying socket. Liveness runs over
+// yamux pings; there is no transport-native probe.
+type tlsTransport struct {
+	config *tls.Config
+}
+
+func (t *tlsTransport) Name() string { return "tls" }
+
+func (t *tlsTransport) Dial(serverAddr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", serverAddr, t.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	state := conn.ConnectionState()
+	if state.Version != tls.VersionTLS13 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("server not using TLS 1.3 (version: 0x%04x)", state.Version)
+	}
+
+	if tcpConn, ok := conn.NetConn().(*net.TCPConn); ok {
+		_ = tcpConn.SetNoDelay(true)
+		_ = tcpConn.SetKeepAlive(true)
+		_ = tcpConn.SetKeepAlivePeriod(30 * time.Second)
+		_ = tcpConn.SetReadBuffer(256 * 1024)
+		_ = tcpConn.SetWriteBuffer(256 * 1024)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,63 @@
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"drip/internal/shared/netutil"
+
+	"golang.org/x/net/quic"
+)
+
+// quicTransport runs the control channel over a single bidirectional QUIC
+// stream (golang.org/x/net/quic). QUIC carries TLS 1.3 in its handshake
+// and migrates across client address changes, so roaming clients keep
+// their tunnel where a TCP conn would drop. The server must expose a QUIC
+// control listener (--quic-port).
+type quicTransport struct {
+	config *tls.Config
+}
+
+func (t *quicTransport) Name() string { return "quic" }
+
+func (t *quicTransport) Dial(serverAddr string) (net.Conn, error) {
+	tlsConfig := t.config.Clone()
+	tlsConfig.NextProtos = []string{netutil.QUICControlALPN}
+
+	// Each control connection owns its endpoint (one UDP socket); the
+	// adapter closes it with the conn.
+	endpoint, err := quic.Listen("udp", ":0", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open QUIC endpoint: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := endpoint.Dial(ctx, "udp", serverAddr, &quic.Config{
+		TLSConfig:       tlsConfig,
+		KeepAlivePeriod: 30 * time.Second,
+	})
+	if err != nil {
+		closeEndpoint(endpoint)
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	stream, err := conn.NewStream(ctx)
+	if err != nil {
+		conn.Abort(nil)
+		closeEndpoint(endpoint)
+		return nil, fmt.Errorf("failed to open control stream: %w", err)
+	}
+
+	return netutil.NewQUICStreamConn(endpoint, conn, stream), nil
+}
+
+func closeEndpoint(endpoint *quic.Endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = endpoint.Close(ctx)
+}
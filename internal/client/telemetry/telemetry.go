@@ -0,0 +1,208 @@
+// Package telemetry implements the client's opt-in crash reporting and
+// anonymous feature usage counters. Everything here is a no-op unless the
+// user enabled telemetry in the client config; reports contain the drip
+// version, OS/arch, and stack traces — never traffic data, hostnames, or
+// tokens.
+package telemetry
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"drip/pkg/config"
+
+	json "github.com/goccy/go-json"
+)
+
+const uploadTimeout = 3 * time.Second
+
+// flushInterval is how long usage counters accumulate on disk before the
+// next run uploads and resets them.
+const flushInterval = 24 * time.Hour
+
+var (
+	mu  sync.Mutex
+	cfg *config.TelemetryConfig
+)
+
+// Init loads the telemetry section of the client config. Telemetry stays
+// disabled when there is no config or the section is absent.
+func Init() {
+	c, err := config.LoadClientConfig("")
+	if err != nil || c.Telemetry == nil || !c.Telemetry.Enabled {
+		return
+	}
+	mu.Lock()
+	cfg = c.Telemetry
+	mu.Unlock()
+}
+
+func enabled() *config.TelemetryConfig {
+	mu.Lock()
+	defer mu.Unlock()
+	return cfg
+}
+
+// CrashReport is what gets written (and optionally uploaded) when the
+// client panics.
+type CrashReport struct {
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	Time      time.Time `json:"time"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+}
+
+// CapturePanic records a crash report when the surrounding call panics,
+// then re-panics so the failure still surfaces normally. Use it deferred
+// at the top of main.
+func CapturePanic(version string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if c := enabled(); c != nil {
+		report := &CrashReport{
+			Version:   version,
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			Time:      time.Now().UTC(),
+			Panic:     fmt.Sprint(r),
+			Stack:     string(stack()),
+		}
+
+		if path, err := saveCrashReport(report); err == nil {
+			fmt.Fprintf(os.Stderr, "Crash report saved to %s\n", path)
+		}
+		if c.Endpoint != "" {
+			upload(c.Endpoint+"/crash", report)
+		}
+	}
+
+	panic(r)
+}
+
+func stack() []byte {
+	buf := make([]byte, 64<<10)
+	return buf[:runtime.Stack(buf, false)]
+}
+
+func crashDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "crashes")
+	}
+	return filepath.Join(home, ".drip", "crashes")
+}
+
+func saveCrashReport(report *CrashReport) (string, error) {
+	dir := crashDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.json", report.Time.Format("20060102-150405")))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// usageFile accumulates feature counters between uploads.
+type usageFile struct {
+	Version  string           `json:"version"`
+	OS       string           `json:"os"`
+	Arch     string           `json:"arch"`
+	Since    time.Time        `json:"since"`
+	Counters map[string]int64 `json:"counters"`
+}
+
+func usagePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "usage.json")
+	}
+	return filepath.Join(home, ".drip", "usage.json")
+}
+
+// CountFeature increments the anonymous usage counter for one feature
+// (e.g. the command name). It does nothing unless telemetry is enabled.
+func CountFeature(version, name string) {
+	c := enabled()
+	if c == nil || name == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	usage := loadUsage()
+	usage.Version = version
+	usage.Counters[name]++
+	saveUsage(usage)
+
+	// Counters older than the flush interval ship with the next command
+	// run; failures just leave them on disk for the run after.
+	if c.Endpoint != "" && time.Since(usage.Since) >= flushInterval {
+		if upload(c.Endpoint+"/usage", usage) {
+			saveUsage(&usageFile{Since: time.Now().UTC(), Counters: make(map[string]int64)})
+		}
+	}
+}
+
+func loadUsage() *usageFile {
+	usage := &usageFile{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Since: time.Now().UTC(),
+	}
+
+	if data, err := os.ReadFile(usagePath()); err == nil {
+		_ = json.Unmarshal(data, usage)
+	}
+	if usage.Counters == nil {
+		usage.Counters = make(map[string]int64)
+	}
+	return usage
+}
+
+func saveUsage(usage *usageFile) {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(usagePath(), data, 0600)
+}
+
+// upload POSTs a JSON payload to the collector, best-effort with a short
+// timeout so telemetry never delays the command itself.
+func upload(url string, payload interface{}) bool {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return false
+	}
+
+	client := &http.Client{Timeout: uploadTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode < 300
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// deliveryIDHeaders lists the per-delivery identifier headers major providers
+// attach to webhook requests. The same ID is re-sent on provider retries, so
+// it is safe to key deduplication on.
+var deliveryIDHeaders = []string{
+	"X-Github-Delivery",
+	"X-Gitlab-Event-Uuid",
+	"Svix-Id",
+	"Webhook-Id",
+	"X-Shopify-Webhook-Id",
+}
+
+// DeliveryID extracts the provider delivery identifier from a webhook
+// request, or "" when the request carries none.
+func DeliveryID(r *http.Request) string {
+	for _, key := range deliveryIDHeaders {
+		if id := r.Header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// Deduper remembers recently seen delivery IDs so duplicate webhook
+// deliveries — common after tunnel reconnects, when providers redeliver
+// everything that timed out — can be acknowledged without reaching the
+// local handler.
+type Deduper struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewDeduper creates a deduper that treats repeated delivery IDs within
+// window as duplicates.
+func NewDeduper(window time.Duration) *Deduper {
+	return &Deduper{
+		window: window,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Duplicate records id and reports whether it was already seen within the
+// window. Expired entries are pruned as a side effect.
+func (d *Deduper) Duplicate(id string) bool {
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k, t := range d.seen {
+		if now.Sub(t) > d.window {
+			delete(d.seen, k)
+		}
+	}
+
+	if t, ok := d.seen[id]; ok && now.Sub(t) <= d.window {
+		return true
+	}
+	d.seen[id] = now
+	return false
+}
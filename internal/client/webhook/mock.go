@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// MockEvent is a crafted webhook request for testing handlers without
+// configuring the real provider.
+type MockEvent struct {
+	Provider string
+	Event    string
+	Body     []byte
+	Header   http.Header
+}
+
+// NewMockEvent builds a realistic payload for a "provider:event" spec such
+// as "github:push" or "stripe:payment_intent.succeeded".
+func NewMockEvent(spec string) (*MockEvent, error) {
+	provider, event, found := strings.Cut(spec, ":")
+	if !found || provider == "" || event == "" {
+		return nil, fmt.Errorf("invalid event spec: %s (use provider:event, e.g. github:push)", spec)
+	}
+
+	e := &MockEvent{
+		Provider: strings.ToLower(provider),
+		Event:    event,
+		Header:   make(http.Header),
+	}
+	e.Header.Set("Content-Type", "application/json")
+
+	var payload map[string]any
+	switch e.Provider {
+	case ProviderGitHub:
+		var err error
+		if payload, err = githubPayload(event); err != nil {
+			return nil, err
+		}
+		e.Header.Set("X-GitHub-Event", event)
+		e.Header.Set("X-GitHub-Delivery", mockID(16))
+		e.Header.Set("User-Agent", "GitHub-Hookshot/mock")
+	case ProviderStripe:
+		payload = stripePayload(event)
+		e.Header.Set("User-Agent", "Stripe/1.0")
+	case ProviderSlack:
+		var err error
+		if payload, err = slackPayload(event); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported webhook provider: %s (must be stripe, github, or slack)", provider)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	e.Body = body
+	return e, nil
+}
+
+// Sign adds the provider's signature headers for the payload, using the
+// same schemes Verify checks.
+func (e *MockEvent) Sign(secret string) {
+	key := []byte(secret)
+	switch e.Provider {
+	case ProviderGitHub:
+		e.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(hmacSHA256(key, e.Body)))
+	case ProviderStripe:
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		sig := hmacSHA256(key, append([]byte(ts+"."), e.Body...))
+		e.Header.Set("Stripe-Signature", fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(sig)))
+	case ProviderSlack:
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		sig := hmacSHA256(key, append([]byte("v0:"+ts+":"), e.Body...))
+		e.Header.Set("X-Slack-Request-Timestamp", ts)
+		e.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(sig))
+	}
+}
+
+func githubPayload(event string) (map[string]any, error) {
+	repo := map[string]any{
+		"id":        1296269,
+		"name":      "example",
+		"full_name": "octocat/example",
+		"private":   false,
+		"owner":     map[string]any{"login": "octocat", "id": 1},
+	}
+
+	switch event {
+	case "ping":
+		return map[string]any{
+			"zen":        "Design for failure.",
+			"hook_id":    12345678,
+			"repository": repo,
+		}, nil
+	case "push":
+		sha := mockID(20)
+		return map[string]any{
+			"ref":        "refs/heads/main",
+			"before":     mockID(20),
+			"after":      sha,
+			"repository": repo,
+			"pusher":     map[string]any{"name": "octocat", "email": "octocat@example.com"},
+			"commits": []map[string]any{{
+				"id":        sha,
+				"message":   "Update README",
+				"timestamp": time.Now().Format(time.RFC3339),
+				"author":    map[string]any{"name": "octocat", "email": "octocat@example.com"},
+				"added":     []string{},
+				"removed":   []string{},
+				"modified":  []string{"README.md"},
+			}},
+			"head_commit": map[string]any{"id": sha, "message": "Update README"},
+		}, nil
+	case "pull_request":
+		return map[string]any{
+			"action": "opened",
+			"number": 42,
+			"pull_request": map[string]any{
+				"id":    1,
+				"state": "open",
+				"title": "Add new feature",
+				"user":  map[string]any{"login": "octocat"},
+				"head":  map[string]any{"ref": "feature-branch"},
+				"base":  map[string]any{"ref": "main"},
+			},
+			"repository": repo,
+		}, nil
+	case "issues":
+		return map[string]any{
+			"action": "opened",
+			"issue": map[string]any{
+				"number": 7,
+				"state":  "open",
+				"title":  "Something is broken",
+				"user":   map[string]any{"login": "octocat"},
+			},
+			"repository": repo,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown github event: %s (supported: ping, push, pull_request, issues)", event)
+}
+
+// stripePayload wraps any event type in Stripe's standard event envelope.
+func stripePayload(event string) map[string]any {
+	return map[string]any{
+		"id":          "evt_" + mockID(12),
+		"object":      "event",
+		"api_version": "2024-06-20",
+		"created":     time.Now().Unix(),
+		"type":        event,
+		"livemode":    false,
+		"data": map[string]any{
+			"object": map[string]any{
+				"id":       "pi_" + mockID(12),
+				"object":   "payment_intent",
+				"amount":   2000,
+				"currency": "usd",
+				"status":   "succeeded",
+			},
+		},
+	}
+}
+
+func slackPayload(event string) (map[string]any, error) {
+	switch event {
+	case "url_verification":
+		return map[string]any{
+			"token":     mockID(12),
+			"challenge": mockID(16),
+			"type":      "url_verification",
+		}, nil
+	case "event_callback", "message":
+		return map[string]any{
+			"token":    mockID(12),
+			"team_id":  "T0001",
+			"type":     "event_callback",
+			"event_id": "Ev" + strings.ToUpper(mockID(5)),
+			"event_ts": fmt.Sprintf("%d.000100", time.Now().Unix()),
+			"event": map[string]any{
+				"type":    "message",
+				"channel": "C2147483705",
+				"user":    "U2147483697",
+				"text":    "Hello world",
+				"ts":      fmt.Sprintf("%d.000100", time.Now().Unix()),
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown slack event: %s (supported: url_verification, event_callback)", event)
+}
+
+// mockID returns n random bytes as hex, used for delivery IDs and SHAs.
+func mockID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
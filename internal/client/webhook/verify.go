@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported webhook providers.
+const (
+	ProviderStripe = "stripe"
+	ProviderGitHub = "github"
+	ProviderSlack  = "slack"
+)
+
+// Verification outcomes recorded on captured requests.
+const (
+	ResultVerified  = "verified"
+	ResultInvalid   = "invalid"
+	ResultDuplicate = "duplicate"
+)
+
+// MaxBodySize bounds how much of a request body is buffered for signature
+// verification; larger bodies are treated as invalid.
+const MaxBodySize = 1 << 20
+
+// timestampTolerance rejects signed payloads older than this, matching the
+// replay windows the providers themselves recommend.
+const timestampTolerance = 5 * time.Minute
+
+// Verifier checks webhook signatures for a single provider against a shared
+// secret before requests reach the local app.
+type Verifier struct {
+	provider string
+	secret   []byte
+
+	// Reject controls whether invalid requests are refused with 401 instead
+	// of just being tagged in the capture buffer.
+	Reject bool
+}
+
+// NewVerifier creates a signature verifier for one of the supported
+// providers.
+func NewVerifier(provider, secret string, reject bool) (*Verifier, error) {
+	provider = strings.ToLower(provider)
+	switch provider {
+	case ProviderStripe, ProviderGitHub, ProviderSlack:
+	default:
+		return nil, fmt.Errorf("unsupported webhook provider: %s (must be stripe, github, or slack)", provider)
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	return &Verifier{
+		provider: provider,
+		secret:   []byte(secret),
+		Reject:   reject,
+	}, nil
+}
+
+// Verify checks the request's signature headers against the body.
+func (v *Verifier) Verify(r *http.Request, body []byte) error {
+	switch v.provider {
+	case ProviderGitHub:
+		return v.verifyGitHub(r, body)
+	case ProviderStripe:
+		return v.verifyStripe(r, body)
+	case ProviderSlack:
+		return v.verifySlack(r, body)
+	}
+	return fmt.Errorf("unknown provider")
+}
+
+// verifyGitHub checks X-Hub-Signature-256: sha256=<hex hmac(secret, body)>
+func (v *Verifier) verifyGitHub(r *http.Request, body []byte) error {
+	sig := r.Header.Get("X-Hub-Signature-256")
+	if !strings.HasPrefix(sig, "sha256=") {
+		return fmt.Errorf("missing X-Hub-Signature-256 header")
+	}
+
+	want, err := hex.DecodeString(strings.TrimPrefix(sig, "sha256="))
+	if err != nil {
+		return fmt.Errorf("malformed signature")
+	}
+
+	if !hmac.Equal(want, hmacSHA256(v.secret, body)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// verifyStripe checks Stripe-Signature: t=<ts>,v1=<hex hmac(secret, ts.body)>
+func (v *Verifier) verifyStripe(r *http.Request, body []byte) error {
+	header := r.Header.Get("Stripe-Signature")
+	if header == "" {
+		return fmt.Errorf("missing Stripe-Signature header")
+	}
+
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("malformed Stripe-Signature header")
+	}
+	if err := checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	payload := append([]byte(timestamp+"."), body...)
+	want := hmacSHA256(v.secret, payload)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, want) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature mismatch")
+}
+
+// verifySlack checks X-Slack-Signature: v0=<hex hmac(secret, v0:ts:body)>
+func (v *Verifier) verifySlack(r *http.Request, body []byte) error {
+	sig := r.Header.Get("X-Slack-Signature")
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	if !strings.HasPrefix(sig, "v0=") || timestamp == "" {
+		return fmt.Errorf("missing Slack signature headers")
+	}
+	if err := checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	want, err := hex.DecodeString(strings.TrimPrefix(sig, "v0="))
+	if err != nil {
+		return fmt.Errorf("malformed signature")
+	}
+
+	payload := append([]byte("v0:"+timestamp+":"), body...)
+	if !hmac.Equal(want, hmacSHA256(v.secret, payload)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func checkTimestamp(ts string) error {
+	seconds, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed timestamp")
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > timestampTolerance || age < -timestampTolerance {
+		return fmt.Errorf("timestamp outside tolerance")
+	}
+	return nil
+}
+
+func hmacSHA256(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
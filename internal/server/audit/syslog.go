@@ -0,0 +1,189 @@
+// Package audit forwards server events to external log collection.
+//
+// Events are emitted as RFC 5424 syslog messages over UDP or TCP, which
+// every SIEM ingests natively. The forwarder subscribes to the server's
+// event bus, so everything published there (tunnel lifecycle, quota
+// warnings, admin notices) reaches the collector without extra wiring.
+package audit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"drip/internal/server/tunnel"
+	"drip/internal/shared/protocol"
+)
+
+const (
+	// local0.info: operational events from a network service.
+	syslogPriority = 16*8 + 6
+
+	syslogAppName     = "drip"
+	syslogDialTimeout = 5 * time.Second
+)
+
+// SyslogForwarder formats server events as RFC 5424 messages and writes
+// them to a collector. Delivery is best-effort: a failed write drops the
+// message and the connection is re-established lazily.
+type SyslogForwarder struct {
+	network  string
+	addr     string
+	hostname string
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	conn net.Conn
+
+	events chan protocol.Event
+	bus    *tunnel.EventBus
+	stopCh chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewSyslogForwarder creates a forwarder for the given collector address.
+// addr accepts "udp://host:port", "tcp://host:port", or a bare host:port
+// (UDP by default).
+func NewSyslogForwarder(addr string, logger *zap.Logger) (*SyslogForwarder, error) {
+	network := "udp"
+	if rest, ok := strings.CutPrefix(addr, "udp://"); ok {
+		addr = rest
+	} else if rest, ok := strings.CutPrefix(addr, "tcp://"); ok {
+		network = "tcp"
+		addr = rest
+	}
+
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %w", addr, err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &SyslogForwarder{
+		network:  network,
+		addr:     addr,
+		hostname: hostname,
+		logger:   logger,
+		stopCh:   make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to the event bus and forwards events in the background.
+func (f *SyslogForwarder) Start(bus *tunnel.EventBus) {
+	f.bus = bus
+	f.events = bus.Subscribe()
+
+	f.wg.Add(1)
+	go f.forwardLoop()
+}
+
+// Stop unsubscribes and closes the collector connection.
+func (f *SyslogForwarder) Stop() {
+	f.once.Do(func() {
+		close(f.stopCh)
+	})
+	f.wg.Wait()
+
+	if f.bus != nil {
+		f.bus.Unsubscribe(f.events)
+	}
+
+	f.mu.Lock()
+	if f.conn != nil {
+		f.conn.Close()
+		f.conn = nil
+	}
+	f.mu.Unlock()
+}
+
+func (f *SyslogForwarder) forwardLoop() {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case ev := <-f.events:
+			if err := f.send(ev); err != nil {
+				f.logger.Debug("Syslog forward failed",
+					zap.String("event_type", ev.Type),
+					zap.Error(err),
+				)
+			}
+		case <-f.stopCh:
+			return
+		}
+	}
+}
+
+// send writes one event as an RFC 5424 message, redialing once if the
+// cached connection has gone stale.
+func (f *SyslogForwarder) send(ev protocol.Event) error {
+	msg := f.format(ev)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.conn == nil {
+		conn, err := net.DialTimeout(f.network, f.addr, syslogDialTimeout)
+		if err != nil {
+			return err
+		}
+		f.conn = conn
+	}
+
+	if _, err := f.conn.Write([]byte(msg)); err != nil {
+		f.conn.Close()
+		f.conn = nil
+
+		conn, dialErr := net.DialTimeout(f.network, f.addr, syslogDialTimeout)
+		if dialErr != nil {
+			return err
+		}
+		f.conn = conn
+		_, err = f.conn.Write([]byte(msg))
+		return err
+	}
+	return nil
+}
+
+// format renders one event as an RFC 5424 message with the subdomain in a
+// structured-data element, e.g.
+//
+//	<134>1 2026-01-02T15:04:05Z host drip 1234 quota_warning [drip subdomain="api"] tunnel has used ...
+func (f *SyslogForwarder) format(ev protocol.Event) string {
+	ts := ev.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	structured := "-"
+	if ev.Subdomain != "" {
+		structured = fmt.Sprintf(`[drip subdomain="%s"]`, sdEscape(ev.Subdomain))
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s\n",
+		syslogPriority,
+		ts.UTC().Format(time.RFC3339),
+		f.hostname,
+		syslogAppName,
+		os.Getpid(),
+		ev.Type,
+		structured,
+		ev.Message,
+	)
+}
+
+// sdEscape escapes the characters RFC 5424 reserves inside structured-data
+// parameter values.
+func sdEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	return r.Replace(s)
+}
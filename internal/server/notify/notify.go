@@ -0,0 +1,166 @@
+// Package notify warns owners before server resources expire, so a demo URL
+// doesn't break because a 30-day reservation or a certificate lapsed
+// silently. Notifications go to a configured webhook; the payload is a
+// Slack-compatible {"text": ...} document, which most chat and email bridges
+// accept.
+package notify
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"drip/internal/server/tunnel"
+	"go.uber.org/zap"
+)
+
+const (
+	// checkInterval is how often expiring resources are scanned.
+	checkInterval = 1 * time.Hour
+
+	// leadTime is how far ahead of expiry the warning fires.
+	leadTime = 72 * time.Hour
+)
+
+// Notifier periodically scans for resources nearing expiry and posts a
+// warning to the configured webhook. Each resource is warned about once per
+// expiry time; renewing a reservation re-arms its warning.
+type Notifier struct {
+	webhookURL string
+	certFile   string
+	manager    *tunnel.Manager
+	logger     *zap.Logger
+	client     *http.Client
+
+	mu       sync.Mutex
+	notified map[string]time.Time // resource key -> expiry already warned about
+
+	stopCh chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+// NewNotifier creates a notifier posting to webhookURL. certFile is the
+// server's TLS certificate path and may be empty to skip the cert check.
+func NewNotifier(webhookURL, certFile string, manager *tunnel.Manager, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		webhookURL: webhookURL,
+		certFile:   certFile,
+		manager:    manager,
+		logger:     logger,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		notified:   make(map[string]time.Time),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start begins the background scan loop.
+func (n *Notifier) Start() {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		n.check()
+		for {
+			select {
+			case <-ticker.C:
+				n.check()
+			case <-n.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the scan loop. Safe to call multiple times.
+func (n *Notifier) Stop() {
+	n.once.Do(func() {
+		close(n.stopCh)
+	})
+	n.wg.Wait()
+}
+
+// check scans all expiring resources and sends warnings for any not already
+// warned about at their current expiry time.
+func (n *Notifier) check() {
+	for name, expires := range n.manager.ExpiringReservations(leadTime) {
+		n.warn("reservation:"+name, expires,
+			fmt.Sprintf("Subdomain reservation %q expires at %s", name, expires.Format(time.RFC3339)))
+	}
+
+	if n.certFile != "" {
+		if expires, ok := certExpiry(n.certFile); ok && time.Until(expires) < leadTime {
+			n.warn("cert:"+n.certFile, expires,
+				fmt.Sprintf("TLS certificate %s expires at %s", n.certFile, expires.Format(time.RFC3339)))
+		}
+	}
+}
+
+// warn posts a message unless this resource was already warned about at the
+// same expiry time.
+func (n *Notifier) warn(key string, expires time.Time, message string) {
+	n.mu.Lock()
+	if prev, ok := n.notified[key]; ok && prev.Equal(expires) {
+		n.mu.Unlock()
+		return
+	}
+	n.notified[key] = expires
+	n.mu.Unlock()
+
+	payload, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return
+	}
+
+	resp, err := n.client.Post(n.webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		n.logger.Warn("Failed to deliver expiry notification",
+			zap.String("resource", key),
+			zap.Error(err),
+		)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		n.logger.Warn("Expiry notification rejected by webhook",
+			zap.String("resource", key),
+			zap.Int("status", resp.StatusCode),
+		)
+		return
+	}
+
+	n.logger.Info("Expiry notification sent",
+		zap.String("resource", key),
+		zap.Time("expires_at", expires),
+	)
+}
+
+// certExpiry reads the leaf certificate's NotAfter from a PEM file.
+func certExpiry(path string) (time.Time, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, false
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
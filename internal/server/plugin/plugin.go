@@ -0,0 +1,205 @@
+// Package plugin calls operator-run extension processes over gRPC so
+// deployments can hook their own SSO, routing, or billing into the server
+// without forking it. Calls are unary against the drip.plugin.v1.Plugin
+// service and use the JSON codec (content-type application/grpc+json), so a
+// plugin is any gRPC server that registers a JSON codec — no shared protobuf
+// definitions needed. Like grpcprobe, the client speaks just enough HTTP/2
+// and gRPC framing itself to avoid a full gRPC dependency.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+const (
+	servicePath = "/drip.plugin.v1.Plugin/"
+
+	// callTimeout bounds each plugin call; the plugin sits next to the
+	// server, so anything slower is treated as failed.
+	callTimeout = 3 * time.Second
+
+	// maxResponseSize bounds the plugin response we're willing to parse.
+	maxResponseSize = 1 << 20
+)
+
+// AuthRequest asks the plugin whether a registration may proceed.
+type AuthRequest struct {
+	Token      string `json:"token"`
+	Subdomain  string `json:"subdomain"`
+	TunnelType string `json:"tunnel_type"`
+	ClientAddr string `json:"client_addr"`
+}
+
+// AuthResponse is the plugin's verdict on a registration.
+type AuthResponse struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+// RouteRequest asks the plugin for a decision on one public request.
+type RouteRequest struct {
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+	Subdomain string `json:"subdomain"`
+	VisitorIP string `json:"visitor_ip"`
+}
+
+// RouteResponse carries the plugin's routing decision; zero values mean
+// "no opinion, proceed".
+type RouteResponse struct {
+	Deny       bool     `json:"deny"`
+	RedirectTo string   `json:"redirect_to"`
+	Tags       []string `json:"tags"`
+}
+
+// AccountRecord reports a closed tunnel's usage for external billing.
+type AccountRecord struct {
+	Subdomain   string `json:"subdomain"`
+	Token       string `json:"token"`
+	TunnelType  string `json:"tunnel_type"`
+	BytesIn     int64  `json:"bytes_in"`
+	BytesOut    int64  `json:"bytes_out"`
+	DurationSec int64  `json:"duration_sec"`
+}
+
+// Client makes unary calls to one plugin process.
+type Client struct {
+	addr      string
+	transport *http2.Transport
+	logger    *zap.Logger
+}
+
+// NewClient creates a plugin client for addr (host:port, plaintext HTTP/2).
+func NewClient(addr string, logger *zap.Logger) *Client {
+	return &Client{
+		addr: addr,
+		transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Authorize asks the plugin to approve a tunnel registration.
+func (c *Client) Authorize(ctx context.Context, req *AuthRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := c.call(ctx, "Authorize", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Route asks the plugin for a decision on one public request.
+func (c *Client) Route(ctx context.Context, req *RouteRequest) (*RouteResponse, error) {
+	var resp RouteResponse
+	if err := c.call(ctx, "Route", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Account reports a closed tunnel's usage. Failures are logged, not
+// returned: accounting must not block teardown.
+func (c *Client) Account(rec *AccountRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	var resp struct{}
+	if err := c.call(ctx, "Account", rec, &resp); err != nil {
+		c.logger.Warn("Plugin accounting call failed", zap.Error(err))
+	}
+}
+
+// call performs one unary gRPC call with JSON-encoded messages.
+func (c *Client) call(ctx context.Context, method string, in, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	// gRPC message framing: compressed flag byte + 4-byte big-endian length.
+	body := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(body[1:5], uint32(len(payload)))
+	body = append(body, payload...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+c.addr+servicePath+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/grpc+json")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return fmt.Errorf("plugin unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	msg, err := readMessage(resp.Body)
+	if err != nil {
+		// No message usually means the server reported an error entirely in
+		// trailers (trailers-only response).
+		if status := grpcStatus(resp); status != "" && status != "0" {
+			return fmt.Errorf("plugin error (grpc-status %s): %s", status, resp.Trailer.Get("Grpc-Message"))
+		}
+		return fmt.Errorf("malformed plugin response: %w", err)
+	}
+
+	// Drain to EOF so trailers are populated.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if status := grpcStatus(resp); status != "" && status != "0" {
+		return fmt.Errorf("plugin error (grpc-status %s): %s", status, resp.Trailer.Get("Grpc-Message"))
+	}
+
+	return json.Unmarshal(msg, out)
+}
+
+// grpcStatus returns the call status from trailers, falling back to the
+// headers for trailers-only responses.
+func grpcStatus(resp *http.Response) string {
+	if s := resp.Trailer.Get("Grpc-Status"); s != "" {
+		return s
+	}
+	return resp.Header.Get("Grpc-Status")
+}
+
+// readMessage reads one length-prefixed gRPC message from the body.
+func readMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	if prefix[0] != 0 {
+		return nil, fmt.Errorf("compressed response not supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxResponseSize {
+		return nil, fmt.Errorf("response too large (%d bytes)", size)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,289 @@
+// Package policy evaluates an operator-supplied rule script against each
+// public request, covering access and routing decisions the built-in
+// controls don't. The script is one rule per line; blank lines and #
+// comments are ignored:
+//
+//	deny     host=*.internal.example.com
+//	deny     ip=203.0.113.0/24 status=451
+//	redirect path=/old/* to=https://example.com/new
+//	tag      header.User-Agent=*curl* tag=cli
+//
+// A rule is an action (deny, redirect, tag) followed by key=value tokens.
+// Match keys — host, path, ip, subdomain, tunnel, header.<Name> — must all
+// hold for the rule to fire; values support * wildcards and ip also accepts
+// CIDR notation. Action keys configure the outcome: status for deny
+// (default 403), to for redirect, tag for tag. The first matching deny or
+// redirect wins; tags accumulate and are forwarded to the tunnel client as
+// X-Drip-Tag headers. The script is re-read when its mtime changes, so
+// policies can be edited without restarting the server.
+package policy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// reloadCheckInterval is how often Evaluate stats the script file to pick up
+// edits.
+const reloadCheckInterval = 5 * time.Second
+
+// Request carries the attributes a rule can match on.
+type Request struct {
+	Host       string
+	Path       string
+	VisitorIP  string
+	Subdomain  string
+	TunnelType string
+	Header     http.Header
+}
+
+// Decision is the outcome of evaluating the script against one request.
+type Decision struct {
+	Deny       bool
+	DenyStatus int
+	RedirectTo string
+	Tags       []string
+}
+
+type condition struct {
+	key     string // host, path, ip, subdomain, tunnel, or header.<Name>
+	pattern string
+	cidr    *net.IPNet // set when key is ip and pattern is CIDR notation
+}
+
+type rule struct {
+	action     string
+	conditions []condition
+	status     int    // deny
+	to         string // redirect
+	tag        string // tag
+}
+
+// Engine holds a parsed policy script and re-parses it when the file
+// changes.
+type Engine struct {
+	path   string
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	rules     []rule
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// NewEngine parses the script at path. Parse errors are returned so a bad
+// script fails server startup instead of silently allowing everything.
+func NewEngine(path string, logger *zap.Logger) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy script: %w", err)
+	}
+	rules, err := parseRules(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Engine{
+		path:      path,
+		logger:    logger,
+		rules:     rules,
+		lastCheck: time.Now(),
+	}
+	if info, err := os.Stat(path); err == nil {
+		e.modTime = info.ModTime()
+	}
+	return e, nil
+}
+
+// Evaluate runs the script against one request. The first matching deny or
+// redirect rule decides; tag rules accumulate along the way.
+func (e *Engine) Evaluate(req *Request) Decision {
+	e.maybeReload()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var d Decision
+	for i := range e.rules {
+		r := &e.rules[i]
+		if !r.matches(req) {
+			continue
+		}
+		switch r.action {
+		case "deny":
+			d.Deny = true
+			d.DenyStatus = r.status
+			return d
+		case "redirect":
+			d.RedirectTo = r.to
+			return d
+		case "tag":
+			d.Tags = append(d.Tags, r.tag)
+		}
+	}
+	return d
+}
+
+// maybeReload re-parses the script when its mtime changed since the last
+// check. A script that became unparsable is logged and the previous rules
+// stay in effect.
+func (e *Engine) maybeReload() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if time.Since(e.lastCheck) < reloadCheckInterval {
+		return
+	}
+	e.lastCheck = time.Now()
+
+	info, err := os.Stat(e.path)
+	if err != nil || info.ModTime().Equal(e.modTime) {
+		return
+	}
+
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return
+	}
+	rules, err := parseRules(string(data))
+	if err != nil {
+		e.logger.Warn("Policy script reload failed, keeping previous rules", zap.Error(err))
+		return
+	}
+
+	e.rules = rules
+	e.modTime = info.ModTime()
+	e.logger.Info("Policy script reloaded", zap.Int("rules", len(rules)))
+}
+
+func (r *rule) matches(req *Request) bool {
+	for i := range r.conditions {
+		if !r.conditions[i].matches(req) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *condition) matches(req *Request) bool {
+	switch {
+	case c.key == "host":
+		return matchWildcard(c.pattern, stripPort(req.Host))
+	case c.key == "path":
+		return matchWildcard(c.pattern, req.Path)
+	case c.key == "subdomain":
+		return matchWildcard(c.pattern, req.Subdomain)
+	case c.key == "tunnel":
+		return matchWildcard(c.pattern, req.TunnelType)
+	case c.key == "ip":
+		if c.cidr != nil {
+			ip := net.ParseIP(req.VisitorIP)
+			return ip != nil && c.cidr.Contains(ip)
+		}
+		return matchWildcard(c.pattern, req.VisitorIP)
+	case strings.HasPrefix(c.key, "header."):
+		name := strings.TrimPrefix(c.key, "header.")
+		return matchWildcard(c.pattern, req.Header.Get(name))
+	}
+	return false
+}
+
+func parseRules(script string) ([]rule, error) {
+	var rules []rule
+	for lineNo, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		r := rule{action: fields[0], status: http.StatusForbidden}
+		switch r.action {
+		case "deny", "redirect", "tag":
+		default:
+			return nil, fmt.Errorf("policy line %d: unknown action %q", lineNo+1, r.action)
+		}
+
+		for _, token := range fields[1:] {
+			key, value, ok := strings.Cut(token, "=")
+			if !ok || value == "" {
+				return nil, fmt.Errorf("policy line %d: malformed token %q", lineNo+1, token)
+			}
+			switch {
+			case key == "status":
+				status, err := strconv.Atoi(value)
+				if err != nil || status < 100 || status > 599 {
+					return nil, fmt.Errorf("policy line %d: invalid status %q", lineNo+1, value)
+				}
+				r.status = status
+			case key == "to":
+				r.to = value
+			case key == "tag":
+				r.tag = value
+			case key == "host" || key == "path" || key == "ip" || key == "subdomain" || key == "tunnel" || strings.HasPrefix(key, "header."):
+				cond := condition{key: key, pattern: value}
+				if key == "ip" && strings.Contains(value, "/") {
+					_, cidr, err := net.ParseCIDR(value)
+					if err != nil {
+						return nil, fmt.Errorf("policy line %d: invalid CIDR %q", lineNo+1, value)
+					}
+					cond.cidr = cidr
+				}
+				r.conditions = append(r.conditions, cond)
+			default:
+				return nil, fmt.Errorf("policy line %d: unknown key %q", lineNo+1, key)
+			}
+		}
+
+		if r.action == "redirect" && r.to == "" {
+			return nil, fmt.Errorf("policy line %d: redirect requires to=", lineNo+1)
+		}
+		if r.action == "tag" && r.tag == "" {
+			return nil, fmt.Errorf("policy line %d: tag requires tag=", lineNo+1)
+		}
+		if len(r.conditions) == 0 {
+			return nil, fmt.Errorf("policy line %d: rule has no match conditions", lineNo+1)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// matchWildcard matches value against pattern, where * matches any run of
+// characters.
+func matchWildcard(pattern, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(value, part)
+		if idx == -1 {
+			return false
+		}
+		value = value[idx+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
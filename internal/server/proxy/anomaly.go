@@ -0,0 +1,142 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// anomalyWindow is the sampling bucket; rates are compared between the
+	// current and previous window.
+	anomalyWindow = time.Minute
+
+	// anomalySpikeFactor flags a window whose request count is this many
+	// times the previous window's.
+	anomalySpikeFactor = 10
+
+	// anomalyMinBaseline suppresses spike alerts on tiny baselines, where
+	// a 10x jump is just a handful of requests.
+	anomalyMinBaseline = 30
+
+	// anomalyScanThreshold is how many scanner-looking paths in one window
+	// trigger an alert.
+	anomalyScanThreshold = 12
+
+	// anomalyAlertCooldown spaces repeat alerts for the same tunnel+rule.
+	anomalyAlertCooldown = 10 * time.Minute
+)
+
+// scannerPathFragments are substrings typical of automated vulnerability
+// scanners rather than real application traffic.
+var scannerPathFragments = []string{
+	"/wp-", "/.env", "/.git", ".php", "/phpmyadmin", "/cgi-bin",
+	"/etc/passwd", "/vendor/phpunit", "/actuator", "/.aws", "/config.json",
+}
+
+// anomalyDetector keeps per-subdomain traffic counters and raises alerts
+// on sudden spikes, visitor-IP surges, and scanner-like path patterns.
+// Alerts reach the tunnel owner through the event bus like quota warnings.
+type anomalyDetector struct {
+	mu   sync.Mutex
+	subs map[string]*anomalyState
+}
+
+type anomalyState struct {
+	windowStart  time.Time
+	requests     int
+	prevRequests int
+	ips          map[string]struct{}
+	prevIPs      int
+	scanHits     int
+	lastAlert    map[string]time.Time // rule -> last alert time
+}
+
+func newAnomalyDetector() *anomalyDetector {
+	return &anomalyDetector{
+		subs: make(map[string]*anomalyState),
+	}
+}
+
+// observe records one request and returns any alert messages it triggers.
+func (d *anomalyDetector) observe(subdomain, path, visitorIP string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	state, ok := d.subs[subdomain]
+	if !ok {
+		state = &anomalyState{
+			windowStart: now,
+			ips:         make(map[string]struct{}),
+			lastAlert:   make(map[string]time.Time),
+		}
+		d.subs[subdomain] = state
+	}
+
+	if now.Sub(state.windowStart) >= anomalyWindow {
+		state.prevRequests = state.requests
+		state.prevIPs = len(state.ips)
+		state.requests = 0
+		state.scanHits = 0
+		state.ips = make(map[string]struct{})
+		state.windowStart = now
+	}
+
+	state.requests++
+	state.ips[visitorIP] = struct{}{}
+	if isScannerPath(path) {
+		state.scanHits++
+	}
+
+	var alerts []string
+
+	if state.prevRequests >= anomalyMinBaseline &&
+		state.requests >= state.prevRequests*anomalySpikeFactor {
+		if state.allowAlert("spike", now) {
+			alerts = append(alerts, fmt.Sprintf(
+				"traffic spike: %d requests this minute vs %d the minute before",
+				state.requests, state.prevRequests))
+		}
+	}
+
+	if state.prevIPs >= anomalyMinBaseline/3 &&
+		len(state.ips) >= state.prevIPs*anomalySpikeFactor {
+		if state.allowAlert("ips", now) {
+			alerts = append(alerts, fmt.Sprintf(
+				"visitor surge: %d distinct IPs this minute vs %d the minute before",
+				len(state.ips), state.prevIPs))
+		}
+	}
+
+	if state.scanHits >= anomalyScanThreshold {
+		if state.allowAlert("scan", now) {
+			alerts = append(alerts, fmt.Sprintf(
+				"scanner-like traffic: %d probe paths this minute (last: %s)",
+				state.scanHits, path))
+		}
+	}
+
+	return alerts
+}
+
+// allowAlert reports whether the rule is past its cooldown and marks it
+// fired. Caller must hold d.mu.
+func (s *anomalyState) allowAlert(rule string, now time.Time) bool {
+	if last, ok := s.lastAlert[rule]; ok && now.Sub(last) < anomalyAlertCooldown {
+		return false
+	}
+	s.lastAlert[rule] = now
+	return true
+}
+
+func isScannerPath(path string) bool {
+	path = strings.ToLower(path)
+	for _, fragment := range scannerPathFragments {
+		if strings.Contains(path, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// enumWindow is how long unknown-subdomain misses are remembered per IP.
+	enumWindow = time.Minute
+
+	// enumTarpitThreshold is how many misses in one window start delaying
+	// responses to that IP.
+	enumTarpitThreshold = 8
+
+	// enumBanThreshold is how many misses in one window get the IP refused
+	// outright, including on live subdomains.
+	enumBanThreshold = 24
+
+	// enumTarpitDelay is the artificial latency added to each 404 once an
+	// IP is tarpitted, making enumeration sweeps expensive.
+	enumTarpitDelay = 2 * time.Second
+
+	// enumBanDuration is how long a banned IP stays refused.
+	enumBanDuration = 10 * time.Minute
+
+	// enumMaxTracked caps the per-IP map; stale entries are pruned when the
+	// cap is hit so a spoofed sweep cannot grow memory without bound.
+	enumMaxTracked = 10000
+)
+
+// enumGuardAction is the verdict for one unknown-subdomain request.
+type enumGuardAction int
+
+const (
+	enumAllow enumGuardAction = iota
+	enumTarpit
+	enumBan
+)
+
+// enumGuard tracks visitors hitting unknown subdomains. Scanners walking
+// random names rack up misses from one IP and get tarpitted, then banned,
+// so enumeration cannot cheaply discover short-lived tunnels. Legitimate
+// visitors following a stale link see at most one slow 404.
+type enumGuard struct {
+	mu  sync.Mutex
+	ips map[string]*enumState
+}
+
+type enumState struct {
+	windowStart time.Time
+	misses      int
+	bannedUntil time.Time
+}
+
+func newEnumGuard() *enumGuard {
+	return &enumGuard{
+		ips: make(map[string]*enumState),
+	}
+}
+
+// miss records an unknown-subdomain hit from visitorIP and returns how the
+// 404 should be served. The returned bool is true the moment a ban starts,
+// so the caller can log it once rather than per request.
+func (g *enumGuard) miss(visitorIP string) (enumGuardAction, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	state, ok := g.ips[visitorIP]
+	if !ok {
+		if len(g.ips) >= enumMaxTracked {
+			g.prune(now)
+		}
+		state = &enumState{windowStart: now}
+		g.ips[visitorIP] = state
+	}
+
+	if now.Before(state.bannedUntil) {
+		return enumBan, false
+	}
+
+	if now.Sub(state.windowStart) >= enumWindow {
+		state.windowStart = now
+		state.misses = 0
+	}
+	state.misses++
+
+	switch {
+	case state.misses >= enumBanThreshold:
+		state.bannedUntil = now.Add(enumBanDuration)
+		return enumBan, true
+	case state.misses >= enumTarpitThreshold:
+		return enumTarpit, false
+	default:
+		return enumAllow, false
+	}
+}
+
+// banned reports whether visitorIP is currently refused. Checked on every
+// proxied request so a banned scanner cannot confirm live subdomains either.
+func (g *enumGuard) banned(visitorIP string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.ips[visitorIP]
+	return ok && time.Now().Before(state.bannedUntil)
+}
+
+// prune drops entries whose window has lapsed and whose ban has expired.
+// Caller must hold g.mu.
+func (g *enumGuard) prune(now time.Time) {
+	for ip, state := range g.ips {
+		if now.Sub(state.windowStart) >= enumWindow && !now.Before(state.bannedUntil) {
+			delete(g.ips, ip)
+		}
+	}
+}
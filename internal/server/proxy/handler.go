@@ -9,36 +9,83 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	json "github.com/goccy/go-json"
 
+	"drip/internal/server/plugin"
+	"drip/internal/server/policy"
 	"drip/internal/server/tunnel"
+	"drip/internal/shared/constants"
 	"drip/internal/shared/httputil"
 	"drip/internal/shared/netutil"
 	"drip/internal/shared/protocol"
+	"drip/internal/shared/utils"
 
 	"go.uber.org/zap"
 )
 
-const openStreamTimeout = 10 * time.Second
-
 type Handler struct {
-	manager   *tunnel.Manager
-	logger    *zap.Logger
-	domain    string
-	authToken string
+	ctx            context.Context
+	manager        *tunnel.Manager
+	router         *tunnel.Router
+	logger         *zap.Logger
+	domain         string
+	authToken      string
+	maxTunnelBytes int64
+	memBudget      int64
+	policy         *policy.Engine // nil when no policy script is configured
+	plugins        *plugin.Client // nil when no plugin is configured
+	quotaWarned    sync.Map       // subdomain -> struct{}; quota warning sent once per tunnel
+	sessions       *sessionStore  // admin dashboard login sessions
+	totpSecret     string         // empty disables the TOTP login factor
+	observerToken  string         // read-only token; empty disables the observer role
+	statusPage     bool           // serve the public status page on status.<domain>
+	anomaly        *anomalyDetector
+	enum           *enumGuard
+	incidentsMu    sync.Mutex
+	incidents      []*incident // operator-declared incidents for the status page
+	nextIncidentID int64
 }
 
-func NewHandler(manager *tunnel.Manager, logger *zap.Logger, domain string, authToken string) *Handler {
+// NewHandler creates the public HTTP proxy handler. ctx is the server's root
+// context; cancelling it aborts long-lived forwarding (e.g. WebSocket pipes)
+// during shutdown. maxTunnelBytes caps cumulative per-tunnel traffic and
+// memBudget caps a tunnel's in-flight request body bytes; zero disables
+// either limit.
+func NewHandler(ctx context.Context, manager *tunnel.Manager, logger *zap.Logger, domain string, authToken string, maxTunnelBytes, memBudget int64) *Handler {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	return &Handler{
-		manager:   manager,
-		logger:    logger,
-		domain:    domain,
-		authToken: authToken,
+		ctx:            ctx,
+		manager:        manager,
+		router:         tunnel.NewRouter(manager, domain),
+		logger:         logger,
+		domain:         domain,
+		authToken:      authToken,
+		maxTunnelBytes: maxTunnelBytes,
+		memBudget:      memBudget,
+		sessions:       newSessionStore(),
+		anomaly:        newAnomalyDetector(),
+		enum:           newEnumGuard(),
 	}
 }
 
+// SetPolicy installs an operator policy script evaluated against every
+// tunnel-bound request.
+func (h *Handler) SetPolicy(engine *policy.Engine) {
+	h.policy = engine
+}
+
+// SetPlugin installs a plugin client asked to route every tunnel-bound
+// request. Plugin failures let the request through; only explicit decisions
+// deny or redirect.
+func (h *Handler) SetPlugin(client *plugin.Client) {
+	h.plugins = client
+}
+
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path == "/health" {
 		h.serveHealth(w, r)
@@ -48,15 +95,42 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.serveStats(w, r)
 		return
 	}
+	// The router owns endpoint resolution: subdomains, aliases, and CNAMEd
+	// custom domains all land on the same lookup.
+	subdomain, tconn, ok := h.router.RouteHost(r.Host)
+	if subdomain == "" && !ok {
+		h.serveApex(w, r)
+		return
+	}
+	if subdomain == "status" && h.statusPage {
+		h.serveStatusPage(w, r)
+		return
+	}
 
-	subdomain := h.extractSubdomain(r.Host)
-	if subdomain == "" {
-		h.serveHomePage(w, r)
+	enumIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		enumIP = host
+	}
+	if h.enum.banned(enumIP) {
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
 		return
 	}
 
-	tconn, ok := h.manager.Get(subdomain)
 	if !ok || tconn == nil {
+		action, bannedNow := h.enum.miss(enumIP)
+		if bannedNow {
+			h.logger.Warn("Banning IP for subdomain enumeration",
+				zap.String("ip", enumIP),
+			)
+		}
+		if action == enumBan {
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+		if action == enumTarpit {
+			// Slow the sweep down without tying up the visitor forever.
+			time.Sleep(enumTarpitDelay)
+		}
 		http.Error(w, "Tunnel not found. The tunnel may have been closed.", http.StatusNotFound)
 		return
 	}
@@ -76,12 +150,135 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if httputil.IsWebSocketUpgrade(r) {
+	anomalyIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		anomalyIP = host
+	}
+	for _, msg := range h.anomaly.observe(subdomain, r.URL.Path, anomalyIP) {
+		h.manager.Events().Publish(protocol.EventAnomaly, subdomain, msg)
+		h.logger.Warn("Traffic anomaly detected",
+			zap.String("subdomain", subdomain),
+			zap.String("tunnel_id", tconn.TunnelID()),
+			zap.String("detail", msg),
+		)
+	}
+
+	if h.policy != nil {
+		visitorIP := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			visitorIP = host
+		}
+		decision := h.policy.Evaluate(&policy.Request{
+			Host:       r.Host,
+			Path:       r.URL.Path,
+			VisitorIP:  visitorIP,
+			Subdomain:  subdomain,
+			TunnelType: string(tType),
+			Header:     r.Header,
+		})
+		if decision.RedirectTo != "" {
+			http.Redirect(w, r, decision.RedirectTo, http.StatusFound)
+			return
+		}
+		if decision.Deny {
+			http.Error(w, "Denied by server policy", decision.DenyStatus)
+			return
+		}
+		for _, tag := range decision.Tags {
+			r.Header.Add("X-Drip-Tag", tag)
+		}
+	}
+
+	if h.plugins != nil {
+		visitorIP := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			visitorIP = host
+		}
+		routeResp, err := h.plugins.Route(r.Context(), &plugin.RouteRequest{
+			Host:      r.Host,
+			Path:      r.URL.Path,
+			Subdomain: subdomain,
+			VisitorIP: visitorIP,
+		})
+		if err != nil {
+			h.logger.Debug("Plugin route call failed, continuing", zap.Error(err))
+		} else {
+			if routeResp.RedirectTo != "" {
+				http.Redirect(w, r, routeResp.RedirectTo, http.StatusFound)
+				return
+			}
+			if routeResp.Deny {
+				http.Error(w, "Denied by server plugin", http.StatusForbidden)
+				return
+			}
+			for _, tag := range routeResp.Tags {
+				r.Header.Add("X-Drip-Tag", tag)
+			}
+		}
+	}
+
+	if on, msg := tconn.Maintenance(); on {
+		h.serveMaintenancePage(w, msg)
+		return
+	}
+
+	if target, redirect := tconn.MatchRedirect(r.URL.Path); redirect {
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+
+	if h.maxTunnelBytes > 0 {
+		used := tconn.GetBytesIn() + tconn.GetBytesOut()
+		if used >= h.maxTunnelBytes {
+			http.Error(w, "Tunnel traffic limit exceeded", http.StatusForbidden)
+			return
+		}
+		// Warn subscribed clients once when a tunnel nears its quota.
+		if used >= h.maxTunnelBytes*9/10 {
+			if _, warned := h.quotaWarned.LoadOrStore(subdomain, struct{}{}); !warned {
+				h.manager.Events().Publish(protocol.EventQuotaWarning, subdomain,
+					fmt.Sprintf("tunnel has used %d of %d allowed bytes", used, h.maxTunnelBytes))
+			}
+		}
+	}
+
+	// Fast-fail instead of queueing unboundedly when the tunnel client
+	// can't keep up with its pending requests.
+	if tconn.GetActiveConnections() >= constants.MaxPendingPerTunnel {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Tunnel overloaded, too many pending requests", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Enforce the per-tunnel memory budget before buffering anything: a body
+	// that can never fit gets 413, and a tunnel whose in-flight bodies already
+	// fill the budget gets 503 until some of them drain.
+	if h.memBudget > 0 {
+		if r.ContentLength > h.memBudget {
+			http.Error(w, "Request body too large for tunnel memory budget", http.StatusRequestEntityTooLarge)
+			return
+		}
+		reserve := r.ContentLength
+		if reserve < 0 {
+			reserve = 0
+		}
+		if !tconn.ReserveMemory(reserve, h.memBudget) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Tunnel memory budget exceeded", http.StatusServiceUnavailable)
+			return
+		}
+		defer tconn.ReleaseMemory(reserve)
+	}
+
+	if httputil.IsConnectionUpgrade(r) {
 		h.handleWebSocket(w, r, tconn)
 		return
 	}
 
-	stream, err := h.openStreamWithTimeout(tconn)
+	stream, err := h.openStreamWithTimeout(r.Context(), tconn)
 	if err != nil {
 		w.Header().Set("Connection", "close")
 		http.Error(w, "Tunnel unavailable", http.StatusBadGateway)
@@ -92,6 +289,20 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	tconn.IncActiveConnections()
 	defer tconn.DecActiveConnections()
 
+	// Propagate visitor disconnects to the tunnel client immediately:
+	// closing the stream lets the client abort the in-flight local request
+	// instead of completing it and dropping the response.
+	ctx := r.Context()
+	watchDone := make(chan struct{})
+	defer close(watchDone)
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.Close()
+		case <-watchDone:
+		}
+	}()
+
 	countingStream := netutil.NewCountingConn(stream,
 		tconn.AddBytesOut,
 		tconn.AddBytesIn,
@@ -137,22 +348,73 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(statusCode)
 
-	ctx := r.Context()
-	done := make(chan struct{})
-	go func() {
-		select {
-		case <-ctx.Done():
-			stream.Close()
-		case <-done:
+	var body io.Writer = netutil.FairWriter(tconn.Subdomain, w)
+	if resp.ContentLength < 0 {
+		// Unknown length means the tunnel client is streaming (SSE,
+		// gRPC-web, chunked responses): flush each write so messages reach
+		// h1 and h2 visitors as they arrive instead of when the server's
+		// buffer fills.
+		if f, ok := w.(http.Flusher); ok {
+			body = flushWriter{dst: body, f: f}
 		}
-	}()
+	}
 
-	_, _ = io.Copy(w, resp.Body)
-	close(done)
+	_, _ = io.Copy(body, resp.Body)
 	stream.Close()
 }
 
-func (h *Handler) openStreamWithTimeout(tconn *tunnel.Connection) (net.Conn, error) {
+// flushWriter flushes the response writer after every write.
+type flushWriter struct {
+	dst io.Writer
+	f   http.Flusher
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.dst.Write(p)
+	if n > 0 {
+		fw.f.Flush()
+	}
+	return n, err
+}
+
+// serveApex dispatches requests addressed to the server itself rather than
+// a tunnel: the admin and control endpoints, and the home page for
+// everything else. Matching these paths only on the apex domain keeps them
+// from shadowing identically named routes on tunneled applications.
+func (h *Handler) serveApex(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/uptime":
+		h.serveUptime(w, r)
+	case "/history":
+		h.serveHistory(w, r)
+	case "/maintenance":
+		h.serveMaintenanceControl(w, r)
+	case "/subdomain":
+		h.serveSubdomainCheck(w, r)
+	case "/subdomain/reserve":
+		h.serveSubdomainReserve(w, r)
+	case "/subdomain/release":
+		h.serveSubdomainRelease(w, r)
+	case "/incident":
+		h.serveIncidentControl(w, r)
+	case "/notice":
+		h.serveNotice(w, r)
+	case "/login":
+		h.serveLogin(w, r)
+	case "/logout":
+		h.serveLogout(w, r)
+	default:
+		h.serveHomePage(w, r)
+	}
+}
+
+func (h *Handler) openStreamWithTimeout(ctx context.Context, tconn *tunnel.Connection) (net.Conn, error) {
+	if ctx == nil {
+		ctx = h.ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, constants.OpenStreamTimeout)
+	defer cancel()
+
 	type result struct {
 		stream net.Conn
 		err    error
@@ -161,19 +423,26 @@ func (h *Handler) openStreamWithTimeout(tconn *tunnel.Connection) (net.Conn, err
 
 	go func() {
 		s, err := tconn.OpenStream()
-		ch <- result{s, err}
+		select {
+		case ch <- result{s, err}:
+		case <-ctx.Done():
+			// Request abandoned; don't leak the stream.
+			if s != nil {
+				s.Close()
+			}
+		}
 	}()
 
 	select {
 	case r := <-ch:
 		return r.stream, r.err
-	case <-time.After(openStreamTimeout):
-		return nil, fmt.Errorf("open stream timeout")
+	case <-ctx.Done():
+		return nil, fmt.Errorf("open stream timeout: %w", ctx.Err())
 	}
 }
 
 func (h *Handler) handleWebSocket(w http.ResponseWriter, r *http.Request, tconn *tunnel.Connection) {
-	stream, err := h.openStreamWithTimeout(tconn)
+	stream, err := h.openStreamWithTimeout(r.Context(), tconn)
 	if err != nil {
 		http.Error(w, "Tunnel unavailable", http.StatusBadGateway)
 		return
@@ -217,7 +486,10 @@ func (h *Handler) handleWebSocket(w http.ResponseWriter, r *http.Request, tconn
 			}
 		}
 
-		_ = netutil.PipeWithCallbacks(context.Background(), stream, clientRW,
+		// Use the server's root context (not the request context, which is
+		// cancelled once ServeHTTP returns) so the pipe survives until either
+		// side disconnects or the server shuts down.
+		_ = netutil.PipeWithCallbacks(h.ctx, stream, clientRW,
 			func(n int64) { tconn.AddBytesOut(n) },
 			func(n int64) { tconn.AddBytesIn(n) },
 		)
@@ -226,7 +498,7 @@ func (h *Handler) handleWebSocket(w http.ResponseWriter, r *http.Request, tconn
 
 func (h *Handler) copyResponseHeaders(dst http.Header, src http.Header, proxyHost string) {
 	for key, values := range src {
-		canonicalKey := http.CanonicalHeaderKey(key)
+		canonicalKey := httputil.InternHeaderKey(key)
 
 		// Hop-by-hop headers must not be forwarded.
 		if canonicalKey == "Connection" ||
@@ -244,9 +516,10 @@ func (h *Handler) copyResponseHeaders(dst http.Header, src http.Header, proxyHos
 			continue
 		}
 
-		for _, value := range values {
-			dst.Add(key, value)
-		}
+		// The key is already canonical and the response is discarded after
+		// forwarding, so append the parsed value slices directly instead of
+		// re-canonicalizing and copying per value.
+		dst[canonicalKey] = append(dst[canonicalKey], values...)
 	}
 }
 
@@ -277,23 +550,6 @@ func (h *Handler) rewriteLocationHeader(location, proxyHost string) string {
 	return location
 }
 
-func (h *Handler) extractSubdomain(host string) string {
-	if idx := strings.Index(host, ":"); idx != -1 {
-		host = host[:idx]
-	}
-
-	if host == h.domain {
-		return ""
-	}
-
-	suffix := "." + h.domain
-	if strings.HasSuffix(host, suffix) {
-		return strings.TrimSuffix(host, suffix)
-	}
-
-	return ""
-}
-
 func (h *Handler) serveHomePage(w http.ResponseWriter, r *http.Request) {
 	html := `<!DOCTYPE html>
 <html>
@@ -347,8 +603,50 @@ func (h *Handler) serveHealth(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-func (h *Handler) serveStats(w http.ResponseWriter, r *http.Request) {
-	if h.authToken != "" {
+// isAuthorized checks the request token (query param or Bearer header)
+// against the server auth token. Always true when auth is disabled.
+func (h *Handler) isAuthorized(r *http.Request) bool {
+	if h.authToken == "" {
+		return true
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		authHeader := r.Header.Get("Authorization")
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			token = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+	}
+
+	if token == h.authToken {
+		return true
+	}
+
+	// Dashboard browsers authenticate with a session cookie from /login
+	// (CSRF-checked for state-changing requests) instead of the raw token.
+	if h.sessionAuthorized(r) {
+		h.auditAdmin(r, "admin_request", "")
+		return true
+	}
+
+	return false
+}
+
+// SetObserverToken enables a read-only role: requests (and /login
+// sessions) carrying this token can view stats and tunnels but are
+// refused by every state-changing admin endpoint.
+func (h *Handler) SetObserverToken(token string) {
+	h.observerToken = token
+}
+
+// isReadAuthorized allows full admins plus read-only observers. View-only
+// endpoints use this; destructive ones keep isAuthorized.
+func (h *Handler) isReadAuthorized(r *http.Request) bool {
+	if h.isAuthorized(r) {
+		return true
+	}
+
+	if h.observerToken != "" {
 		token := r.URL.Query().Get("token")
 		if token == "" {
 			authHeader := r.Header.Get("Authorization")
@@ -356,11 +654,292 @@ func (h *Handler) serveStats(w http.ResponseWriter, r *http.Request) {
 				token = strings.TrimPrefix(authHeader, "Bearer ")
 			}
 		}
+		if token == h.observerToken {
+			return true
+		}
+	}
+
+	return h.sessionFor(r) != nil
+}
+
+func (h *Handler) serveMaintenancePage(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "This tunnel is temporarily down for maintenance."
+	}
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="UTF-8" />
+	<title>Maintenance - Drip</title>
+	<style>
+		body { font-family: Arial, sans-serif; max-width: 600px; margin: 100px auto; padding: 20px; text-align: center; }
+		h1 { color: #333; }
+		p { color: #666; }
+	</style>
+</head>
+<body>
+	<h1>🔧 Under Maintenance</h1>
+	<p>%s</p>
+</body>
+</html>`, htmlEscape(message))
+
+	data := []byte(html)
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Header().Set("Retry-After", "60")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write(data)
+}
+
+// serveMaintenanceControl toggles maintenance mode for a tunnel.
+// GET/POST /maintenance?subdomain=<name>&on=<true|false>&message=<text>
+func (h *Handler) serveMaintenanceControl(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	subdomain := r.URL.Query().Get("subdomain")
+	if subdomain == "" {
+		http.Error(w, "Missing subdomain parameter", http.StatusBadRequest)
+		return
+	}
+
+	tconn, ok := h.manager.Get(subdomain)
+	if !ok || tconn == nil {
+		http.Error(w, "Tunnel not found", http.StatusNotFound)
+		return
+	}
+
+	on := r.URL.Query().Get("on") == "true"
+	message := r.URL.Query().Get("message")
+	tconn.SetMaintenance(on, message)
+
+	h.logger.Info("Maintenance mode changed",
+		zap.String("subdomain", subdomain),
+		zap.Bool("on", on),
+	)
+
+	result := map[string]interface{}{
+		"subdomain":   subdomain,
+		"maintenance": on,
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveNotice broadcasts an admin notice to all subscribed clients.
+// GET/POST /notice?message=<text>&subdomain=<name>
+// subdomain is optional; when set, only clients displaying that tunnel
+// surface the notice.
+func (h *Handler) serveNotice(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	message := r.URL.Query().Get("message")
+	if message == "" {
+		http.Error(w, "Missing message parameter", http.StatusBadRequest)
+		return
+	}
+	subdomain := r.URL.Query().Get("subdomain")
+
+	h.manager.Events().Publish(protocol.EventAdminNotice, subdomain, message)
+
+	h.logger.Info("Admin notice broadcast",
+		zap.String("subdomain", subdomain),
+	)
+
+	result := map[string]interface{}{
+		"status": "sent",
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveSubdomainCheck reports whether a subdomain is free to register.
+// GET /subdomain?name=<name>
+func (h *Handler) serveSubdomainCheck(w http.ResponseWriter, r *http.Request) {
+	if !h.isReadAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Missing name parameter", http.StatusBadRequest)
+		return
+	}
+
+	status := "available"
+	switch {
+	case !utils.ValidateSubdomain(name):
+		status = "invalid"
+	case utils.IsReserved(name):
+		status = "reserved"
+	default:
+		if _, ok := h.manager.Get(name); ok {
+			status = "taken"
+		} else if _, aliased := h.manager.ResolveAlias(name); aliased {
+			status = "alias"
+		} else if h.manager.IsReservedClaim(name) {
+			status = "claimed"
+		}
+	}
+
+	result := map[string]interface{}{
+		"name":   name,
+		"status": status,
+	}
 
-		if token != h.authToken {
-			http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+	data, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveSubdomainReserve claims a subdomain for the caller's token without an
+// active tunnel, so the URL can be handed out before the tunnel exists.
+// POST /subdomain/reserve?name=<name>&ttl=<duration>
+func (h *Handler) serveSubdomainReserve(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Missing name parameter", http.StatusBadRequest)
+		return
+	}
+
+	ttl := 30 * 24 * time.Hour
+	if ttlParam := r.URL.Query().Get("ttl"); ttlParam != "" {
+		parsed, err := time.ParseDuration(ttlParam)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid ttl parameter", http.StatusBadRequest)
 			return
 		}
+		ttl = parsed
+	}
+
+	expires, err := h.manager.Reserve(name, h.requestToken(r), ttl)
+	if err != nil {
+		switch err {
+		case tunnel.ErrInvalidSubdomain, tunnel.ErrReservedSubdomain:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+		return
+	}
+
+	result := map[string]interface{}{
+		"name":       name,
+		"expires_at": expires.Format(time.RFC3339),
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveSubdomainRelease drops a reservation made by the caller's token.
+// POST /subdomain/release?name=<name>
+func (h *Handler) serveSubdomainRelease(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Missing name parameter", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.manager.ReleaseReservation(name, h.requestToken(r)); err != nil {
+		switch err {
+		case tunnel.ErrTunnelNotFound:
+			http.Error(w, "No reservation for that subdomain", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusConflict)
+		}
+		return
+	}
+
+	result := map[string]interface{}{
+		"name":     name,
+		"released": true,
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// requestToken extracts the caller's token the same way isAuthorized does;
+// it identifies the reservation owner.
+func (h *Handler) requestToken(r *http.Request) string {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		authHeader := r.Header.Get("Authorization")
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			token = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+	}
+	return token
+}
+
+func htmlEscape(s string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+	)
+	return replacer.Replace(s)
+}
+
+func (h *Handler) serveStats(w http.ResponseWriter, r *http.Request) {
+	if !h.isReadAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
 	}
 
 	connections := h.manager.List()
@@ -368,21 +947,29 @@ func (h *Handler) serveStats(w http.ResponseWriter, r *http.Request) {
 	stats := map[string]interface{}{
 		"total_tunnels": len(connections),
 		"tunnels":       []map[string]interface{}{},
+		"lifetime":      h.manager.LifetimeSnapshot(),
 	}
 
 	for _, conn := range connections {
 		if conn == nil {
 			continue
 		}
-		stats["tunnels"] = append(stats["tunnels"].([]map[string]interface{}), map[string]interface{}{
+		entry := map[string]interface{}{
 			"subdomain":          conn.Subdomain,
 			"tunnel_type":        string(conn.GetTunnelType()),
 			"last_active":        conn.LastActive.Unix(),
 			"bytes_in":           conn.GetBytesIn(),
 			"bytes_out":          conn.GetBytesOut(),
 			"active_connections": conn.GetActiveConnections(),
+			"pending_requests":   conn.GetActiveConnections(),
+			"max_pending":        constants.MaxPendingPerTunnel,
 			"total_bytes":        conn.GetBytesIn() + conn.GetBytesOut(),
-		})
+			"latency_ms":         conn.GetLatency().Milliseconds(),
+		}
+		if services := conn.GetServices(); len(services) > 0 {
+			entry["services"] = services
+		}
+		stats["tunnels"] = append(stats["tunnels"].([]map[string]interface{}), entry)
 	}
 
 	data, err := json.Marshal(stats)
@@ -396,6 +983,71 @@ func (h *Handler) serveStats(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
+// serveUptime reports per-subdomain availability since the server started,
+// for teams treating certain tunnels as quasi-production.
+// GET /uptime[?subdomain=<name>]
+func (h *Handler) serveUptime(w http.ResponseWriter, r *http.Request) {
+	if !h.isReadAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	reports := h.manager.UptimeSnapshot()
+
+	if name := r.URL.Query().Get("subdomain"); name != "" {
+		for _, report := range reports {
+			if report.Subdomain == name {
+				reports = []tunnel.UptimeReport{report}
+				break
+			}
+		}
+		if len(reports) != 1 || reports[0].Subdomain != name {
+			http.Error(w, "No uptime record for that subdomain", http.StatusNotFound)
+			return
+		}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"uptime": reports,
+	})
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveHistory returns the persisted registration history (who connected
+// which subdomain when, from where, and how much traffic it carried).
+// Entries include tokens, so this stays admin-only.
+// GET /history[?subdomain=<name>][&filter_token=<token>]
+func (h *Handler) serveHistory(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	entries := h.manager.HistorySnapshot(
+		r.URL.Query().Get("subdomain"),
+		r.URL.Query().Get("filter_token"),
+	)
+
+	data, err := json.Marshal(map[string]interface{}{
+		"history": entries,
+	})
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
 type bufferedReadWriteCloser struct {
 	*bufio.Reader
 	net.Conn
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"drip/internal/server/tunnel"
+	"go.uber.org/zap"
+)
+
+// Allocation budget for the cheapest full request through ServeHTTP (the
+// /health endpoint): dispatch, the response recorder, and the JSON body. A
+// loose ceiling so per-request handling can't silently grow an allocation
+// habit. Compare BenchmarkServeHTTPHealth with benchstat when tuning.
+const healthAllocBudget = 64
+
+func newBenchHandler(tb testing.TB) *Handler {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := tunnel.NewManager(zap.NewNop())
+	tb.Cleanup(func() {
+		cancel()
+		manager.Shutdown()
+	})
+	return NewHandler(ctx, manager, zap.NewNop(), "drip.example.com", "", 0, 0)
+}
+
+func BenchmarkServeHTTPHealth(b *testing.B) {
+	h := newBenchHandler(b)
+	req := httptest.NewRequest(http.MethodGet, "http://drip.example.com/health", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			b.Fatalf("unexpected status %d", rec.Code)
+		}
+	}
+}
+
+func TestServeHTTPHealthAllocBudget(t *testing.T) {
+	h := newBenchHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "http://drip.example.com/health", nil)
+
+	allocs := testing.AllocsPerRun(200, func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d", rec.Code)
+		}
+	})
+	if allocs > healthAllocBudget {
+		t.Errorf("/health request allocates %.1f objects, budget is %d",
+			allocs, healthAllocBudget)
+	}
+}
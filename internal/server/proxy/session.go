@@ -0,0 +1,267 @@
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"go.uber.org/zap"
+)
+
+const (
+	sessionCookieName = "drip_session"
+	csrfHeaderName    = "X-Drip-Csrf"
+	sessionTTL        = 12 * time.Hour
+)
+
+// adminSession is one logged-in admin browser session. The CSRF token is
+// issued at login and must accompany every state-changing request made
+// with the session cookie.
+type adminSession struct {
+	csrf     string
+	expires  time.Time
+	readOnly bool // observer sessions may view but not change anything
+}
+
+// sessionStore holds admin sessions in memory; sessions do not survive a
+// server restart, which is acceptable for an operator dashboard.
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*adminSession
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*adminSession),
+	}
+}
+
+// create issues a new session, returning its ID and CSRF token.
+func (s *sessionStore) create(readOnly bool) (id, csrf string) {
+	id = randomHex(32)
+	csrf = randomHex(32)
+
+	s.mu.Lock()
+	s.prune()
+	s.sessions[id] = &adminSession{
+		csrf:     csrf,
+		expires:  time.Now().Add(sessionTTL),
+		readOnly: readOnly,
+	}
+	s.mu.Unlock()
+
+	return id, csrf
+}
+
+// get returns the live session for id, or nil.
+func (s *sessionStore) get(id string) *adminSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(sess.expires) {
+		delete(s.sessions, id)
+		return nil
+	}
+	return sess
+}
+
+func (s *sessionStore) delete(id string) {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+}
+
+// prune drops expired sessions. Caller must hold s.mu.
+func (s *sessionStore) prune() {
+	now := time.Now()
+	for id, sess := range s.sessions {
+		if now.After(sess.expires) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// SetAdminTOTP requires a time-based one-time code (RFC 6238, as produced
+// by any authenticator app) in addition to the token when logging in.
+// secret is the shared base32 secret.
+func (h *Handler) SetAdminTOTP(secret string) {
+	h.totpSecret = strings.ToUpper(strings.TrimSpace(secret))
+}
+
+// serveLogin exchanges the server token (plus a TOTP code when configured)
+// for a session cookie and CSRF token.
+// POST /login with form fields token= and totp=
+func (h *Handler) serveLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Malformed form body", http.StatusBadRequest)
+		return
+	}
+
+	token := r.PostFormValue("token")
+	admin := h.authToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.authToken)) == 1
+	observer := h.observerToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.observerToken)) == 1
+	if !admin && !observer {
+		h.auditAdmin(r, "login_failed", "invalid token")
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if h.totpSecret != "" {
+		if !verifyTOTP(h.totpSecret, r.PostFormValue("totp"), time.Now()) {
+			h.auditAdmin(r, "login_failed", "invalid TOTP code")
+			http.Error(w, "Invalid TOTP code", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	id, csrf := h.sessions.create(!admin)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(sessionTTL.Seconds()),
+	})
+
+	role := "admin"
+	if !admin {
+		role = "observer"
+	}
+	h.auditAdmin(r, "login", role)
+
+	data, _ := json.Marshal(map[string]string{"csrf": csrf})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveLogout drops the caller's session.
+// POST /logout
+func (h *Handler) serveLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		h.sessions.delete(cookie.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:   sessionCookieName,
+		Value:  "",
+		Path:   "/",
+		MaxAge: -1,
+	})
+
+	h.auditAdmin(r, "logout", "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionFor returns the live session carried by the request, after CSRF
+// validation for anything but GET/HEAD. Returns nil when the request has
+// no usable session.
+func (h *Handler) sessionFor(r *http.Request) *adminSession {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil
+	}
+
+	sess := h.sessions.get(cookie.Value)
+	if sess == nil {
+		return nil
+	}
+
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return sess
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(csrfHeaderName)), []byte(sess.csrf)) != 1 {
+		return nil
+	}
+	return sess
+}
+
+// sessionAuthorized reports whether the request carries a full-access
+// (non-observer) session.
+func (h *Handler) sessionAuthorized(r *http.Request) bool {
+	sess := h.sessionFor(r)
+	return sess != nil && !sess.readOnly
+}
+
+// auditAdmin records an admin action with enough context to reconstruct
+// who did what; the log stream is what syslog forwarding ships to SIEMs.
+func (h *Handler) auditAdmin(r *http.Request, action, detail string) {
+	fields := []zap.Field{
+		zap.String("action", action),
+		zap.String("path", r.URL.Path),
+		zap.String("remote_addr", r.RemoteAddr),
+	}
+	if detail != "" {
+		fields = append(fields, zap.String("detail", detail))
+	}
+	h.logger.Info("Admin action", fields...)
+}
+
+// totpStep is the RFC 6238 time step.
+const totpStep = 30 * time.Second
+
+// verifyTOTP checks a 6-digit code against the shared secret, accepting
+// the previous and next time step to absorb clock skew.
+func verifyTOTP(secret, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != 6 {
+		return false
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return false
+	}
+
+	counter := now.Unix() / int64(totpStep/time.Second)
+	for _, c := range []int64{counter - 1, counter, counter + 1} {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, uint64(c))), []byte(code)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// hotp computes the RFC 4226 6-digit code for one counter value.
+func hotp(key []byte, counter uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000)
+}
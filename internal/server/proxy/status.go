@@ -0,0 +1,214 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"go.uber.org/zap"
+)
+
+// incident is one operator-declared service disruption shown on the public
+// status page until it is resolved.
+type incident struct {
+	ID       int64      `json:"id"`
+	Message  string     `json:"message"`
+	Started  time.Time  `json:"started_at"`
+	Resolved *time.Time `json:"resolved_at,omitempty"`
+}
+
+// SetStatusPage serves an unauthenticated aggregate health page on
+// status.<domain>. The subdomain is reserved, so no tunnel can shadow it.
+func (h *Handler) SetStatusPage(enabled bool) {
+	h.statusPage = enabled
+}
+
+// openIncidents returns unresolved incidents, oldest first.
+func (h *Handler) openIncidents() []*incident {
+	h.incidentsMu.Lock()
+	defer h.incidentsMu.Unlock()
+
+	var open []*incident
+	for _, inc := range h.incidents {
+		if inc.Resolved == nil {
+			open = append(open, inc)
+		}
+	}
+	return open
+}
+
+// latencyP95 returns the 95th percentile of per-tunnel control latencies,
+// or zero when no tunnel has a measurement yet.
+func (h *Handler) latencyP95() time.Duration {
+	var latencies []time.Duration
+	for _, conn := range h.manager.List() {
+		if conn == nil {
+			continue
+		}
+		if l := conn.GetLatency(); l > 0 {
+			latencies = append(latencies, l)
+		}
+	}
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	idx := len(latencies) * 95 / 100
+	if idx >= len(latencies) {
+		idx = len(latencies) - 1
+	}
+	return latencies[idx]
+}
+
+// serveStatusPage renders the public status page. It is deliberately
+// unauthenticated and only exposes aggregates, never per-tunnel details.
+func (h *Handler) serveStatusPage(w http.ResponseWriter, r *http.Request) {
+	open := h.openIncidents()
+	p95 := h.latencyP95()
+	count := h.manager.Count()
+
+	if r.URL.Path == "/status.json" {
+		status := "operational"
+		if len(open) > 0 {
+			status = "degraded"
+		}
+		result := map[string]interface{}{
+			"status":         status,
+			"active_tunnels": count,
+			"latency_p95_ms": p95.Milliseconds(),
+			"incidents":      open,
+			"timestamp":      time.Now().Unix(),
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+		w.Write(data)
+		return
+	}
+
+	banner := `<p class="ok">✅ All systems operational</p>`
+	if len(open) > 0 {
+		banner = `<p class="bad">⚠️ Ongoing incidents</p><ul>`
+		for _, inc := range open {
+			banner += fmt.Sprintf("<li>%s <small>(since %s)</small></li>",
+				htmlEscape(inc.Message), inc.Started.UTC().Format(time.RFC3339))
+		}
+		banner += "</ul>"
+	}
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="UTF-8" />
+	<meta http-equiv="refresh" content="30" />
+	<title>Status - Drip</title>
+	<style>
+		body { font-family: Arial, sans-serif; max-width: 600px; margin: 50px auto; padding: 20px; }
+		h1 { color: #333; }
+		.ok { color: #2a7; }
+		.bad { color: #c60; }
+		.stats { background: #f9f9f9; padding: 15px; border-radius: 5px; margin: 20px 0; }
+	</style>
+</head>
+<body>
+	<h1>💧 Drip Status</h1>
+	%s
+	<div class="stats">
+		<p>Active tunnels: %d</p>
+		<p>Control latency (p95): %d ms</p>
+	</div>
+	<p><small>Also available as <a href="/status.json">JSON</a>.</small></p>
+</body>
+</html>`, banner, count, p95.Milliseconds())
+
+	data := []byte(html)
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+// serveIncidentControl manages status page incidents.
+// GET/POST /incident?message=<text> opens one, /incident?resolve=<id>
+// closes one, and a bare GET lists open incidents.
+func (h *Handler) serveIncidentControl(w http.ResponseWriter, r *http.Request) {
+	if !h.isAuthorized(r) {
+		http.Error(w, "Unauthorized: invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	if resolve := r.URL.Query().Get("resolve"); resolve != "" {
+		id, err := strconv.ParseInt(resolve, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid resolve parameter", http.StatusBadRequest)
+			return
+		}
+
+		h.incidentsMu.Lock()
+		var found *incident
+		for _, inc := range h.incidents {
+			if inc.ID == id && inc.Resolved == nil {
+				now := time.Now()
+				inc.Resolved = &now
+				found = inc
+				break
+			}
+		}
+		h.incidentsMu.Unlock()
+
+		if found == nil {
+			http.Error(w, "No open incident with that id", http.StatusNotFound)
+			return
+		}
+
+		h.logger.Info("Incident resolved", zap.Int64("id", id))
+		h.writeIncidentJSON(w, found)
+		return
+	}
+
+	if message := r.URL.Query().Get("message"); message != "" {
+		h.incidentsMu.Lock()
+		h.nextIncidentID++
+		inc := &incident{
+			ID:      h.nextIncidentID,
+			Message: message,
+			Started: time.Now(),
+		}
+		h.incidents = append(h.incidents, inc)
+		h.incidentsMu.Unlock()
+
+		h.logger.Info("Incident opened", zap.Int64("id", inc.ID))
+		h.writeIncidentJSON(w, inc)
+		return
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"incidents": h.openIncidents(),
+	})
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
+
+func (h *Handler) writeIncidentJSON(w http.ResponseWriter, inc *incident) {
+	data, err := json.Marshal(inc)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
+	w.Write(data)
+}
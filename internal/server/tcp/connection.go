@@ -13,12 +13,16 @@ import (
 	"sync"
 	"time"
 
-	json "github.com/goccy/go-json"
 	"github.com/hashicorp/yamux"
 
+	"drip/internal/server/plugin"
 	"drip/internal/server/tunnel"
+	"drip/internal/shared/clock"
 	"drip/internal/shared/constants"
+	"drip/internal/shared/httputil"
+	"drip/internal/shared/netutil"
 	"drip/internal/shared/protocol"
+	"drip/internal/shared/utils"
 
 	"go.uber.org/zap"
 )
@@ -45,15 +49,33 @@ type Connection struct {
 	cancel        context.CancelFunc
 	session       *yamux.Session
 	proxy         *Proxy
+	udpProxy      *UDPProxy
+	socksProxy    *SOCKSProxy
 	tunnelID      string
 	groupManager  *ConnectionGroupManager
-	httpListener  *connQueueListener
-	handedOff     bool
+	extraSubs     []string
+	extraConns    []*tunnel.Connection
+
+	localPort       int      // primary tunnel's client-side local port
+	extraPorts      []int    // public ports allocated for extra TCP tunnels
+	extraLocalPorts []int    // client-side local ports, one per extra TCP tunnel
+	extraProxies    []*Proxy // one per extra TCP tunnel
+	httpListener    *connQueueListener
+	handedOff       bool
+	plugins         *plugin.Client // nil when no plugin is configured
+	powGate         *powGate       // nil unless anonymous registrations require proof-of-work
+	clock           clock.Clock    // heartbeat timing; tests inject a fake
+	token           string
+	startedAt       time.Time
 }
 
-// NewConnection creates a new connection handler
-func NewConnection(conn net.Conn, authToken string, manager *tunnel.Manager, logger *zap.Logger, portAlloc *PortAllocator, domain string, publicPort int, httpHandler http.Handler, groupManager *ConnectionGroupManager, httpListener *connQueueListener) *Connection {
-	ctx, cancel := context.WithCancel(context.Background())
+// NewConnection creates a new connection handler. parent is the listener's
+// root context; cancelling it tears down all connection-scoped work.
+func NewConnection(parent context.Context, conn net.Conn, authToken string, manager *tunnel.Manager, logger *zap.Logger, portAlloc *PortAllocator, domain string, publicPort int, httpHandler http.Handler, groupManager *ConnectionGroupManager, httpListener *connQueueListener) *Connection {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
 	c := &Connection{
 		conn:          conn,
 		authToken:     authToken,
@@ -65,10 +87,12 @@ func NewConnection(conn net.Conn, authToken string, manager *tunnel.Manager, log
 		httpHandler:   httpHandler,
 		stopCh:        make(chan struct{}),
 		lastHeartbeat: time.Now(),
+		startedAt:     time.Now(),
 		ctx:           ctx,
 		cancel:        cancel,
 		groupManager:  groupManager,
 		httpListener:  httpListener,
+		clock:         clock.System,
 	}
 	return c
 }
@@ -111,12 +135,21 @@ func (c *Connection) Handle() error {
 		return c.handleDataConnect(sf.Frame, reader)
 	}
 
+	if sf.Frame.Type == protocol.FrameTypeForward {
+		return c.handleForward(sf.Frame, reader)
+	}
+
 	if sf.Frame.Type != protocol.FrameTypeRegister {
 		return fmt.Errorf("expected register frame, got %s", sf.Frame.Type)
 	}
 
+	if len(sf.Frame.Payload) > protocol.MaxControlPayload {
+		c.sendError("invalid_request", "Registration request too large")
+		return fmt.Errorf("registration payload too large: %d bytes", len(sf.Frame.Payload))
+	}
+
 	var req protocol.RegisterRequest
-	if err := json.Unmarshal(sf.Frame.Payload, &req); err != nil {
+	if err := protocol.UnmarshalJSON(sf.Frame.Payload, &req); err != nil {
 		return fmt.Errorf("failed to parse registration request: %w", err)
 	}
 
@@ -126,14 +159,58 @@ func (c *Connection) Handle() error {
 		c.sendError("authentication_failed", "Invalid authentication token")
 		return fmt.Errorf("authentication failed")
 	}
+	c.token = req.Token
 
-	if req.TunnelType == protocol.TunnelTypeTCP {
+	// Anonymous servers can demand proof-of-work before registering; the
+	// client solves the returned challenge and retries. Token-authenticated
+	// deployments never reach this (powGate is only set without a token).
+	if c.powGate != nil && c.authToken == "" {
+		if !c.powGate.Verify(req.PowChallenge, req.PowNonce) {
+			c.sendPowChallenge()
+			return fmt.Errorf("registration requires proof-of-work")
+		}
+	}
+
+	// An unreachable plugin allows registration (and is logged) rather than
+	// taking every tunnel down with it; an explicit denial is final.
+	if c.plugins != nil {
+		authResp, err := c.plugins.Authorize(c.ctx, &plugin.AuthRequest{
+			Token:      req.Token,
+			Subdomain:  req.CustomSubdomain,
+			TunnelType: string(req.TunnelType),
+			ClientAddr: c.conn.RemoteAddr().String(),
+		})
+		if err != nil {
+			c.logger.Warn("Plugin authorize call failed, allowing registration", zap.Error(err))
+		} else if !authResp.Allow {
+			reason := authResp.Reason
+			if reason == "" {
+				reason = "Registration denied by plugin"
+			}
+			c.sendError("authentication_failed", reason)
+			return fmt.Errorf("plugin denied registration")
+		}
+	}
+
+	if req.TunnelType == protocol.TunnelTypeTCP || req.TunnelType == protocol.TunnelTypeUDP || req.TunnelType == protocol.TunnelTypeSOCKS {
 		if c.portAlloc == nil {
 			return fmt.Errorf("port allocator not configured")
 		}
 
-		if requestedPort, ok := parseTCPSubdomainPort(req.CustomSubdomain); ok {
-			port, err := c.portAlloc.AllocateSpecific(requestedPort)
+		owner := PortOwner(req.Token, req.CustomSubdomain)
+		if req.RemotePort != 0 {
+			port, err := c.portAlloc.AllocateSpecific(req.RemotePort, owner)
+			if err != nil {
+				c.sendError("port_allocation_failed", err.Error())
+				return fmt.Errorf("failed to allocate requested port %d: %w", req.RemotePort, err)
+			}
+			c.port = port
+
+			if req.CustomSubdomain == "" {
+				req.CustomSubdomain = fmt.Sprintf("%s-%d", req.TunnelType, port)
+			}
+		} else if requestedPort, ok := parsePortSubdomain(req.CustomSubdomain); ok {
+			port, err := c.portAlloc.AllocateSpecific(requestedPort, owner)
 			if err != nil {
 				c.sendError("port_allocation_failed", err.Error())
 				return fmt.Errorf("failed to allocate requested port %d: %w", requestedPort, err)
@@ -148,12 +225,27 @@ func (c *Connection) Handle() error {
 			c.port = port
 
 			if req.CustomSubdomain == "" {
-				req.CustomSubdomain = fmt.Sprintf("tcp-%d", port)
+				req.CustomSubdomain = fmt.Sprintf("%s-%d", req.TunnelType, port)
 			}
 		}
 	}
 
-	subdomain, err := c.manager.Register(nil, req.CustomSubdomain)
+	// A requested naming style only applies when the server is choosing the
+	// name anyway.
+	if req.CustomSubdomain == "" && req.NameStyle != "" {
+		req.CustomSubdomain = c.manager.GenerateName(req.NameStyle)
+	}
+
+	if req.CustomSubdomain != "" {
+		if err := c.manager.CheckReservation(req.CustomSubdomain, req.Token); err != nil {
+			c.sendError("registration_failed", err.Error())
+			c.portAlloc.Release(c.port)
+			c.port = 0
+			return fmt.Errorf("tunnel registration failed: %w", err)
+		}
+	}
+
+	subdomain, err := c.manager.Register(nil, req.CustomSubdomain, req.InstanceID)
 	if err != nil {
 		c.sendError("registration_failed", err.Error())
 		c.portAlloc.Release(c.port)
@@ -169,12 +261,116 @@ func (c *Connection) Handle() error {
 	}
 	c.tunnelConn = tunnelConn
 
+	// Assign the tunnel ID up front and bind it (with the subdomain and a
+	// hashed token ID) onto the connection logger, so every subsequent log
+	// line for this tunnel can be grepped by any of the three.
+	c.tunnelID = GenerateTunnelID()
+	c.logger = c.logger.With(
+		zap.String("tunnel_id", c.tunnelID),
+		zap.String("subdomain", subdomain),
+		zap.String("token_id", utils.TokenID(req.Token)),
+	)
+	tunnelConn.SetTunnelID(c.tunnelID)
+
 	c.tunnelConn.Conn = nil
 	c.tunnelConn.SetTunnelType(req.TunnelType)
 	c.tunnelType = req.TunnelType
+	c.localPort = req.LocalPort
+	c.manager.RecordConnect(subdomain, req.Token, c.conn.RemoteAddr().String(), req.TunnelType)
+	if c.port != 0 {
+		c.manager.SetPort(subdomain, c.port)
+	}
+	if len(req.Services) > 0 {
+		c.tunnelConn.SetServices(req.Services)
+	}
+
+	if len(req.Redirects) > 0 || req.ForceTrailingSlash {
+		c.tunnelConn.SetRedirects(req.Redirects, req.ForceTrailingSlash)
+	}
+
+	if req.Alias != "" {
+		if err := c.manager.SetAlias(req.Alias, req.Token, subdomain); err != nil {
+			c.sendError("alias_failed", err.Error())
+			return fmt.Errorf("alias registration failed: %w", err)
+		}
+	}
+
+	if req.CustomDomain != "" {
+		if req.TunnelType != protocol.TunnelTypeHTTP && req.TunnelType != protocol.TunnelTypeHTTPS {
+			c.sendError("domain_failed", "Custom domains are only supported for HTTP tunnels")
+			return fmt.Errorf("custom domain on %s tunnel", req.TunnelType)
+		}
+		if err := c.manager.SetCustomDomain(req.CustomDomain, req.Token, subdomain); err != nil {
+			c.sendError("domain_failed", err.Error())
+			return fmt.Errorf("custom domain registration failed: %w", err)
+		}
+	}
+
+	if len(req.ExtraTunnels) > 0 {
+		switch req.TunnelType {
+		case protocol.TunnelTypeHTTP, protocol.TunnelTypeHTTPS:
+			for _, extra := range req.ExtraTunnels {
+				if extra.Subdomain == "" {
+					c.sendError("registration_failed", "Extra tunnels require an explicit subdomain")
+					return fmt.Errorf("extra tunnel without subdomain")
+				}
+				extraSub, err := c.manager.Register(nil, extra.Subdomain, req.InstanceID)
+				if err != nil {
+					c.sendError("registration_failed", fmt.Sprintf("extra subdomain %s: %v", extra.Subdomain, err))
+					return fmt.Errorf("extra tunnel registration failed: %w", err)
+				}
+				ec, ok := c.manager.Get(extraSub)
+				if !ok {
+					return fmt.Errorf("failed to get registered extra tunnel")
+				}
+				ec.Conn = nil
+				ec.SetTunnelType(req.TunnelType)
+				ec.SetTunnelID(c.tunnelID)
+				c.manager.RecordConnect(extraSub, req.Token, c.conn.RemoteAddr().String(), req.TunnelType)
+				c.extraSubs = append(c.extraSubs, extraSub)
+				c.extraConns = append(c.extraConns, ec)
+			}
+		case protocol.TunnelTypeTCP:
+			// Each extra TCP tunnel gets its own public port; the stream
+			// prefix written by the proxy tells the client which local
+			// port the connection is for.
+			for _, extra := range req.ExtraTunnels {
+				port, err := c.portAlloc.Allocate()
+				if err != nil {
+					c.sendError("port_allocation_failed", err.Error())
+					return fmt.Errorf("failed to allocate extra port: %w", err)
+				}
+				extraName := extra.Subdomain
+				if extraName == "" {
+					extraName = fmt.Sprintf("tcp-%d", port)
+				}
+				extraSub, err := c.manager.Register(nil, extraName, req.InstanceID)
+				if err != nil {
+					c.portAlloc.Release(port)
+					c.sendError("registration_failed", fmt.Sprintf("extra tunnel %s: %v", extraName, err))
+					return fmt.Errorf("extra tunnel registration failed: %w", err)
+				}
+				ec, ok := c.manager.Get(extraSub)
+				if !ok {
+					return fmt.Errorf("failed to get registered extra tunnel")
+				}
+				ec.Conn = nil
+				ec.SetTunnelType(req.TunnelType)
+				ec.SetTunnelID(c.tunnelID)
+				c.manager.RecordConnect(extraSub, req.Token, c.conn.RemoteAddr().String(), req.TunnelType)
+				c.manager.SetPort(extraSub, port)
+				c.extraSubs = append(c.extraSubs, extraSub)
+				c.extraConns = append(c.extraConns, ec)
+				c.extraPorts = append(c.extraPorts, port)
+				c.extraLocalPorts = append(c.extraLocalPorts, extra.LocalPort)
+			}
+		default:
+			c.sendError("registration_failed", "Extra tunnels are only supported for HTTP and TCP tunnels")
+			return fmt.Errorf("extra tunnels requested on %s tunnel", req.TunnelType)
+		}
+	}
 
 	c.logger.Info("Tunnel registered",
-		zap.String("subdomain", subdomain),
 		zap.String("tunnel_type", string(req.TunnelType)),
 		zap.Int("local_port", req.LocalPort),
 		zap.Int("remote_port", c.port),
@@ -187,8 +383,29 @@ func (c *Connection) Handle() error {
 		} else {
 			tunnelURL = fmt.Sprintf("https://%s.%s:%d", subdomain, c.domain, c.publicPort)
 		}
+	} else if req.TunnelType == protocol.TunnelTypeSOCKS {
+		tunnelURL = fmt.Sprintf("socks5://%s:%d", c.domain, c.port)
 	} else {
-		tunnelURL = fmt.Sprintf("tcp://%s:%d", c.domain, c.port)
+		tunnelURL = fmt.Sprintf("%s://%s:%d", req.TunnelType, c.domain, c.port)
+	}
+
+	var extraURLs []string
+	if req.CustomDomain != "" {
+		if c.publicPort == 443 {
+			extraURLs = append(extraURLs, fmt.Sprintf("https://%s", req.CustomDomain))
+		} else {
+			extraURLs = append(extraURLs, fmt.Sprintf("https://%s:%d", req.CustomDomain, c.publicPort))
+		}
+	}
+	for i, extraSub := range c.extraSubs {
+		switch {
+		case req.TunnelType == protocol.TunnelTypeTCP:
+			extraURLs = append(extraURLs, fmt.Sprintf("tcp://%s:%d", c.domain, c.extraPorts[i]))
+		case c.publicPort == 443:
+			extraURLs = append(extraURLs, fmt.Sprintf("https://%s.%s", extraSub, c.domain))
+		default:
+			extraURLs = append(extraURLs, fmt.Sprintf("https://%s.%s:%d", extraSub, c.domain, c.publicPort))
+		}
 	}
 
 	var tunnelID string
@@ -196,14 +413,12 @@ func (c *Connection) Handle() error {
 	recommendedConns := 0
 
 	if req.PoolCapabilities != nil && req.ConnectionType == "primary" && c.groupManager != nil {
-		group := c.groupManager.CreateGroup(subdomain, req.Token, c, req.TunnelType)
+		group := c.groupManager.CreateGroup(c.tunnelID, subdomain, req.Token, c, req.TunnelType)
 		tunnelID = group.TunnelID
-		c.tunnelID = tunnelID
 		supportsDataConn = true
 		recommendedConns = 4
 
 		c.logger.Info("Created connection group for multi-connection support",
-			zap.String("tunnel_id", tunnelID),
 			zap.Int("max_data_conns", req.PoolCapabilities.MaxDataConns),
 		)
 	}
@@ -213,12 +428,13 @@ func (c *Connection) Handle() error {
 		Port:             c.port,
 		URL:              tunnelURL,
 		Message:          "Tunnel registered successfully",
+		ExtraURLs:        extraURLs,
 		TunnelID:         tunnelID,
 		SupportsDataConn: supportsDataConn,
 		RecommendedConns: recommendedConns,
 	}
 
-	respData, _ := json.Marshal(resp)
+	respData, _ := protocol.MarshalJSON(resp)
 	ackFrame := protocol.NewFrame(protocol.FrameTypeRegisterAck, respData)
 
 	err = protocol.WriteFrame(c.conn, ackFrame)
@@ -231,6 +447,12 @@ func (c *Connection) Handle() error {
 	if req.TunnelType == protocol.TunnelTypeTCP {
 		return c.handleTCPTunnel(reader)
 	}
+	if req.TunnelType == protocol.TunnelTypeUDP {
+		return c.handleUDPTunnel(reader)
+	}
+	if req.TunnelType == protocol.TunnelTypeSOCKS {
+		return c.handleSOCKSTunnel(reader, req.SocksUser, req.SocksPass)
+	}
 	if req.TunnelType == protocol.TunnelTypeHTTP || req.TunnelType == protocol.TunnelTypeHTTPS {
 		return c.handleHTTPProxyTunnel(reader)
 	}
@@ -347,7 +569,7 @@ func (c *Connection) handleHTTPRequestLegacy(reader *bufio.Reader) error {
 		respWriter := &httpResponseWriter{
 			conn:   c.conn,
 			writer: bufio.NewWriterSize(c.conn, 4096),
-			header: make(http.Header),
+			header: httputil.GetHeader(),
 		}
 
 		c.httpHandler.ServeHTTP(respWriter, req)
@@ -379,6 +601,8 @@ func (c *Connection) handleHTTPRequestLegacy(reader *bufio.Reader) error {
 			shouldClose = true
 		}
 
+		httputil.PutHeader(respWriter.header)
+
 		if shouldClose {
 			c.logger.Debug("Closing connection as requested by client or server")
 			return nil
@@ -393,12 +617,16 @@ func min(a, b int) int {
 	return b
 }
 
-func parseTCPSubdomainPort(subdomain string) (int, bool) {
-	if !strings.HasPrefix(subdomain, "tcp-") {
+func parsePortSubdomain(subdomain string) (int, bool) {
+	var portStr string
+	switch {
+	case strings.HasPrefix(subdomain, "tcp-"):
+		portStr = strings.TrimPrefix(subdomain, "tcp-")
+	case strings.HasPrefix(subdomain, "udp-"):
+		portStr = strings.TrimPrefix(subdomain, "udp-")
+	default:
 		return 0, false
 	}
-
-	portStr := strings.TrimPrefix(subdomain, "tcp-")
 	if portStr == "" {
 		return 0, false
 	}
@@ -462,7 +690,7 @@ func (c *Connection) handleFrames(reader *bufio.Reader) error {
 
 func (c *Connection) handleHeartbeat() {
 	c.mu.Lock()
-	c.lastHeartbeat = time.Now()
+	c.lastHeartbeat = c.clock.Now()
 	c.mu.Unlock()
 
 	ackFrame := protocol.NewFrame(protocol.FrameTypeHeartbeatAck, nil)
@@ -473,22 +701,22 @@ func (c *Connection) handleHeartbeat() {
 }
 
 func (c *Connection) heartbeatChecker() {
-	ticker := time.NewTicker(constants.HeartbeatInterval)
+	ticker := c.clock.NewTicker(constants.HeartbeatInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-c.stopCh:
 			return
-		case <-ticker.C:
+		case <-ticker.Chan():
 			c.mu.RLock()
 			lastHB := c.lastHeartbeat
 			c.mu.RUnlock()
 
-			if time.Since(lastHB) > constants.HeartbeatTimeout {
+			if c.clock.Since(lastHB) > constants.HeartbeatTimeout {
 				c.logger.Warn("Heartbeat timeout",
 					zap.String("subdomain", c.subdomain),
-					zap.Duration("last_heartbeat", time.Since(lastHB)),
+					zap.Duration("last_heartbeat", c.clock.Since(lastHB)),
 				)
 				c.Close()
 				return
@@ -497,12 +725,27 @@ func (c *Connection) heartbeatChecker() {
 	}
 }
 
+func (c *Connection) sendPowChallenge() {
+	challenge := protocol.PowChallenge{
+		Challenge:  c.powGate.Challenge(),
+		Difficulty: c.powGate.bits,
+	}
+	data, _ := protocol.MarshalJSON(challenge)
+	frame := protocol.NewFrame(protocol.FrameTypePowChallenge, data)
+
+	if c.frameWriter == nil {
+		protocol.WriteFrame(c.conn, frame)
+	} else {
+		c.frameWriter.WriteFrame(frame)
+	}
+}
+
 func (c *Connection) sendError(code, message string) {
 	errMsg := protocol.ErrorMessage{
 		Code:    code,
 		Message: message,
 	}
-	data, _ := json.Marshal(errMsg)
+	data, _ := protocol.MarshalJSON(errMsg)
 	errFrame := protocol.NewFrame(protocol.FrameTypeError, data)
 
 	if c.frameWriter == nil {
@@ -538,6 +781,18 @@ func (c *Connection) Close() {
 			c.proxy.Stop()
 		}
 
+		for _, ep := range c.extraProxies {
+			ep.Stop()
+		}
+
+		if c.udpProxy != nil {
+			c.udpProxy.Stop()
+		}
+
+		if c.socksProxy != nil {
+			c.socksProxy.Stop()
+		}
+
 		if c.session != nil {
 			_ = c.session.Close()
 		}
@@ -549,6 +804,23 @@ func (c *Connection) Close() {
 		if c.port > 0 && c.portAlloc != nil {
 			c.portAlloc.Release(c.port)
 		}
+		if c.portAlloc != nil {
+			for _, port := range c.extraPorts {
+				c.portAlloc.Release(port)
+			}
+		}
+
+		if c.plugins != nil && c.subdomain != "" && c.tunnelConn != nil {
+			record := &plugin.AccountRecord{
+				Subdomain:   c.subdomain,
+				Token:       c.token,
+				TunnelType:  string(c.tunnelType),
+				BytesIn:     c.tunnelConn.GetBytesIn(),
+				BytesOut:    c.tunnelConn.GetBytesOut(),
+				DurationSec: int64(time.Since(c.startedAt).Seconds()),
+			}
+			go c.plugins.Account(record)
+		}
 
 		if c.subdomain != "" {
 			c.manager.Unregister(c.subdomain)
@@ -557,6 +829,10 @@ func (c *Connection) Close() {
 			}
 		}
 
+		for _, extraSub := range c.extraSubs {
+			c.manager.Unregister(extraSub)
+		}
+
 		c.logger.Info("Connection closed",
 			zap.String("subdomain", c.subdomain),
 		)
@@ -613,8 +889,13 @@ func (w *httpResponseWriter) Write(data []byte) (int, error) {
 }
 
 func (c *Connection) handleDataConnect(frame *protocol.Frame, reader *bufio.Reader) error {
+	if len(frame.Payload) > protocol.MaxControlPayload {
+		c.sendDataConnectError("invalid_request", "Data connect request too large")
+		return fmt.Errorf("data connect payload too large: %d bytes", len(frame.Payload))
+	}
+
 	var req protocol.DataConnectRequest
-	if err := json.Unmarshal(frame.Payload, &req); err != nil {
+	if err := protocol.UnmarshalJSON(frame.Payload, &req); err != nil {
 		c.sendError("invalid_request", "Failed to parse data connect request")
 		return fmt.Errorf("failed to parse data connect request: %w", err)
 	}
@@ -653,7 +934,7 @@ func (c *Connection) handleDataConnect(frame *protocol.Frame, reader *bufio.Read
 		Message:      "Data connection accepted",
 	}
 
-	respData, _ := json.Marshal(resp)
+	respData, _ := protocol.MarshalJSON(resp)
 	ackFrame := protocol.NewFrame(protocol.FrameTypeDataConnectAck, respData)
 
 	if err := protocol.WriteFrame(c.conn, ackFrame); err != nil {
@@ -673,12 +954,7 @@ func (c *Connection) handleDataConnect(frame *protocol.Frame, reader *bufio.Read
 		reader: reader,
 	}
 
-	cfg := yamux.DefaultConfig()
-	cfg.EnableKeepAlive = false
-	cfg.LogOutput = io.Discard
-	cfg.AcceptBacklog = constants.YamuxAcceptBacklog
-
-	session, err := yamux.Client(bc, cfg)
+	session, err := yamux.Client(bc, netutil.YamuxConfig())
 	if err != nil {
 		return fmt.Errorf("failed to init yamux session: %w", err)
 	}
@@ -711,7 +987,7 @@ func (c *Connection) sendDataConnectError(code, message string) {
 		Accepted: false,
 		Message:  fmt.Sprintf("%s: %s", code, message),
 	}
-	respData, _ := json.Marshal(resp)
+	respData, _ := protocol.MarshalJSON(resp)
 	frame := protocol.NewFrame(protocol.FrameTypeDataConnectAck, respData)
 	protocol.WriteFrame(c.conn, frame)
 }
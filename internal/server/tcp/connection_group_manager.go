@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"drip/internal/shared/clock"
 	"drip/internal/shared/protocol"
 
 	"go.uber.org/zap"
@@ -21,6 +22,7 @@ type ConnectionGroupManager struct {
 	cleanupInterval time.Duration
 	staleTimeout    time.Duration
 	stopCh          chan struct{}
+	clock           clock.Clock // drives the cleanup ticker; tests inject a fake
 }
 
 // NewConnectionGroupManager creates a new connection group manager
@@ -31,6 +33,7 @@ func NewConnectionGroupManager(logger *zap.Logger) *ConnectionGroupManager {
 		cleanupInterval: 60 * time.Second,
 		staleTimeout:    5 * time.Minute,
 		stopCh:          make(chan struct{}),
+		clock:           clock.System,
 	}
 
 	go m.cleanupLoop()
@@ -45,12 +48,15 @@ func GenerateTunnelID() string {
 	return hex.EncodeToString(b)
 }
 
-// CreateGroup creates a new connection group
-func (m *ConnectionGroupManager) CreateGroup(subdomain, token string, primaryConn *Connection, tunnelType protocol.TunnelType) *ConnectionGroup {
+// CreateGroup creates a new connection group keyed by the tunnel ID the
+// registration already assigned, so the group and its log lines share it.
+func (m *ConnectionGroupManager) CreateGroup(tunnelID, subdomain, token string, primaryConn *Connection, tunnelType protocol.TunnelType) *ConnectionGroup {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	tunnelID := GenerateTunnelID()
+	if tunnelID == "" {
+		tunnelID = GenerateTunnelID()
+	}
 
 	group := NewConnectionGroup(tunnelID, subdomain, token, primaryConn, tunnelType, m.logger)
 
@@ -84,12 +90,12 @@ func (m *ConnectionGroupManager) RemoveGroup(tunnelID string) {
 
 // cleanupLoop periodically cleans up stale groups
 func (m *ConnectionGroupManager) cleanupLoop() {
-	ticker := time.NewTicker(m.cleanupInterval)
+	ticker := m.clock.NewTicker(m.cleanupInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.Chan():
 			m.cleanupStaleGroups()
 		case <-m.stopCh:
 			return
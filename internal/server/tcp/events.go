@@ -0,0 +1,77 @@
+package tcp
+
+import (
+	"time"
+
+	"github.com/hashicorp/yamux"
+	"go.uber.org/zap"
+
+	"drip/internal/shared/protocol"
+)
+
+// eventSubTimeout bounds how long the server waits for the subscription
+// frame on a client-opened stream before dropping it.
+const eventSubTimeout = 10 * time.Second
+
+// acceptEventStreams accepts client-opened streams on the tunnel session.
+// All proxied traffic flows over server-opened streams, so any stream the
+// client opens is an event subscription (FrameTypeEventSub); the server
+// then pushes bus events to it as FrameTypeEvent frames until the session
+// or tunnel closes.
+func (c *Connection) acceptEventStreams(session *yamux.Session) {
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			return
+		}
+		go c.serveEventStream(stream)
+	}
+}
+
+func (c *Connection) serveEventStream(stream *yamux.Stream) {
+	defer stream.Close()
+
+	stream.SetReadDeadline(time.Now().Add(eventSubTimeout))
+	frame, err := protocol.ReadFrame(stream)
+	if err != nil {
+		c.logger.Debug("Failed to read event subscription frame",
+			zap.String("subdomain", c.subdomain),
+			zap.Error(err),
+		)
+		return
+	}
+	frameType := frame.Type
+	frame.Release()
+	stream.SetReadDeadline(time.Time{})
+
+	if frameType != protocol.FrameTypeEventSub {
+		c.logger.Debug("Unexpected frame on client-opened stream",
+			zap.String("subdomain", c.subdomain),
+			zap.String("frame_type", frameType.String()),
+		)
+		return
+	}
+
+	events := c.manager.Events().Subscribe()
+	defer c.manager.Events().Unsubscribe(events)
+
+	c.logger.Debug("Client subscribed to events",
+		zap.String("subdomain", c.subdomain),
+	)
+
+	for {
+		select {
+		case ev := <-events:
+			payload, err := protocol.MarshalJSON(ev)
+			if err != nil {
+				continue
+			}
+			frame := protocol.NewFrame(protocol.FrameTypeEvent, payload)
+			if err := protocol.WriteFrame(stream, frame); err != nil {
+				return
+			}
+		case <-c.stopCh:
+			return
+		}
+	}
+}
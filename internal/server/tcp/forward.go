@@ -0,0 +1,106 @@
+package tcp
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"drip/internal/shared/netutil"
+	"drip/internal/shared/pool"
+	"drip/internal/shared/protocol"
+)
+
+// handleForward pipes an authenticated client connection into an existing
+// tunnel (drip forward): the opposite direction of normal flow, letting
+// teammates reach a tunneled service without its public TCP port.
+func (c *Connection) handleForward(frame *protocol.Frame, reader *bufio.Reader) error {
+	if len(frame.Payload) > protocol.MaxControlPayload {
+		c.sendForwardError("invalid_request", "Forward request too large")
+		return fmt.Errorf("forward payload too large: %d bytes", len(frame.Payload))
+	}
+
+	var req protocol.ForwardRequest
+	if err := protocol.UnmarshalJSON(frame.Payload, &req); err != nil {
+		c.sendForwardError("invalid_request", "Failed to parse forward request")
+		return fmt.Errorf("failed to parse forward request: %w", err)
+	}
+
+	if c.authToken != "" && req.Token != c.authToken {
+		c.sendForwardError("authentication_failed", "Invalid authentication token")
+		return fmt.Errorf("authentication failed for forward connection")
+	}
+
+	subdomain := req.Subdomain
+	tconn, ok := c.manager.Get(subdomain)
+	if !ok {
+		if target, aliased := c.manager.ResolveAlias(subdomain); aliased {
+			subdomain = target
+			tconn, ok = c.manager.Get(subdomain)
+		}
+	}
+	if !ok || tconn == nil || tconn.IsClosed() {
+		c.sendForwardError("not_found", "Tunnel not found")
+		return fmt.Errorf("forward target not found: %s", req.Subdomain)
+	}
+
+	tType := tconn.GetTunnelType()
+	if tType == protocol.TunnelTypeUDP {
+		c.sendForwardError("not_supported", "UDP tunnels cannot be forwarded")
+		return fmt.Errorf("forward to udp tunnel %s", subdomain)
+	}
+
+	stream, err := tconn.OpenStream()
+	if err != nil {
+		c.sendForwardError("forward_failed", "Tunnel has no open control session")
+		return fmt.Errorf("failed to open stream to tunnel %s: %w", subdomain, err)
+	}
+	defer stream.Close()
+
+	resp := protocol.ForwardResponse{
+		Accepted:   true,
+		TunnelType: string(tType),
+		Message:    "Forward established",
+	}
+	respData, _ := protocol.MarshalJSON(resp)
+	if err := protocol.WriteFrame(c.conn, protocol.NewFrame(protocol.FrameTypeForwardAck, respData)); err != nil {
+		return fmt.Errorf("failed to send forward ack: %w", err)
+	}
+
+	_ = c.conn.SetReadDeadline(time.Time{})
+
+	c.logger.Info("Forward connection established",
+		zap.String("subdomain", subdomain),
+		zap.String("remote_addr", c.conn.RemoteAddr().String()),
+	)
+
+	tconn.IncActiveConnections()
+	defer tconn.DecActiveConnections()
+
+	bc := &bufferedConn{
+		Conn:   c.conn,
+		reader: reader,
+	}
+
+	_ = netutil.PipeWithCallbacksAndBufferSize(
+		c.ctx,
+		bc,
+		stream,
+		pool.SizeLarge,
+		func(n int64) { tconn.AddBytesIn(n) },
+		func(n int64) { tconn.AddBytesOut(n) },
+	)
+
+	return nil
+}
+
+func (c *Connection) sendForwardError(code, message string) {
+	resp := protocol.ForwardResponse{
+		Accepted: false,
+		Message:  fmt.Sprintf("%s: %s", code, message),
+	}
+	respData, _ := protocol.MarshalJSON(resp)
+	frame := protocol.NewFrame(protocol.FrameTypeForwardAck, respData)
+	protocol.WriteFrame(c.conn, frame)
+}
@@ -5,16 +5,19 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"drip/internal/shared/constants"
 )
 
 // connQueueListener is a net.Listener backed by a channel of pre-accepted conns.
 // It lets the TCP/TLS multiplexer hand off HTTP connections to a standard http.Server.
 type connQueueListener struct {
-	addr   net.Addr
-	conns  chan net.Conn
-	done   chan struct{}
-	once   sync.Once
-	closed atomic.Bool
+	addr    net.Addr
+	conns   chan net.Conn
+	done    chan struct{}
+	once    sync.Once
+	closed  atomic.Bool
+	limiter *ipConnLimiter
 }
 
 func newConnQueueListener(addr net.Addr, buffer int) *connQueueListener {
@@ -22,9 +25,10 @@ func newConnQueueListener(addr net.Addr, buffer int) *connQueueListener {
 		buffer = 1024
 	}
 	return &connQueueListener{
-		addr:  addr,
-		conns: make(chan net.Conn, buffer),
-		done:  make(chan struct{}),
+		addr:    addr,
+		conns:   make(chan net.Conn, buffer),
+		done:    make(chan struct{}),
+		limiter: newIPConnLimiter(constants.PublicMaxConnsPerIP),
 	}
 }
 
@@ -59,10 +63,23 @@ func (l *connQueueListener) Enqueue(conn net.Conn) bool {
 		return false
 	}
 
+	// Bound concurrent public connections per source IP so a single hostile
+	// visitor can't exhaust the queue or pin server goroutines.
+	if l.limiter != nil {
+		wrapped, ok := l.limiter.Wrap(conn)
+		if !ok {
+			return false
+		}
+		conn = wrapped
+	}
+
 	select {
 	case l.conns <- conn:
 		return true
 	default:
+		if lc, ok := conn.(*limitedConn); ok {
+			lc.releaseSlot()
+		}
 		return false
 	}
 }
@@ -0,0 +1,86 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+)
+
+// ipConnLimiter bounds the number of concurrent connections per source IP.
+// It protects the public edge from a single visitor opening enough slow
+// connections to starve other traffic.
+type ipConnLimiter struct {
+	mu    sync.Mutex
+	perIP map[string]int
+	max   int
+}
+
+func newIPConnLimiter(max int) *ipConnLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &ipConnLimiter{
+		perIP: make(map[string]int),
+		max:   max,
+	}
+}
+
+// Wrap reserves a slot for the connection's source IP and returns a wrapped
+// conn that releases the slot on Close. Returns false when the IP is at its
+// connection limit.
+func (l *ipConnLimiter) Wrap(conn net.Conn) (net.Conn, bool) {
+	ip := remoteIP(conn)
+	if ip == "" {
+		return conn, true
+	}
+
+	l.mu.Lock()
+	if l.perIP[ip] >= l.max {
+		l.mu.Unlock()
+		return nil, false
+	}
+	l.perIP[ip]++
+	l.mu.Unlock()
+
+	return &limitedConn{Conn: conn, limiter: l, ip: ip}, true
+}
+
+func (l *ipConnLimiter) release(ip string) {
+	l.mu.Lock()
+	if n := l.perIP[ip]; n <= 1 {
+		delete(l.perIP, ip)
+	} else {
+		l.perIP[ip] = n - 1
+	}
+	l.mu.Unlock()
+}
+
+// limitedConn releases its IP slot exactly once when closed.
+type limitedConn struct {
+	net.Conn
+	limiter *ipConnLimiter
+	ip      string
+	once    sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	c.releaseSlot()
+	return c.Conn.Close()
+}
+
+func (c *limitedConn) releaseSlot() {
+	c.once.Do(func() {
+		c.limiter.release(c.ip)
+	})
+}
+
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
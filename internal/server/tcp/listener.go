@@ -3,6 +3,7 @@ package tcp
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -10,12 +11,15 @@ import (
 	"sync"
 	"time"
 
+	"drip/internal/server/plugin"
 	"drip/internal/server/tunnel"
+	"drip/internal/shared/constants"
 	"drip/internal/shared/pool"
 	"drip/internal/shared/recovery"
 
 	"go.uber.org/zap"
 	"golang.org/x/net/http2"
+	"golang.org/x/net/quic"
 )
 
 type Listener struct {
@@ -29,7 +33,9 @@ type Listener struct {
 	publicPort   int
 	httpHandler  http.Handler
 	listener     net.Listener
+	quicEndpoint *quic.Endpoint
 	stopCh       chan struct{}
+	stopOnce     sync.Once
 	wg           sync.WaitGroup
 	connections  map[string]*Connection
 	connMu       sync.RWMutex
@@ -39,6 +45,10 @@ type Listener struct {
 	groupManager *ConnectionGroupManager
 	httpServer   *http.Server
 	httpListener *connQueueListener
+	plugins      *plugin.Client
+	powGate      *powGate
+	ctx          context.Context
+	cancel       context.CancelFunc
 }
 
 func NewListener(address string, tlsConfig *tls.Config, authToken string, manager *tunnel.Manager, logger *zap.Logger, portAlloc *PortAllocator, domain string, publicPort int, httpHandler http.Handler) *Listener {
@@ -56,7 +66,11 @@ func NewListener(address string, tlsConfig *tls.Config, authToken string, manage
 	panicMetrics := recovery.NewPanicMetrics(logger, nil)
 	recoverer := recovery.NewRecoverer(logger, panicMetrics)
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Listener{
+		ctx:          ctx,
+		cancel:       cancel,
 		address:      address,
 		tlsConfig:    tlsConfig,
 		authToken:    authToken,
@@ -75,6 +89,20 @@ func NewListener(address string, tlsConfig *tls.Config, authToken string, manage
 	}
 }
 
+// SetRegistrationPoW requires registrations to solve a proof-of-work
+// challenge with the given leading-zero-bit difficulty. Intended for
+// servers running without an auth token; authenticated registrations are
+// never gated. Call before Start.
+func (l *Listener) SetRegistrationPoW(bits int) {
+	l.powGate = newPowGate(bits)
+}
+
+// SetPlugin installs a plugin client consulted during registration and
+// reported to on teardown. Call before Start.
+func (l *Listener) SetPlugin(client *plugin.Client) {
+	l.plugins = client
+}
+
 func (l *Listener) Start() error {
 	var err error
 
@@ -92,15 +120,18 @@ func (l *Listener) Start() error {
 
 	l.httpServer = &http.Server{
 		Handler:           l.httpHandler,
-		ReadHeaderTimeout: 30 * time.Second,
+		ReadHeaderTimeout: constants.PublicReadHeaderTimeout,
 		ReadTimeout:       0,
 		WriteTimeout:      0,
 		IdleTimeout:       120 * time.Second,
-		MaxHeaderBytes:    1 << 20,
+		MaxHeaderBytes:    constants.PublicMaxHeaderBytes,
+		// Derive request contexts from the listener context so shutdown
+		// deadlines propagate into in-flight request handling.
+		BaseContext: func(net.Listener) context.Context { return l.ctx },
 	}
 
 	if err := http2.ConfigureServer(l.httpServer, &http2.Server{
-		MaxConcurrentStreams: 1000,
+		MaxConcurrentStreams: constants.PublicMaxConcurrentStreams,
 		IdleTimeout:          120 * time.Second,
 	}); err != nil {
 		l.logger.Warn("Failed to configure HTTP/2", zap.Error(err))
@@ -230,7 +261,17 @@ func (l *Listener) handleConnection(netConn net.Conn) {
 		return
 	}
 
-	conn := NewConnection(netConn, l.authToken, l.manager, l.logger, l.portAlloc, l.domain, l.publicPort, l.httpHandler, l.groupManager, l.httpListener)
+	l.handleControlConn(netConn)
+}
+
+// handleControlConn runs the control protocol on an established transport
+// connection. The TCP path calls it after the TLS handshake; the QUIC
+// path after accepting the control stream, whose handshake QUIC already
+// completed.
+func (l *Listener) handleControlConn(netConn net.Conn) {
+	conn := NewConnection(l.ctx, netConn, l.authToken, l.manager, l.logger, l.portAlloc, l.domain, l.publicPort, l.httpHandler, l.groupManager, l.httpListener)
+	conn.plugins = l.plugins
+	conn.powGate = l.powGate
 
 	connID := netConn.RemoteAddr().String()
 	l.connMu.Lock()
@@ -275,10 +316,25 @@ func (l *Listener) handleConnection(netConn net.Conn) {
 	}
 }
 
+// Drain stops accepting new connections while leaving established tunnels
+// and their in-flight requests untouched. Call Stop afterwards to finish
+// the shutdown.
+func (l *Listener) Drain() {
+	l.logger.Info("Draining TCP listener: no longer accepting connections")
+
+	l.stopOnce.Do(func() { close(l.stopCh) })
+	if l.listener != nil {
+		if err := l.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			l.logger.Error("Failed to close listener", zap.Error(err))
+		}
+	}
+}
+
 func (l *Listener) Stop() error {
 	l.logger.Info("Stopping TCP listener")
 
-	close(l.stopCh)
+	l.stopOnce.Do(func() { close(l.stopCh) })
+	l.cancel()
 
 	if l.httpServer != nil {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -294,11 +350,13 @@ func (l *Listener) Stop() error {
 	}
 
 	if l.listener != nil {
-		if err := l.listener.Close(); err != nil {
+		if err := l.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 			l.logger.Error("Failed to close listener", zap.Error(err))
 		}
 	}
 
+	l.stopQUIC()
+
 	l.connMu.Lock()
 	for _, conn := range l.connections {
 		conn.Close()
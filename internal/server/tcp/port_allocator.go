@@ -5,16 +5,25 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
+
+	json "github.com/goccy/go-json"
 )
 
 // PortAllocator manages dynamic TCP port allocation within a configured range.
 // It keeps an in-memory reservation map; ports are held until Release is called.
+// Requested fixed ports are additionally pinned to their owner (token plus
+// requested subdomain) and persisted, so the same client gets the same public
+// port across restarts and nobody else can take it.
 type PortAllocator struct {
-	min  int
-	max  int
-	used map[int]bool
-	mu   sync.Mutex
+	min      int
+	max      int
+	used     map[int]bool
+	reserved map[int]string // port -> owner key; persisted
+	path     string
+	mu       sync.Mutex
 }
 
 // NewPortAllocator creates a new allocator with the given inclusive range.
@@ -23,13 +32,61 @@ func NewPortAllocator(min, max int) (*PortAllocator, error) {
 		return nil, fmt.Errorf("invalid port range %d-%d", min, max)
 	}
 
+	path := portReservationsPath()
 	return &PortAllocator{
-		min:  min,
-		max:  max,
-		used: make(map[int]bool),
+		min:      min,
+		max:      max,
+		used:     make(map[int]bool),
+		reserved: loadPortReservations(path),
+		path:     path,
 	}, nil
 }
 
+// PortOwner builds the reservation owner key for a registration. The
+// requested subdomain (possibly empty) is part of the key so one token can
+// hold several fixed ports.
+func PortOwner(token, subdomain string) string {
+	return token + "|" + subdomain
+}
+
+// portReservationsPath returns the file where fixed-port reservations are
+// persisted.
+func portReservationsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "server_ports.json")
+	}
+	return filepath.Join(home, ".drip", "server_ports.json")
+}
+
+// loadPortReservations reads persisted reservations; a missing or
+// unreadable file simply starts them fresh.
+func loadPortReservations(path string) map[int]string {
+	reserved := make(map[int]string)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return reserved
+	}
+	if err := json.Unmarshal(data, &reserved); err != nil {
+		return make(map[int]string)
+	}
+	return reserved
+}
+
+// savePortReservations persists the reservations; failures are non-fatal.
+// Callers must hold p.mu.
+func (p *PortAllocator) savePortReservations() {
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(p.reserved, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(p.path, data, 0600)
+}
+
 // Allocate finds a free port, marks it as used, and ensures it's currently available.
 func (p *PortAllocator) Allocate() (int, error) {
 	p.mu.Lock()
@@ -41,6 +98,10 @@ func (p *PortAllocator) Allocate() (int, error) {
 		if p.used[port] {
 			continue
 		}
+		// Never hand a pinned port to a random allocation.
+		if _, pinned := p.reserved[port]; pinned {
+			continue
+		}
 
 		// Probe the port to ensure it's not taken by the OS/other process.
 		ln, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
@@ -56,14 +117,19 @@ func (p *PortAllocator) Allocate() (int, error) {
 	return 0, fmt.Errorf("no available port in range %d-%d", p.min, p.max)
 }
 
-// AllocateSpecific reserves a specific port if it is within range and available.
-func (p *PortAllocator) AllocateSpecific(port int) (int, error) {
+// AllocateSpecific reserves a specific port if it is within range, available,
+// and not pinned to a different owner. On success the port is pinned to
+// owner and the pin persisted, so the same client always gets it back.
+func (p *PortAllocator) AllocateSpecific(port int, owner string) (int, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	if port < p.min || port > p.max {
 		return 0, fmt.Errorf("requested port %d outside range %d-%d", port, p.min, p.max)
 	}
+	if holder, pinned := p.reserved[port]; pinned && holder != owner {
+		return 0, fmt.Errorf("requested port %d is reserved by another client", port)
+	}
 	if p.used[port] {
 		return 0, fmt.Errorf("requested port %d already in use", port)
 	}
@@ -75,6 +141,10 @@ func (p *PortAllocator) AllocateSpecific(port int) (int, error) {
 	_ = ln.Close()
 
 	p.used[port] = true
+	if p.reserved[port] != owner {
+		p.reserved[port] = owner
+		p.savePortReservations()
+	}
 	return port, nil
 }
 
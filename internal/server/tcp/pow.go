@@ -0,0 +1,70 @@
+package tcp
+
+import (
+	"sync"
+	"time"
+
+	"drip/internal/shared/protocol"
+	"drip/internal/shared/utils"
+)
+
+// powTTL is how long an issued challenge stays solvable. Generous enough
+// for slow hardware at reasonable difficulties, short enough that hoarding
+// challenges is pointless.
+const powTTL = 2 * time.Minute
+
+// powGate issues and verifies single-use proof-of-work challenges for
+// registrations on servers running without token auth. Challenges are
+// bound to this server instance and consumed on first verification, so
+// solutions cannot be precomputed or replayed.
+type powGate struct {
+	bits int
+
+	mu     sync.Mutex
+	issued map[string]time.Time
+}
+
+func newPowGate(bits int) *powGate {
+	return &powGate{
+		bits:   bits,
+		issued: make(map[string]time.Time),
+	}
+}
+
+// Challenge mints a fresh challenge and remembers it for powTTL.
+func (g *powGate) Challenge() string {
+	challenge := utils.GenerateID()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for c, expires := range g.issued {
+		if now.After(expires) {
+			delete(g.issued, c)
+		}
+	}
+	g.issued[challenge] = now.Add(powTTL)
+	return challenge
+}
+
+// Verify consumes the challenge and checks the nonce against the
+// configured difficulty. A challenge this gate never issued, or one past
+// its TTL, fails regardless of the nonce.
+func (g *powGate) Verify(challenge, nonce string) bool {
+	if challenge == "" || nonce == "" {
+		return false
+	}
+
+	g.mu.Lock()
+	expires, ok := g.issued[challenge]
+	if ok {
+		delete(g.issued, challenge)
+	}
+	g.mu.Unlock()
+
+	if !ok || time.Now().After(expires) {
+		return false
+	}
+	return protocol.CheckPow(challenge, nonce, g.bits)
+}
@@ -216,7 +216,7 @@ func (p *Proxy) handleConn(conn net.Conn) {
 
 	_ = netutil.PipeWithCallbacksAndBufferSize(
 		p.ctx,
-		conn,
+		netutil.FairConn(p.subdomain, conn),
 		stream,
 		pool.SizeLarge,
 		func(n int64) {
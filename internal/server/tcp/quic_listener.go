@@ -0,0 +1,118 @@
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"drip/internal/shared/netutil"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/quic"
+)
+
+// StartQUIC additionally accepts control channels over QUIC on the given
+// UDP address. The endpoint reuses the listener's TLS config — including
+// any GetCertificate reload hook — with the control ALPN added, since
+// QUIC requires ALPN. Call after Start; Stop shuts the endpoint down with
+// the rest of the listener.
+func (l *Listener) StartQUIC(address string) error {
+	tlsConfig := l.tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{netutil.QUICControlALPN}
+
+	endpoint, err := quic.Listen("udp", address, &quic.Config{
+		TLSConfig:       tlsConfig,
+		KeepAlivePeriod: 30 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start QUIC listener: %w", err)
+	}
+	l.quicEndpoint = endpoint
+
+	l.logger.Info("QUIC listener started",
+		zap.String("address", address),
+	)
+
+	l.wg.Add(1)
+	go l.acceptQUICLoop()
+
+	return nil
+}
+
+func (l *Listener) acceptQUICLoop() {
+	defer l.wg.Done()
+	defer l.recoverer.Recover("acceptQUICLoop")
+
+	// Stop accepting on Drain (stopCh) without closing the endpoint, which
+	// would tear down the established QUIC control channels it owns.
+	acceptCtx, cancel := context.WithCancel(l.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-l.stopCh:
+			cancel()
+		case <-acceptCtx.Done():
+		}
+	}()
+
+	for {
+		conn, err := l.quicEndpoint.Accept(acceptCtx)
+		if err != nil {
+			select {
+			case <-acceptCtx.Done():
+				return
+			default:
+				l.logger.Error("Failed to accept QUIC connection", zap.Error(err))
+				continue
+			}
+		}
+
+		l.wg.Add(1)
+		l.recoverer.SafeGo(
+			fmt.Sprintf("handleQUICConn-%s", conn.RemoteAddr().String()),
+			func() {
+				l.handleQUICConn(conn)
+			},
+		)
+	}
+}
+
+// handleQUICConn waits for the client's single control stream and runs the
+// control protocol over it. TLS 1.3 is guaranteed by QUIC itself, so the
+// version check from the TCP path does not apply.
+func (l *Listener) handleQUICConn(conn *quic.Conn) {
+	defer l.wg.Done()
+	defer l.recoverer.Recover("handleQUICConn")
+
+	ctx, cancel := context.WithTimeout(l.ctx, 10*time.Second)
+	stream, err := conn.AcceptStream(ctx)
+	cancel()
+	if err != nil {
+		l.logger.Warn("No control stream from QUIC peer",
+			zap.String("remote_addr", conn.RemoteAddr().String()),
+			zap.Error(err),
+		)
+		conn.Abort(nil)
+		return
+	}
+
+	netConn := netutil.NewQUICStreamConn(nil, conn, stream)
+
+	l.logger.Info("New connection",
+		zap.String("remote_addr", netConn.RemoteAddr().String()),
+		zap.String("transport", "quic"),
+	)
+
+	l.handleControlConn(netConn)
+}
+
+func (l *Listener) stopQUIC() {
+	if l.quicEndpoint == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.quicEndpoint.Close(ctx); err != nil {
+		l.logger.Warn("QUIC endpoint shutdown error", zap.Error(err))
+	}
+}
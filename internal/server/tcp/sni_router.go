@@ -0,0 +1,296 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"drip/internal/server/tunnel"
+	"drip/internal/shared/netutil"
+	"drip/internal/shared/pool"
+	"drip/internal/shared/protocol"
+
+	"go.uber.org/zap"
+)
+
+// maxClientHelloSize bounds the first TLS record read for SNI sniffing.
+// A TLS record payload is at most 16KB.
+const maxClientHelloSize = 16 * 1024
+
+// SNIRouter accepts raw TLS connections on a shared public port, reads the
+// ClientHello without terminating TLS, and forwards the connection to the
+// HTTPS or TCP tunnel whose subdomain matches the SNI hostname. TLS stays
+// end-to-end: the client connector pipes the bytes straight to the local
+// server, so many tunnels can share one public port while terminating TLS
+// with their own certificates.
+type SNIRouter struct {
+	addr    string
+	domain  string
+	manager *tunnel.Manager
+	router  *tunnel.Router
+	logger  *zap.Logger
+
+	listener net.Listener
+	stopCh   chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewSNIRouter(ctx context.Context, addr, domain string, manager *tunnel.Manager, logger *zap.Logger) *SNIRouter {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cctx, cancel := context.WithCancel(ctx)
+
+	return &SNIRouter{
+		addr:    addr,
+		domain:  domain,
+		manager: manager,
+		router:  tunnel.NewRouter(manager, domain),
+		logger:  logger,
+		stopCh:  make(chan struct{}),
+		ctx:     cctx,
+		cancel:  cancel,
+	}
+}
+
+func (r *SNIRouter) Start() error {
+	ln, err := net.Listen("tcp", r.addr)
+	if err != nil {
+		return fmt.Errorf("failed to start SNI router on %s: %w", r.addr, err)
+	}
+	r.listener = ln
+
+	r.logger.Info("SNI router started",
+		zap.String("address", r.addr),
+		zap.String("domain", r.domain),
+	)
+
+	r.wg.Add(1)
+	go r.acceptLoop()
+	return nil
+}
+
+func (r *SNIRouter) Stop() {
+	r.once.Do(func() {
+		close(r.stopCh)
+		r.cancel()
+
+		if r.listener != nil {
+			_ = r.listener.Close()
+		}
+		r.wg.Wait()
+
+		r.logger.Info("SNI router stopped")
+	})
+}
+
+func (r *SNIRouter) acceptLoop() {
+	defer r.wg.Done()
+
+	tcpLn, _ := r.listener.(*net.TCPListener)
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		default:
+		}
+
+		if tcpLn != nil {
+			_ = tcpLn.SetDeadline(time.Now().Add(1 * time.Second))
+		}
+
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			select {
+			case <-r.stopCh:
+				return
+			default:
+				continue
+			}
+		}
+
+		r.wg.Add(1)
+		go r.handleConn(conn)
+	}
+}
+
+func (r *SNIRouter) handleConn(conn net.Conn) {
+	defer r.wg.Done()
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	hello, serverName, err := readClientHello(conn)
+	if err != nil {
+		r.logger.Debug("Failed to read ClientHello", zap.Error(err))
+		return
+	}
+	if serverName == "" {
+		r.logger.Debug("ClientHello without SNI, dropping connection")
+		return
+	}
+
+	// The shared router resolves subdomains, aliases, and CNAMEd custom
+	// domains alike, so SNI passthrough routes the same hosts the HTTP
+	// handler does.
+	subdomain, tconn, ok := r.router.RouteHost(serverName)
+	if !ok || tconn == nil || tconn.IsClosed() {
+		r.logger.Debug("SNI hostname has no live tunnel",
+			zap.String("server_name", serverName),
+		)
+		return
+	}
+	// TCP tunnels pipe raw bytes on the client side, so TLS passthrough
+	// works for them too: a TCP tunnel serving its own certificate can
+	// share the public TLS port by SNI instead of needing a dedicated one.
+	tType := tconn.GetTunnelType()
+	if tType != protocol.TunnelTypeHTTPS && tType != protocol.TunnelTypeTCP {
+		r.logger.Debug("SNI matched a tunnel without a raw-stream path",
+			zap.String("subdomain", subdomain),
+			zap.String("tunnel_type", string(tType)),
+		)
+		return
+	}
+
+	stream, err := tconn.OpenStream()
+	if err != nil {
+		r.logger.Debug("Open stream failed", zap.Error(err))
+		return
+	}
+	defer stream.Close()
+
+	if _, err := stream.Write(hello); err != nil {
+		return
+	}
+
+	_ = conn.SetReadDeadline(time.Time{})
+	tconn.AddBytesIn(int64(len(hello)))
+	tconn.IncActiveConnections()
+	defer tconn.DecActiveConnections()
+
+	_ = netutil.PipeWithCallbacksAndBufferSize(
+		r.ctx,
+		netutil.FairConn(subdomain, conn),
+		stream,
+		pool.SizeLarge,
+		func(n int64) { tconn.AddBytesIn(n) },
+		func(n int64) { tconn.AddBytesOut(n) },
+	)
+}
+
+// readClientHello reads the first TLS record from conn and extracts the SNI
+// hostname without consuming more than the record. The raw bytes read are
+// returned so they can be replayed to the tunnel.
+func readClientHello(conn net.Conn) (raw []byte, serverName string, err error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, "", err
+	}
+
+	// TLS record: type (22 = handshake), version, length.
+	if header[0] != 0x16 {
+		return nil, "", fmt.Errorf("not a TLS handshake record (type %d)", header[0])
+	}
+	recLen := int(binary.BigEndian.Uint16(header[3:5]))
+	if recLen == 0 || recLen > maxClientHelloSize {
+		return nil, "", fmt.Errorf("invalid TLS record length %d", recLen)
+	}
+
+	body := make([]byte, recLen)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, "", err
+	}
+
+	serverName = parseClientHelloSNI(body)
+	return append(header, body...), serverName, nil
+}
+
+// parseClientHelloSNI walks a ClientHello handshake message and returns the
+// server_name extension's hostname, or "" when absent or malformed.
+func parseClientHelloSNI(b []byte) string {
+	// Handshake header: type (1 = ClientHello) + 3-byte length.
+	if len(b) < 4 || b[0] != 0x01 {
+		return ""
+	}
+	b = b[4:]
+
+	// Client version (2) + random (32).
+	if len(b) < 34 {
+		return ""
+	}
+	b = b[34:]
+
+	// Session ID.
+	if len(b) < 1 || len(b) < 1+int(b[0]) {
+		return ""
+	}
+	b = b[1+int(b[0]):]
+
+	// Cipher suites.
+	if len(b) < 2 {
+		return ""
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return ""
+	}
+	b = b[2+n:]
+
+	// Compression methods.
+	if len(b) < 1 || len(b) < 1+int(b[0]) {
+		return ""
+	}
+	b = b[1+int(b[0]):]
+
+	// Extensions.
+	if len(b) < 2 {
+		return ""
+	}
+	extLen := int(binary.BigEndian.Uint16(b))
+	b = b[2:]
+	if len(b) < extLen {
+		return ""
+	}
+	b = b[:extLen]
+
+	for len(b) >= 4 {
+		extType := binary.BigEndian.Uint16(b)
+		size := int(binary.BigEndian.Uint16(b[2:]))
+		b = b[4:]
+		if len(b) < size {
+			return ""
+		}
+
+		if extType != 0 { // server_name
+			b = b[size:]
+			continue
+		}
+
+		ext := b[:size]
+		if len(ext) < 5 {
+			return ""
+		}
+		// server_name_list length (2) + name_type (1, 0 = host_name).
+		if ext[2] != 0 {
+			return ""
+		}
+		nameLen := int(binary.BigEndian.Uint16(ext[3:5]))
+		if len(ext) < 5+nameLen {
+			return ""
+		}
+		return string(ext[5 : 5+nameLen])
+	}
+
+	return ""
+}
@@ -0,0 +1,375 @@
+package tcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"drip/internal/shared/netutil"
+	"drip/internal/shared/pool"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// socksHandshakeTimeout bounds the whole SOCKS5 negotiation so a silent
+	// visitor cannot hold a connection open.
+	socksHandshakeTimeout = 10 * time.Second
+
+	// socksDialTimeout is how long we wait for the client to report that it
+	// reached (or failed to reach) the requested target.
+	socksDialTimeout = 15 * time.Second
+)
+
+// SOCKS5 reply codes (RFC 1928).
+const (
+	socksReplySuccess      = 0x00
+	socksReplyRefused      = 0x05
+	socksReplyCmdUnsupport = 0x07
+	socksReplyAddrUnsupprt = 0x08
+)
+
+// SOCKSProxy exposes a public port speaking SOCKS5. After the handshake,
+// each CONNECT is relayed over a mux stream to the tunnel client, which
+// dials the requested target inside its own network. Only CONNECT is
+// supported; credentials are enforced here so unauthenticated visitors
+// never reach the client's network.
+type SOCKSProxy struct {
+	port      int
+	subdomain string
+	user      string
+	pass      string
+	logger    *zap.Logger
+
+	listener net.Listener
+	stopCh   chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+
+	openStream func() (net.Conn, error)
+	stats      trafficStats
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewSOCKSProxy(ctx context.Context, port int, subdomain, user, pass string, openStream func() (net.Conn, error), stats trafficStats, logger *zap.Logger) *SOCKSProxy {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cctx, cancel := context.WithCancel(ctx)
+
+	return &SOCKSProxy{
+		port:       port,
+		subdomain:  subdomain,
+		user:       user,
+		pass:       pass,
+		logger:     logger,
+		stopCh:     make(chan struct{}),
+		openStream: openStream,
+		stats:      stats,
+		ctx:        cctx,
+		cancel:     cancel,
+	}
+}
+
+func (p *SOCKSProxy) Start() error {
+	addr := fmt.Sprintf("0.0.0.0:%d", p.port)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d: %w", p.port, err)
+	}
+	p.listener = ln
+
+	p.logger.Info("SOCKS proxy started",
+		zap.Int("port", p.port),
+		zap.String("subdomain", p.subdomain),
+	)
+
+	p.wg.Add(1)
+	go p.acceptLoop()
+	return nil
+}
+
+func (p *SOCKSProxy) Stop() {
+	p.once.Do(func() {
+		close(p.stopCh)
+		p.cancel()
+
+		if p.listener != nil {
+			_ = p.listener.Close()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			p.wg.Wait()
+			close(done)
+		}()
+
+		const stopTimeout = 30 * time.Second
+
+		select {
+		case <-done:
+			p.logger.Info("SOCKS proxy stopped",
+				zap.Int("port", p.port),
+				zap.String("subdomain", p.subdomain),
+			)
+		case <-time.After(stopTimeout):
+			p.logger.Warn("SOCKS proxy stop timed out",
+				zap.Int("port", p.port),
+				zap.String("subdomain", p.subdomain),
+				zap.Duration("timeout", stopTimeout),
+			)
+		}
+	})
+}
+
+func (p *SOCKSProxy) acceptLoop() {
+	defer p.wg.Done()
+
+	tcpLn, _ := p.listener.(*net.TCPListener)
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		default:
+		}
+
+		if tcpLn != nil {
+			_ = tcpLn.SetDeadline(time.Now().Add(1 * time.Second))
+		}
+
+		conn, err := p.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			select {
+			case <-p.stopCh:
+				return
+			default:
+				continue
+			}
+		}
+
+		p.wg.Add(1)
+		go p.handleConn(conn)
+	}
+}
+
+func (p *SOCKSProxy) handleConn(conn net.Conn) {
+	defer p.wg.Done()
+	defer conn.Close()
+
+	if p.stats != nil {
+		p.stats.IncActiveConnections()
+		defer p.stats.DecActiveConnections()
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(socksHandshakeTimeout))
+
+	target, err := p.handshake(conn)
+	if err != nil {
+		p.logger.Debug("SOCKS handshake failed", zap.Error(err))
+		return
+	}
+
+	if p.openStream == nil {
+		_ = socksReply(conn, socksReplyRefused)
+		return
+	}
+
+	stream, err := p.openStream()
+	if err != nil {
+		if !errors.Is(err, net.ErrClosed) {
+			p.logger.Debug("Open stream failed", zap.Error(err))
+		}
+		_ = socksReply(conn, socksReplyRefused)
+		return
+	}
+	defer stream.Close()
+
+	// The stream starts with the requested target as a length-prefixed
+	// host:port; the client answers with one status byte once its dial
+	// settles.
+	if len(target) > 255 {
+		_ = socksReply(conn, socksReplyAddrUnsupprt)
+		return
+	}
+	header := make([]byte, 0, len(target)+1)
+	header = append(header, byte(len(target)))
+	header = append(header, target...)
+	if _, err := stream.Write(header); err != nil {
+		_ = socksReply(conn, socksReplyRefused)
+		return
+	}
+
+	var status [1]byte
+	_ = stream.SetReadDeadline(time.Now().Add(socksDialTimeout))
+	if _, err := io.ReadFull(stream, status[:]); err != nil || status[0] != 0 {
+		_ = socksReply(conn, socksReplyRefused)
+		return
+	}
+	_ = stream.SetReadDeadline(time.Time{})
+
+	if err := socksReply(conn, socksReplySuccess); err != nil {
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	_ = netutil.PipeWithCallbacksAndBufferSize(
+		p.ctx,
+		netutil.FairConn(p.subdomain, conn),
+		stream,
+		pool.SizeLarge,
+		func(n int64) {
+			if p.stats != nil {
+				p.stats.AddBytesIn(n)
+			}
+		},
+		func(n int64) {
+			if p.stats != nil {
+				p.stats.AddBytesOut(n)
+			}
+		},
+	)
+}
+
+// handshake negotiates SOCKS5 method selection, authentication, and the
+// CONNECT request, returning the requested target as host:port.
+func (p *SOCKSProxy) handshake(conn net.Conn) (string, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(conn, head[:]); err != nil {
+		return "", err
+	}
+	if head[0] != 0x05 {
+		return "", fmt.Errorf("unsupported SOCKS version %d", head[0])
+	}
+
+	methods := make([]byte, int(head[1]))
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+
+	wantMethod := byte(0x00) // no auth
+	if p.user != "" || p.pass != "" {
+		wantMethod = 0x02 // username/password (RFC 1929)
+	}
+	offered := false
+	for _, m := range methods {
+		if m == wantMethod {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		_, _ = conn.Write([]byte{0x05, 0xFF})
+		return "", fmt.Errorf("no acceptable auth method offered")
+	}
+	if _, err := conn.Write([]byte{0x05, wantMethod}); err != nil {
+		return "", err
+	}
+
+	if wantMethod == 0x02 {
+		if err := p.verifyCredentials(conn); err != nil {
+			return "", err
+		}
+	}
+
+	var req [4]byte
+	if _, err := io.ReadFull(conn, req[:]); err != nil {
+		return "", err
+	}
+	if req[1] != 0x01 { // CONNECT
+		_ = socksReply(conn, socksReplyCmdUnsupport)
+		return "", fmt.Errorf("unsupported SOCKS command %d", req[1])
+	}
+
+	var host string
+	switch req[3] {
+	case 0x01: // IPv4
+		var addr [4]byte
+		if _, err := io.ReadFull(conn, addr[:]); err != nil {
+			return "", err
+		}
+		host = net.IP(addr[:]).String()
+	case 0x03: // domain name
+		var n [1]byte
+		if _, err := io.ReadFull(conn, n[:]); err != nil {
+			return "", err
+		}
+		name := make([]byte, int(n[0]))
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	case 0x04: // IPv6
+		var addr [16]byte
+		if _, err := io.ReadFull(conn, addr[:]); err != nil {
+			return "", err
+		}
+		host = net.IP(addr[:]).String()
+	default:
+		_ = socksReply(conn, socksReplyAddrUnsupprt)
+		return "", fmt.Errorf("unsupported SOCKS address type %d", req[3])
+	}
+
+	var portBytes [2]byte
+	if _, err := io.ReadFull(conn, portBytes[:]); err != nil {
+		return "", err
+	}
+	port := binary.BigEndian.Uint16(portBytes[:])
+
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
+
+// verifyCredentials runs the RFC 1929 username/password sub-negotiation.
+func (p *SOCKSProxy) verifyCredentials(conn net.Conn) error {
+	var head [2]byte
+	if _, err := io.ReadFull(conn, head[:]); err != nil {
+		return err
+	}
+	if head[0] != 0x01 {
+		return fmt.Errorf("unsupported auth sub-negotiation version %d", head[0])
+	}
+
+	user := make([]byte, int(head[1]))
+	if _, err := io.ReadFull(conn, user); err != nil {
+		return err
+	}
+
+	var plen [1]byte
+	if _, err := io.ReadFull(conn, plen[:]); err != nil {
+		return err
+	}
+	pass := make([]byte, int(plen[0]))
+	if _, err := io.ReadFull(conn, pass); err != nil {
+		return err
+	}
+
+	userOK := subtle.ConstantTimeCompare(user, []byte(p.user)) == 1
+	passOK := subtle.ConstantTimeCompare(pass, []byte(p.pass)) == 1
+	if !userOK || !passOK {
+		_, _ = conn.Write([]byte{0x01, 0x01})
+		return fmt.Errorf("bad SOCKS credentials")
+	}
+
+	_, err := conn.Write([]byte{0x01, 0x00})
+	return err
+}
+
+// socksReply sends a SOCKS5 reply with a zeroed bind address; clients only
+// act on the reply code for CONNECT.
+func socksReply(conn net.Conn, code byte) error {
+	_, err := conn.Write([]byte{0x05, code, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+	return err
+}
@@ -2,13 +2,15 @@ package tcp
 
 import (
 	"bufio"
+	"encoding/binary"
 	"fmt"
-	"io"
 	"net"
+	"time"
 
 	"github.com/hashicorp/yamux"
 
 	"drip/internal/shared/constants"
+	"drip/internal/shared/netutil"
 )
 
 type bufferedConn struct {
@@ -20,6 +22,26 @@ func (c *bufferedConn) Read(p []byte) (int, error) {
 	return c.reader.Read(p)
 }
 
+// prefixedOpenStream wraps a stream opener so every new stream starts with
+// the client-side local port as a 2-byte big-endian header. TCP has no
+// in-band routing, so this is how multi-port tunnels sharing one control
+// connection tell the client which local service to dial.
+func prefixedOpenStream(open func() (net.Conn, error), localPort int) func() (net.Conn, error) {
+	var header [2]byte
+	binary.BigEndian.PutUint16(header[:], uint16(localPort))
+	return func() (net.Conn, error) {
+		stream, err := open()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := stream.Write(header[:]); err != nil {
+			stream.Close()
+			return nil, fmt.Errorf("failed to write port header: %w", err)
+		}
+		return stream, nil
+	}
+}
+
 func (c *Connection) handleTCPTunnel(reader *bufio.Reader) error {
 	// Public server acts as yamux Client, client connector acts as yamux Server.
 	bc := &bufferedConn{
@@ -27,12 +49,7 @@ func (c *Connection) handleTCPTunnel(reader *bufio.Reader) error {
 		reader: reader,
 	}
 
-	cfg := yamux.DefaultConfig()
-	cfg.EnableKeepAlive = false
-	cfg.LogOutput = io.Discard
-	cfg.AcceptBacklog = constants.YamuxAcceptBacklog
-
-	session, err := yamux.Client(bc, cfg)
+	session, err := yamux.Client(bc, netutil.YamuxConfig())
 	if err != nil {
 		return fmt.Errorf("failed to init yamux session: %w", err)
 	}
@@ -46,11 +63,99 @@ func (c *Connection) handleTCPTunnel(reader *bufio.Reader) error {
 		}
 	}
 
-	c.proxy = NewProxy(c.ctx, c.port, c.subdomain, openStream, c.tunnelConn, c.logger)
+	// With extra tunnels every stream carries a port header so the client
+	// knows which local service the connection is for; a single-port tunnel
+	// keeps the bare stream for compatibility.
+	primaryOpen := openStream
+	if len(c.extraConns) > 0 {
+		primaryOpen = prefixedOpenStream(openStream, c.localPort)
+	}
+
+	c.proxy = NewProxy(c.ctx, c.port, c.subdomain, primaryOpen, c.tunnelConn, c.logger)
 	if err := c.proxy.Start(); err != nil {
 		return fmt.Errorf("failed to start tcp proxy: %w", err)
 	}
 
+	// Forward connections (drip forward) reach this tunnel through the
+	// manager rather than the public port.
+	if c.tunnelConn != nil {
+		c.tunnelConn.SetOpenStream(primaryOpen)
+	}
+
+	for i, ec := range c.extraConns {
+		extraOpen := prefixedOpenStream(openStream, c.extraLocalPorts[i])
+		ep := NewProxy(c.ctx, c.extraPorts[i], c.extraSubs[i], extraOpen, ec, c.logger)
+		if err := ep.Start(); err != nil {
+			return fmt.Errorf("failed to start extra tcp proxy: %w", err)
+		}
+		c.extraProxies = append(c.extraProxies, ep)
+		ec.SetOpenStream(extraOpen)
+	}
+
+	go c.measureLatency(session)
+	go c.acceptEventStreams(session)
+
+	select {
+	case <-c.stopCh:
+		return nil
+	case <-session.CloseChan():
+		return nil
+	}
+}
+
+// handleUDPTunnel mirrors handleTCPTunnel but relays datagrams: the public
+// side is a UDP listener and each visitor address maps to one mux stream.
+func (c *Connection) handleUDPTunnel(reader *bufio.Reader) error {
+	bc := &bufferedConn{
+		Conn:   c.conn,
+		reader: reader,
+	}
+
+	session, err := yamux.Client(bc, netutil.YamuxConfig())
+	if err != nil {
+		return fmt.Errorf("failed to init yamux session: %w", err)
+	}
+	c.session = session
+
+	c.udpProxy = NewUDPProxy(c.port, c.subdomain, session.Open, c.tunnelConn, c.logger)
+	if err := c.udpProxy.Start(); err != nil {
+		return fmt.Errorf("failed to start udp proxy: %w", err)
+	}
+
+	go c.measureLatency(session)
+	go c.acceptEventStreams(session)
+
+	select {
+	case <-c.stopCh:
+		return nil
+	case <-session.CloseChan():
+		return nil
+	}
+}
+
+// handleSOCKSTunnel exposes a SOCKS5 endpoint on the allocated public port.
+// The proxy does the handshake and auth; each accepted CONNECT becomes one
+// mux stream carrying the target address for the client to dial.
+func (c *Connection) handleSOCKSTunnel(reader *bufio.Reader, user, pass string) error {
+	bc := &bufferedConn{
+		Conn:   c.conn,
+		reader: reader,
+	}
+
+	session, err := yamux.Client(bc, netutil.YamuxConfig())
+	if err != nil {
+		return fmt.Errorf("failed to init yamux session: %w", err)
+	}
+	c.session = session
+
+	c.socksProxy = NewSOCKSProxy(c.ctx, c.port, c.subdomain, user, pass, session.Open, c.tunnelConn, c.logger)
+	if err := c.socksProxy.Start(); err != nil {
+		return fmt.Errorf("failed to start socks proxy: %w", err)
+	}
+
+	go c.measureLatency(session)
+	go c.acceptEventStreams(session)
+
 	select {
 	case <-c.stopCh:
 		return nil
@@ -59,6 +164,33 @@ func (c *Connection) handleTCPTunnel(reader *bufio.Reader) error {
 	}
 }
 
+// measureLatency periodically pings the yamux session and records the RTT on
+// the tunnel connection, so the admin API can report per-client latency.
+func (c *Connection) measureLatency(session *yamux.Session) {
+	ticker := time.NewTicker(constants.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rtt, err := session.Ping()
+			if err != nil {
+				continue
+			}
+			if c.tunnelConn != nil {
+				c.tunnelConn.SetLatency(rtt)
+			}
+			for _, ec := range c.extraConns {
+				ec.SetLatency(rtt)
+			}
+		case <-c.stopCh:
+			return
+		case <-session.CloseChan():
+			return
+		}
+	}
+}
+
 func (c *Connection) handleHTTPProxyTunnel(reader *bufio.Reader) error {
 	// Public server acts as yamux Client, client connector acts as yamux Server.
 	bc := &bufferedConn{
@@ -66,12 +198,7 @@ func (c *Connection) handleHTTPProxyTunnel(reader *bufio.Reader) error {
 		reader: reader,
 	}
 
-	cfg := yamux.DefaultConfig()
-	cfg.EnableKeepAlive = false
-	cfg.LogOutput = io.Discard
-	cfg.AcceptBacklog = constants.YamuxAcceptBacklog
-
-	session, err := yamux.Client(bc, cfg)
+	session, err := yamux.Client(bc, netutil.YamuxConfig())
 	if err != nil {
 		return fmt.Errorf("failed to init yamux session: %w", err)
 	}
@@ -88,6 +215,12 @@ func (c *Connection) handleHTTPProxyTunnel(reader *bufio.Reader) error {
 	if c.tunnelConn != nil {
 		c.tunnelConn.SetOpenStream(openStream)
 	}
+	for _, ec := range c.extraConns {
+		ec.SetOpenStream(openStream)
+	}
+
+	go c.measureLatency(session)
+	go c.acceptEventStreams(session)
 
 	select {
 	case <-c.stopCh:
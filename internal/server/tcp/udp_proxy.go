@@ -0,0 +1,289 @@
+package tcp
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"drip/internal/shared/protocol"
+
+	"go.uber.org/zap"
+)
+
+// udpFlowIdleTimeout closes a visitor's relay stream after this long without
+// datagrams in either direction. Long enough for game-server tick gaps,
+// short enough that abandoned DNS flows don't pile up.
+const udpFlowIdleTimeout = 90 * time.Second
+
+// UDPProxy exposes a public UDP port and relays datagrams over the tunnel.
+// Each visitor address gets a dedicated mux stream carrying length-prefixed
+// datagrams (see protocol.WriteDatagram), so ordering within a flow is
+// preserved and replies find their way back to the right visitor.
+type UDPProxy struct {
+	port      int
+	subdomain string
+	logger    *zap.Logger
+
+	pconn      *net.UDPConn
+	openStream func() (net.Conn, error)
+	stats      trafficStats
+
+	stopCh chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	flows map[string]*udpFlow
+}
+
+type udpFlow struct {
+	stream     net.Conn
+	lastActive atomic.Int64 // unix nanos
+}
+
+func (f *udpFlow) touch() {
+	f.lastActive.Store(time.Now().UnixNano())
+}
+
+func (f *udpFlow) idle() time.Duration {
+	return time.Since(time.Unix(0, f.lastActive.Load()))
+}
+
+func NewUDPProxy(port int, subdomain string, openStream func() (net.Conn, error), stats trafficStats, logger *zap.Logger) *UDPProxy {
+	return &UDPProxy{
+		port:       port,
+		subdomain:  subdomain,
+		logger:     logger,
+		openStream: openStream,
+		stats:      stats,
+		stopCh:     make(chan struct{}),
+		flows:      make(map[string]*udpFlow),
+	}
+}
+
+func (p *UDPProxy) Start() error {
+	pconn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4zero, Port: p.port})
+	if err != nil {
+		return fmt.Errorf("failed to listen on udp port %d: %w", p.port, err)
+	}
+	p.pconn = pconn
+
+	p.logger.Info("UDP proxy started",
+		zap.Int("port", p.port),
+		zap.String("subdomain", p.subdomain),
+	)
+
+	p.wg.Add(2)
+	go p.readLoop()
+	go p.reapLoop()
+	return nil
+}
+
+func (p *UDPProxy) Stop() {
+	p.once.Do(func() {
+		close(p.stopCh)
+
+		if p.pconn != nil {
+			_ = p.pconn.Close()
+		}
+
+		p.mu.Lock()
+		for key, flow := range p.flows {
+			_ = flow.stream.Close()
+			delete(p.flows, key)
+		}
+		p.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			p.wg.Wait()
+			close(done)
+		}()
+
+		const stopTimeout = 10 * time.Second
+
+		select {
+		case <-done:
+			p.logger.Info("UDP proxy stopped",
+				zap.Int("port", p.port),
+				zap.String("subdomain", p.subdomain),
+			)
+		case <-time.After(stopTimeout):
+			p.logger.Warn("UDP proxy stop timed out",
+				zap.Int("port", p.port),
+				zap.String("subdomain", p.subdomain),
+				zap.Duration("timeout", stopTimeout),
+			)
+		}
+	})
+}
+
+// readLoop receives public datagrams and forwards each onto its visitor's
+// stream, opening one lazily for addresses not seen before.
+func (p *UDPProxy) readLoop() {
+	defer p.wg.Done()
+
+	buf := make([]byte, protocol.MaxDatagramSize)
+	for {
+		n, raddr, err := p.pconn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-p.stopCh:
+				return
+			default:
+				continue
+			}
+		}
+
+		flow := p.flowFor(raddr)
+		if flow == nil {
+			continue
+		}
+		flow.touch()
+
+		if err := protocol.WriteDatagram(flow.stream, buf[:n]); err != nil {
+			p.dropFlow(raddr.String())
+			continue
+		}
+		if p.stats != nil {
+			p.stats.AddBytesIn(int64(n))
+		}
+	}
+}
+
+// flowFor returns the visitor's flow, opening a fresh relay stream on first
+// contact. Returns nil when the tunnel cannot provide a stream.
+func (p *UDPProxy) flowFor(raddr *net.UDPAddr) *udpFlow {
+	key := raddr.String()
+
+	p.mu.Lock()
+	if flow, ok := p.flows[key]; ok {
+		p.mu.Unlock()
+		return flow
+	}
+	p.mu.Unlock()
+
+	if p.openStream == nil {
+		return nil
+	}
+
+	// Open outside the lock; stream setup crosses the network.
+	const openStreamTimeout = 10 * time.Second
+	type streamResult struct {
+		stream net.Conn
+		err    error
+	}
+	resultCh := make(chan streamResult, 1)
+	go func() {
+		s, err := p.openStream()
+		resultCh <- streamResult{s, err}
+	}()
+
+	var stream net.Conn
+	select {
+	case result := <-resultCh:
+		if result.err != nil {
+			p.logger.Debug("Failed to open stream for UDP flow", zap.Error(result.err))
+			return nil
+		}
+		stream = result.stream
+	case <-time.After(openStreamTimeout):
+		go func() {
+			if result := <-resultCh; result.stream != nil {
+				_ = result.stream.Close()
+			}
+		}()
+		return nil
+	case <-p.stopCh:
+		return nil
+	}
+
+	flow := &udpFlow{stream: stream}
+	flow.touch()
+
+	p.mu.Lock()
+	if existing, ok := p.flows[key]; ok {
+		// Another datagram raced us here; keep the first stream.
+		p.mu.Unlock()
+		_ = stream.Close()
+		return existing
+	}
+	p.flows[key] = flow
+	p.mu.Unlock()
+
+	if p.stats != nil {
+		p.stats.IncActiveConnections()
+	}
+
+	p.wg.Add(1)
+	go p.runFlow(key, raddr, flow)
+	return flow
+}
+
+// runFlow copies tunnel replies back to the visitor until the stream closes.
+func (p *UDPProxy) runFlow(key string, raddr *net.UDPAddr, flow *udpFlow) {
+	defer p.wg.Done()
+	defer p.dropFlow(key)
+
+	buf := make([]byte, protocol.MaxDatagramSize)
+	for {
+		n, err := protocol.ReadDatagram(flow.stream, buf)
+		if err != nil {
+			return
+		}
+		flow.touch()
+
+		if _, err := p.pconn.WriteToUDP(buf[:n], raddr); err != nil {
+			return
+		}
+		if p.stats != nil {
+			p.stats.AddBytesOut(int64(n))
+		}
+	}
+}
+
+func (p *UDPProxy) dropFlow(key string) {
+	p.mu.Lock()
+	flow, ok := p.flows[key]
+	if ok {
+		delete(p.flows, key)
+	}
+	p.mu.Unlock()
+
+	if ok {
+		_ = flow.stream.Close()
+		if p.stats != nil {
+			p.stats.DecActiveConnections()
+		}
+	}
+}
+
+// reapLoop drops flows that have been idle past the timeout.
+func (p *UDPProxy) reapLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(udpFlowIdleTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			var expired []string
+			for key, flow := range p.flows {
+				if flow.idle() > udpFlowIdleTimeout {
+					expired = append(expired, key)
+				}
+			}
+			p.mu.Unlock()
+
+			for _, key := range expired {
+				p.dropFlow(key)
+			}
+		}
+	}
+}
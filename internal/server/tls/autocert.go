@@ -1,7 +1,9 @@
 package tls
 
 import (
+	"context"
 	"crypto/tls"
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,16 +14,28 @@ import (
 
 // AutoCertManager manages automatic certificate provisioning with Let's Encrypt
 type AutoCertManager struct {
-	manager *autocert.Manager
-	logger  *zap.Logger
+	manager     *autocert.Manager
+	logger      *zap.Logger
+	extraPolicy func(host string) bool
 }
 
 // NewAutoCertManager creates a new AutoCert manager
 func NewAutoCertManager(domain, cacheDir string, logger *zap.Logger) *AutoCertManager {
+	a := &AutoCertManager{logger: logger}
+
+	whitelist := autocert.HostWhitelist(domain, "*."+domain)
 	m := &autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		HostPolicy: autocert.HostWhitelist(domain, "*."+domain),
-		Cache:      autocert.DirCache(cacheDir),
+		Prompt: autocert.AcceptTOS,
+		HostPolicy: func(ctx context.Context, host string) error {
+			if err := whitelist(ctx, host); err == nil {
+				return nil
+			}
+			if a.extraPolicy != nil && a.extraPolicy(host) {
+				return nil
+			}
+			return fmt.Errorf("acme/autocert: host %q not configured", host)
+		},
+		Cache: autocert.DirCache(cacheDir),
 	}
 
 	logger.Info("AutoTLS enabled",
@@ -29,10 +43,16 @@ func NewAutoCertManager(domain, cacheDir string, logger *zap.Logger) *AutoCertMa
 		zap.String("cache_dir", cacheDir),
 	)
 
-	return &AutoCertManager{
-		manager: m,
-		logger:  logger,
-	}
+	a.manager = m
+	return a
+}
+
+// SetExtraHostPolicy installs an additional host check consulted for
+// hostnames outside the base domain, enabling on-demand issuance for
+// CNAMEd custom domains (e.g. tunnel.Manager.HasCustomDomain). Call before
+// the first TLS handshake.
+func (a *AutoCertManager) SetExtraHostPolicy(allow func(host string) bool) {
+	a.extraPolicy = allow
 }
 
 // GetTLSConfig returns the TLS configuration
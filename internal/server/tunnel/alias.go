@@ -0,0 +1,65 @@
+package tunnel
+
+import (
+	"drip/internal/shared/utils"
+	"go.uber.org/zap"
+)
+
+// aliasEntry records who owns an alias and which subdomain it currently
+// points at. The subdomain is updated on every registration that claims the
+// alias, so the alias stays stable while the underlying tunnel is recreated.
+type aliasEntry struct {
+	Token     string
+	Subdomain string
+}
+
+// SetAlias claims or updates an alias for token, pointing it at subdomain.
+// An alias follows the same naming rules as a subdomain and is bound to the
+// first token that claims it; other tokens get ErrAliasTaken.
+func (m *Manager) SetAlias(alias, token, subdomain string) error {
+	if !utils.ValidateSubdomain(alias) {
+		return ErrInvalidSubdomain
+	}
+	if utils.IsReserved(alias) {
+		return ErrReservedSubdomain
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkSubdomainSafety(alias); err != nil {
+		return err
+	}
+	if m.used[alias] && alias != subdomain {
+		return ErrSubdomainTaken
+	}
+
+	if entry, ok := m.aliases[alias]; ok {
+		if entry.Token != token {
+			return ErrAliasTaken
+		}
+		entry.Subdomain = subdomain
+	} else {
+		m.aliases[alias] = &aliasEntry{Token: token, Subdomain: subdomain}
+	}
+
+	m.logger.Info("Alias updated",
+		zap.String("alias", alias),
+		zap.String("subdomain", subdomain),
+	)
+	return nil
+}
+
+// ResolveAlias returns the subdomain an alias currently points at. The
+// mapping outlives the tunnel itself: resolving an alias whose tunnel is
+// down still succeeds, and the subsequent tunnel lookup reports it offline.
+func (m *Manager) ResolveAlias(alias string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.aliases[alias]
+	if !ok {
+		return "", false
+	}
+	return entry.Subdomain, true
+}
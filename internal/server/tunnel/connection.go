@@ -2,10 +2,12 @@ package tunnel
 
 import (
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"drip/internal/shared/clock"
 	"drip/internal/shared/protocol"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
@@ -14,6 +16,7 @@ import (
 // Connection represents a tunnel connection from a client
 type Connection struct {
 	Subdomain  string
+	InstanceID string // client-supplied instance identifier, may be empty
 	Conn       *websocket.Conn
 	SendCh     chan []byte
 	CloseCh    chan struct{}
@@ -22,11 +25,22 @@ type Connection struct {
 	logger     *zap.Logger
 	closed     bool
 	tunnelType protocol.TunnelType
+	tunnelID   string
+	clock      clock.Clock // activity timestamps; tests inject a fake
 	openStream func() (net.Conn, error)
+	services   []string
+	redirects  []protocol.RedirectRule
+	forceSlash bool
+
+	maintenance    bool
+	maintenanceMsg string
+
+	latency time.Duration // last measured RTT to the client
 
 	bytesIn           atomic.Int64
 	bytesOut          atomic.Int64
 	activeConnections atomic.Int64
+	bufferedBytes     atomic.Int64 // in-flight body bytes counted against the memory budget
 }
 
 // NewConnection creates a new tunnel connection
@@ -39,9 +53,22 @@ func NewConnection(subdomain string, conn *websocket.Conn, logger *zap.Logger) *
 		LastActive: time.Now(),
 		logger:     logger,
 		closed:     false,
+		clock:      clock.System,
 	}
 }
 
+// SetClock replaces the clock behind the activity timestamps, so staleness
+// checks follow a fake clock in integration tests.
+func (c *Connection) SetClock(clk clock.Clock) {
+	if clk == nil {
+		return
+	}
+	c.mu.Lock()
+	c.clock = clk
+	c.LastActive = clk.Now()
+	c.mu.Unlock()
+}
+
 // Send sends data through the WebSocket connection
 func (c *Connection) Send(data []byte) error {
 	c.mu.RLock()
@@ -63,14 +90,14 @@ func (c *Connection) Send(data []byte) error {
 func (c *Connection) UpdateActivity() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.LastActive = time.Now()
+	c.LastActive = c.clock.Now()
 }
 
 // IsAlive checks if the connection is still alive based on last activity
 func (c *Connection) IsAlive(timeout time.Duration) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return time.Since(c.LastActive) < timeout
+	return c.clock.Since(c.LastActive) < timeout
 }
 
 // Close closes the connection and all associated channels
@@ -105,6 +132,22 @@ func (c *Connection) IsClosed() bool {
 	return c.closed
 }
 
+// SetLatency records the most recent round-trip time to the client,
+// measured from transport-level pings.
+func (c *Connection) SetLatency(rtt time.Duration) {
+	c.mu.Lock()
+	c.latency = rtt
+	c.mu.Unlock()
+}
+
+// GetLatency returns the most recently measured round-trip time to the
+// client, or zero if no measurement has completed yet.
+func (c *Connection) GetLatency() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.latency
+}
+
 // SetTunnelType sets the tunnel type.
 func (c *Connection) SetTunnelType(tType protocol.TunnelType) {
 	c.mu.Lock()
@@ -119,6 +162,95 @@ func (c *Connection) GetTunnelType() protocol.TunnelType {
 	return c.tunnelType
 }
 
+// SetTunnelID records the stable ID assigned to this tunnel at registration,
+// so log lines outside the control connection can carry it too.
+func (c *Connection) SetTunnelID(id string) {
+	c.mu.Lock()
+	c.tunnelID = id
+	c.mu.Unlock()
+}
+
+// TunnelID returns the registration-time tunnel ID, or "" for tunnels
+// registered before one was assigned.
+func (c *Connection) TunnelID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tunnelID
+}
+
+// SetServices records the service names the client discovered on its local
+// port (e.g. via gRPC reflection), for display in stats.
+func (c *Connection) SetServices(services []string) {
+	c.mu.Lock()
+	c.services = services
+	c.mu.Unlock()
+}
+
+// GetServices returns the labeled service names, if any.
+func (c *Connection) GetServices() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services
+}
+
+// SetRedirects installs the client's redirect rules, evaluated by the HTTP
+// proxy before each request is forwarded. forceSlash additionally redirects
+// extensionless paths without a trailing slash to path + "/".
+func (c *Connection) SetRedirects(rules []protocol.RedirectRule, forceSlash bool) {
+	c.mu.Lock()
+	c.redirects = rules
+	c.forceSlash = forceSlash
+	c.mu.Unlock()
+}
+
+// MatchRedirect returns the redirect target for a request path, if any rule
+// applies. Rules are checked in registration order; the trailing-slash rule
+// runs last.
+func (c *Connection) MatchRedirect(path string) (string, bool) {
+	c.mu.RLock()
+	rules := c.redirects
+	forceSlash := c.forceSlash
+	c.mu.RUnlock()
+
+	for _, rule := range rules {
+		if prefix, ok := strings.CutSuffix(rule.From, "/*"); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return strings.TrimSuffix(rule.To, "/*") + strings.TrimPrefix(path, prefix), true
+			}
+			continue
+		}
+		if path == rule.From {
+			return rule.To, true
+		}
+	}
+
+	if forceSlash && path != "" && !strings.HasSuffix(path, "/") {
+		last := path[strings.LastIndexByte(path, '/')+1:]
+		if !strings.Contains(last, ".") {
+			return path + "/", true
+		}
+	}
+
+	return "", false
+}
+
+// SetMaintenance toggles maintenance mode for this tunnel. While enabled,
+// the HTTP proxy answers public requests with a 503 maintenance page
+// instead of forwarding them, without dropping the tunnel itself.
+func (c *Connection) SetMaintenance(on bool, message string) {
+	c.mu.Lock()
+	c.maintenance = on
+	c.maintenanceMsg = message
+	c.mu.Unlock()
+}
+
+// Maintenance returns the current maintenance state and message.
+func (c *Connection) Maintenance() (bool, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maintenance, c.maintenanceMsg
+}
+
 // SetOpenStream registers a yamux stream opener for this tunnel.
 // It is used by the HTTP proxy to forward each request over a mux stream.
 func (c *Connection) SetOpenStream(open func() (net.Conn, error)) {
@@ -176,6 +308,33 @@ func (c *Connection) GetActiveConnections() int64 {
 	return c.activeConnections.Load()
 }
 
+// ReserveMemory counts n in-flight bytes against the tunnel's memory budget.
+// It returns false, reserving nothing, when the reservation would push the
+// tunnel past the budget.
+func (c *Connection) ReserveMemory(n, budget int64) bool {
+	for {
+		cur := c.bufferedBytes.Load()
+		if cur+n > budget {
+			return false
+		}
+		if c.bufferedBytes.CompareAndSwap(cur, cur+n) {
+			return true
+		}
+	}
+}
+
+// ReleaseMemory returns previously reserved bytes to the budget.
+func (c *Connection) ReleaseMemory(n int64) {
+	if v := c.bufferedBytes.Add(-n); v < 0 {
+		c.bufferedBytes.Store(0)
+	}
+}
+
+// GetBufferedBytes returns the bytes currently reserved against the budget.
+func (c *Connection) GetBufferedBytes() int64 {
+	return c.bufferedBytes.Load()
+}
+
 // StartWritePump starts the write pump for sending messages
 func (c *Connection) StartWritePump() {
 	// Skip write pump for TCP-only connections (no WebSocket)
@@ -0,0 +1,73 @@
+package tunnel
+
+import (
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// customDomainEntry records who owns a custom domain and which subdomain it
+// currently routes to. Like aliases, the subdomain is updated on every
+// registration that claims the domain, so a CNAMEd hostname stays stable
+// while the tunnel behind it is recreated.
+type customDomainEntry struct {
+	Token     string
+	Subdomain string
+}
+
+var hostnameRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// SetCustomDomain claims or updates a custom domain (e.g. app.example.com,
+// CNAMEd at the drip server) for token, routing it to subdomain. The domain
+// is bound to the first token that claims it; other tokens get
+// ErrDomainTaken.
+func (m *Manager) SetCustomDomain(domain, token, subdomain string) error {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if len(domain) > 253 || !hostnameRegex.MatchString(domain) {
+		return ErrInvalidDomain
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.customDomains[domain]; ok {
+		if entry.Token != token {
+			return ErrDomainTaken
+		}
+		entry.Subdomain = subdomain
+	} else {
+		m.customDomains[domain] = &customDomainEntry{Token: token, Subdomain: subdomain}
+	}
+
+	m.logger.Info("Custom domain updated",
+		zap.String("domain", domain),
+		zap.String("subdomain", subdomain),
+	)
+	return nil
+}
+
+// ResolveCustomDomain returns the subdomain a custom domain currently routes
+// to. As with aliases, the mapping outlives the tunnel: resolving a domain
+// whose tunnel is down still succeeds, and the tunnel lookup reports it
+// offline.
+func (m *Manager) ResolveCustomDomain(host string) (string, bool) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.customDomains[host]
+	if !ok {
+		return "", false
+	}
+	return entry.Subdomain, true
+}
+
+// HasCustomDomain reports whether a hostname has been claimed by any
+// registration. Suitable as an ACME host policy check for on-demand
+// certificate issuance.
+func (m *Manager) HasCustomDomain(host string) bool {
+	_, ok := m.ResolveCustomDomain(host)
+	return ok
+}
@@ -20,4 +20,22 @@ var (
 
 	// ErrReservedSubdomain is returned when trying to use a reserved subdomain
 	ErrReservedSubdomain = errors.New("subdomain is reserved")
+
+	// ErrAliasTaken is returned when an alias is owned by a different token
+	ErrAliasTaken = errors.New("alias is owned by another token")
+
+	// ErrSubdomainClaimed is returned when a subdomain is reserved by a
+	// different token
+	ErrSubdomainClaimed = errors.New("subdomain is reserved by another token")
+
+	// ErrSuspiciousSubdomain is returned when a requested name resembles a
+	// blocklisted brand or an IDN homograph
+	ErrSuspiciousSubdomain = errors.New("subdomain resembles a protected name")
+
+	// ErrInvalidDomain is returned when a custom domain is not a valid hostname
+	ErrInvalidDomain = errors.New("invalid custom domain")
+
+	// ErrDomainTaken is returned when a custom domain is owned by a different
+	// token
+	ErrDomainTaken = errors.New("custom domain is owned by another token")
 )
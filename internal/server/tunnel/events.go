@@ -0,0 +1,78 @@
+package tunnel
+
+import (
+	"sync"
+	"time"
+
+	"drip/internal/shared/protocol"
+)
+
+// eventBufferSize bounds each subscriber's backlog. Events are advisory;
+// a subscriber that cannot keep up loses the oldest unread ones rather
+// than blocking publishers.
+const eventBufferSize = 32
+
+// EventBus fans server-side events (tunnel lifecycle, quota warnings,
+// admin notices) out to subscribed clients. Publish never blocks.
+type EventBus struct {
+	mu   sync.Mutex
+	subs map[chan protocol.Event]struct{}
+}
+
+// NewEventBus creates an event bus with no subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subs: make(map[chan protocol.Event]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns its event channel.
+// The caller must call Unsubscribe when done.
+func (b *EventBus) Subscribe() chan protocol.Event {
+	ch := make(chan protocol.Event, eventBufferSize)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe removes a subscriber. The channel is not closed so a
+// concurrent Publish can never send on a closed channel; the caller
+// simply stops reading.
+func (b *EventBus) Unsubscribe(ch chan protocol.Event) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+}
+
+// Publish delivers an event to every subscriber. When a subscriber's
+// buffer is full, the oldest unread event is dropped to make room so the
+// backlog stays current rather than stale.
+func (b *EventBus) Publish(eventType, subdomain, message string) {
+	ev := protocol.Event{
+		Type:      eventType,
+		Subdomain: subdomain,
+		Message:   message,
+		Time:      time.Now(),
+	}
+
+	b.mu.Lock()
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Full buffer: discard the oldest unread event, then retry.
+			// Both sends stay non-blocking in case the subscriber drains
+			// the channel concurrently.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- ev:
+			default:
+			}
+		}
+	}
+	b.mu.Unlock()
+}
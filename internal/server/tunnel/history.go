@@ -0,0 +1,130 @@
+package tunnel
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"drip/internal/shared/protocol"
+)
+
+// maxHistoryEntries bounds the persisted registration history; the oldest
+// entries are dropped first.
+const maxHistoryEntries = 500
+
+// HistoryEntry records one tunnel registration window, so operators can
+// answer questions like "who exposed port 5432 last Tuesday?".
+type HistoryEntry struct {
+	Subdomain       string `json:"subdomain"`
+	Token           string `json:"token,omitempty"`
+	ClientAddr      string `json:"client_addr"`
+	TunnelType      string `json:"tunnel_type"`
+	ConnectedAt     int64  `json:"connected_at"`
+	DisconnectedAt  int64  `json:"disconnected_at,omitempty"` // zero while connected
+	DurationSeconds int64  `json:"duration_seconds,omitempty"`
+	BytesIn         int64  `json:"bytes_in"`
+	BytesOut        int64  `json:"bytes_out"`
+}
+
+// historyPath returns the file where the registration history is persisted.
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "server_history.json")
+	}
+	return filepath.Join(home, ".drip", "server_history.json")
+}
+
+// loadHistory reads the persisted history; a missing or unreadable file
+// simply starts the history fresh.
+func loadHistory(path string) []*HistoryEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entries []*HistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+	return entries
+}
+
+// saveHistory persists the history; failures are non-fatal since the
+// history is advisory. Callers must hold the manager lock.
+func saveHistory(path string, entries []*HistoryEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// RecordConnect opens a history entry for a fresh registration. The tcp
+// listener calls it after Register succeeds, since only it knows the
+// client address and token.
+func (m *Manager) RecordConnect(subdomain, token, clientAddr string, tunnelType protocol.TunnelType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &HistoryEntry{
+		Subdomain:   subdomain,
+		Token:       token,
+		ClientAddr:  clientAddr,
+		TunnelType:  string(tunnelType),
+		ConnectedAt: time.Now().Unix(),
+	}
+	m.history = append(m.history, entry)
+	if len(m.history) > maxHistoryEntries {
+		m.history = m.history[len(m.history)-maxHistoryEntries:]
+	}
+	m.openHistory[subdomain] = entry
+
+	if err := saveHistory(m.historyPath, m.history); err != nil {
+		m.logger.Warn("Failed to persist connection history")
+	}
+}
+
+// finishHistory closes the subdomain's open entry with final duration and
+// traffic counters. Caller must hold m.mu.
+func (m *Manager) finishHistory(tc *Connection) {
+	entry, ok := m.openHistory[tc.Subdomain]
+	if !ok {
+		return
+	}
+	delete(m.openHistory, tc.Subdomain)
+
+	now := time.Now().Unix()
+	entry.DisconnectedAt = now
+	entry.DurationSeconds = now - entry.ConnectedAt
+	entry.BytesIn = tc.GetBytesIn()
+	entry.BytesOut = tc.GetBytesOut()
+
+	if err := saveHistory(m.historyPath, m.history); err != nil {
+		m.logger.Warn("Failed to persist connection history")
+	}
+}
+
+// HistorySnapshot returns a copy of the registration history, newest
+// first, optionally filtered by subdomain and/or token.
+func (m *Manager) HistorySnapshot(subdomain, token string) []HistoryEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]HistoryEntry, 0, len(m.history))
+	for i := len(m.history) - 1; i >= 0; i-- {
+		entry := m.history[i]
+		if subdomain != "" && entry.Subdomain != subdomain {
+			continue
+		}
+		if token != "" && entry.Token != token {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	return entries
+}
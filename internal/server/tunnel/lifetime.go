@@ -0,0 +1,53 @@
+package tunnel
+
+import (
+	"os"
+	"path/filepath"
+
+	json "github.com/goccy/go-json"
+)
+
+// LifetimeTotals aggregates traffic for a subdomain across all of its
+// connections, surviving server restarts.
+type LifetimeTotals struct {
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+	Sessions int64 `json:"sessions"`
+}
+
+// lifetimeStatsPath returns the file where lifetime totals are persisted.
+func lifetimeStatsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "server_stats.json")
+	}
+	return filepath.Join(home, ".drip", "server_stats.json")
+}
+
+// loadLifetime reads persisted totals; a missing or unreadable file simply
+// starts the totals fresh.
+func loadLifetime(path string) map[string]*LifetimeTotals {
+	totals := make(map[string]*LifetimeTotals)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return totals
+	}
+	if err := json.Unmarshal(data, &totals); err != nil {
+		return make(map[string]*LifetimeTotals)
+	}
+	return totals
+}
+
+// saveLifetime persists totals; failures are non-fatal since the totals are
+// advisory. Callers must hold the manager lock.
+func saveLifetime(path string, totals map[string]*LifetimeTotals) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(totals, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -4,6 +4,8 @@ import (
 	"sync"
 	"time"
 
+	"drip/internal/shared/clock"
+	"drip/internal/shared/protocol"
 	"drip/internal/shared/utils"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
@@ -11,24 +13,93 @@ import (
 
 // Manager manages all active tunnel connections
 type Manager struct {
-	tunnels map[string]*Connection // subdomain -> connection
-	mu      sync.RWMutex
-	used    map[string]bool // track used subdomains
-	logger  *zap.Logger
+	tunnels     map[string]*Connection // subdomain -> connection
+	mu          sync.RWMutex
+	used        map[string]bool // track used subdomains
+	logger      *zap.Logger
+	cleanupStop chan struct{}
+	cleanupOnce sync.Once
+
+	lifetime     map[string]*LifetimeTotals // subdomain -> persisted totals
+	lifetimePath string
+
+	aliases map[string]*aliasEntry // alias -> owning token and current subdomain
+
+	customDomains map[string]*customDomainEntry // CNAMEd hostname -> owning token and current subdomain
+
+	ports map[int]string // dedicated public port -> subdomain, for Router.RoutePort
+
+	reservations     map[string]*Reservation // subdomain -> persisted claim
+	reservationsPath string
+
+	events *EventBus
+
+	uptime map[string]*uptimeRecord // subdomain -> availability windows
+
+	history     []*HistoryEntry          // persisted registration history
+	openHistory map[string]*HistoryEntry // subdomain -> entry still connected
+	historyPath string
+
+	genLength int    // generated-subdomain length; 0 uses the default
+	nameStyle string // utils.NameStyleWords switches generated names to words
+
+	brandBlock []string // normalized names refused in requested subdomains
+
+	clock clock.Clock // drives the cleanup ticker; tests inject a fake
 }
 
 // NewManager creates a new tunnel manager
 func NewManager(logger *zap.Logger) *Manager {
+	lifetimePath := lifetimeStatsPath()
+	resPath := reservationsPath()
+	histPath := historyPath()
 	return &Manager{
-		tunnels: make(map[string]*Connection),
-		used:    make(map[string]bool),
-		logger:  logger,
+		tunnels:          make(map[string]*Connection),
+		used:             make(map[string]bool),
+		logger:           logger,
+		cleanupStop:      make(chan struct{}),
+		lifetime:         loadLifetime(lifetimePath),
+		lifetimePath:     lifetimePath,
+		aliases:          make(map[string]*aliasEntry),
+		customDomains:    make(map[string]*customDomainEntry),
+		ports:            make(map[int]string),
+		reservations:     loadReservations(resPath),
+		reservationsPath: resPath,
+		events:           NewEventBus(),
+		uptime:           make(map[string]*uptimeRecord),
+		history:          loadHistory(histPath),
+		openHistory:      make(map[string]*HistoryEntry),
+		historyPath:      histPath,
+		brandBlock:       append([]string(nil), defaultBrandBlocklist...),
+		clock:            clock.System,
 	}
 }
 
+// SetClock replaces the manager's clock. Call before StartCleanupTask;
+// integration tests use a fake clock to fast-forward cleanup.
+func (m *Manager) SetClock(c clock.Clock) {
+	if c == nil {
+		return
+	}
+	m.mu.Lock()
+	m.clock = c
+	m.mu.Unlock()
+}
+
+// Events returns the bus carrying tunnel lifecycle events, quota warnings
+// and admin notices for subscribed clients.
+func (m *Manager) Events() *EventBus {
+	return m.events
+}
+
 // Register registers a new tunnel connection
 // Returns the assigned subdomain and any error
-func (m *Manager) Register(conn *websocket.Conn, customSubdomain string) (string, error) {
+//
+// instanceID identifies the client instance and may be empty. When a client
+// retries registration after a lost ack, the subdomain is still held by its
+// own previous attempt; a matching instanceID re-attaches the client instead
+// of rejecting it with ErrSubdomainTaken.
+func (m *Manager) Register(conn *websocket.Conn, customSubdomain, instanceID string) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -42,8 +113,26 @@ func (m *Manager) Register(conn *websocket.Conn, customSubdomain string) (string
 		if utils.IsReserved(customSubdomain) {
 			return "", ErrReservedSubdomain
 		}
+		if err := m.checkSubdomainSafety(customSubdomain); err != nil {
+			return "", err
+		}
 		if m.used[customSubdomain] {
-			return "", ErrSubdomainTaken
+			existing := m.tunnels[customSubdomain]
+			if existing == nil || instanceID == "" || existing.InstanceID != instanceID {
+				return "", ErrSubdomainTaken
+			}
+
+			// Same client instance retrying: drop the stale registration
+			// and let the new connection take over the subdomain.
+			m.accumulateLifetime(existing)
+			existing.Close()
+			delete(m.tunnels, customSubdomain)
+			delete(m.used, customSubdomain)
+
+			m.logger.Info("Re-attaching client instance to subdomain",
+				zap.String("subdomain", customSubdomain),
+				zap.String("instance_id", instanceID),
+			)
 		}
 		subdomain = customSubdomain
 	} else {
@@ -53,6 +142,8 @@ func (m *Manager) Register(conn *websocket.Conn, customSubdomain string) (string
 
 	// Create connection
 	tc := NewConnection(subdomain, conn, m.logger)
+	tc.SetClock(m.clock)
+	tc.InstanceID = instanceID
 	m.tunnels[subdomain] = tc
 	m.used[subdomain] = true
 
@@ -64,6 +155,9 @@ func (m *Manager) Register(conn *websocket.Conn, customSubdomain string) (string
 		zap.Int("total_tunnels", len(m.tunnels)),
 	)
 
+	m.events.Publish(protocol.EventTunnelRegistered, subdomain, "tunnel registered")
+	m.markConnected(subdomain)
+
 	return subdomain, nil
 }
 
@@ -73,15 +167,36 @@ func (m *Manager) Unregister(subdomain string) {
 	defer m.mu.Unlock()
 
 	if tc, ok := m.tunnels[subdomain]; ok {
+		m.accumulateLifetime(tc)
 		tc.Close()
 		delete(m.tunnels, subdomain)
 		delete(m.used, subdomain)
+		for port, sub := range m.ports {
+			if sub == subdomain {
+				delete(m.ports, port)
+			}
+		}
 
 		m.logger.Info("Tunnel unregistered",
 			zap.String("subdomain", subdomain),
+			zap.String("tunnel_id", tc.TunnelID()),
 			zap.Int("total_tunnels", len(m.tunnels)),
 		)
+
+		m.events.Publish(protocol.EventTunnelClosed, subdomain, "tunnel closed")
+	}
+}
+
+// SetPort records the dedicated public port a tunnel listens on, so the
+// Router can resolve raw port traffic to its connection. The mapping is
+// removed when the subdomain unregisters.
+func (m *Manager) SetPort(subdomain string, port int) {
+	if port == 0 {
+		return
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ports[port] = subdomain
 }
 
 // Get retrieves a tunnel connection by subdomain
@@ -127,6 +242,7 @@ func (m *Manager) CleanupStale(timeout time.Duration) int {
 
 	for _, subdomain := range staleSubdomains {
 		if tc, ok := m.tunnels[subdomain]; ok {
+			m.accumulateLifetime(tc)
 			tc.Close()
 			delete(m.tunnels, subdomain)
 			delete(m.used, subdomain)
@@ -142,33 +258,127 @@ func (m *Manager) CleanupStale(timeout time.Duration) int {
 	return len(staleSubdomains)
 }
 
-// StartCleanupTask starts a background task to clean up stale connections
+// StartCleanupTask starts a background task to clean up stale connections.
+// The task runs until StopCleanupTask or Shutdown is called.
 func (m *Manager) StartCleanupTask(interval, timeout time.Duration) {
-	ticker := time.NewTicker(interval)
+	m.mu.RLock()
+	clk := m.clock
+	m.mu.RUnlock()
+
 	go func() {
-		for range ticker.C {
-			m.CleanupStale(timeout)
+		ticker := clk.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.Chan():
+				m.CleanupStale(timeout)
+			case <-m.cleanupStop:
+				return
+			}
 		}
 	}()
 }
 
+// StopCleanupTask stops the background cleanup task. Safe to call multiple
+// times, and safe to call even if the task was never started.
+func (m *Manager) StopCleanupTask() {
+	m.cleanupOnce.Do(func() {
+		close(m.cleanupStop)
+	})
+}
+
+// SetSubdomainLength sets the length of generated subdomains, clamped to
+// 6-16 characters. Zero keeps the default.
+func (m *Manager) SetSubdomainLength(length int) {
+	if length == 0 {
+		return
+	}
+	length = min(max(length, 6), 16)
+
+	m.mu.Lock()
+	m.genLength = length
+	m.mu.Unlock()
+}
+
+// SetNameStyle sets the default style for generated subdomains. Only
+// utils.NameStyleWords changes anything; other values keep the character
+// generator.
+func (m *Manager) SetNameStyle(style string) {
+	if style != utils.NameStyleWords {
+		return
+	}
+	m.mu.Lock()
+	m.nameStyle = style
+	m.mu.Unlock()
+}
+
+// GenerateName returns a free generated subdomain in the given style,
+// falling back to the character generator for unknown styles. Used when a
+// client requests a naming style at registration.
+func (m *Manager) GenerateName(style string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generateName(style)
+}
+
+// subdomainAvailable reports whether a generated name is free: not serving
+// a live tunnel, not a reserved word, and not claimed in the persistent
+// reservation store. Caller must hold m.mu.
+func (m *Manager) subdomainAvailable(subdomain string) bool {
+	if m.used[subdomain] || utils.IsReserved(subdomain) {
+		return false
+	}
+	if r, ok := m.reservations[subdomain]; ok && !r.Expired() {
+		return false
+	}
+	return true
+}
+
 // generateUniqueSubdomain generates a unique random subdomain
 func (m *Manager) generateUniqueSubdomain() string {
+	return m.generateName(m.nameStyle)
+}
+
+// generateName generates a unique subdomain in the given style. Word-based
+// collisions fall back to the character generator, whose longer names make
+// repeat collisions vanishingly unlikely. Caller must hold m.mu.
+func (m *Manager) generateName(style string) string {
 	const maxAttempts = 10
 
+	length := m.genLength
+	if length == 0 {
+		length = utils.DefaultSubdomainLength
+	}
+
 	for i := 0; i < maxAttempts; i++ {
-		subdomain := utils.GenerateSubdomain(6)
-		if !m.used[subdomain] && !utils.IsReserved(subdomain) {
+		var subdomain string
+		if style == utils.NameStyleWords {
+			subdomain = utils.GenerateWordSubdomain()
+		} else {
+			subdomain = utils.GenerateSubdomain(length)
+		}
+		if m.subdomainAvailable(subdomain) {
 			return subdomain
 		}
 	}
 
-	// Fallback: use longer subdomain if collision persists
-	return utils.GenerateSubdomain(8)
+	// Fallback: retry longer character names against the same availability
+	// check.
+	longer := min(length+2, 16)
+	for i := 0; i < maxAttempts; i++ {
+		subdomain := utils.GenerateSubdomain(longer)
+		if m.subdomainAvailable(subdomain) {
+			return subdomain
+		}
+	}
+	return utils.GenerateSubdomain(longer)
 }
 
 // Shutdown gracefully shuts down all tunnels
 func (m *Manager) Shutdown() {
+	m.StopCleanupTask()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -177,9 +387,56 @@ func (m *Manager) Shutdown() {
 	)
 
 	for _, tc := range m.tunnels {
+		m.accumulateLifetime(tc)
 		tc.Close()
 	}
 
 	m.tunnels = make(map[string]*Connection)
 	m.used = make(map[string]bool)
 }
+
+// accumulateLifetime folds a closing connection's traffic into the persisted
+// per-subdomain totals. Caller must hold m.mu.
+func (m *Manager) accumulateLifetime(tc *Connection) {
+	totals, ok := m.lifetime[tc.Subdomain]
+	if !ok {
+		totals = &LifetimeTotals{}
+		m.lifetime[tc.Subdomain] = totals
+	}
+
+	totals.BytesIn += tc.GetBytesIn()
+	totals.BytesOut += tc.GetBytesOut()
+	totals.Sessions++
+
+	// Every teardown path funnels through here, so it doubles as the end
+	// of the subdomain's availability window and history entry.
+	m.markDisconnected(tc.Subdomain)
+	m.finishHistory(tc)
+
+	if err := saveLifetime(m.lifetimePath, m.lifetime); err != nil {
+		m.logger.Warn("Failed to persist lifetime stats",
+			zap.Error(err),
+		)
+	}
+}
+
+// LifetimeSnapshot returns a copy of the persisted per-subdomain totals,
+// including traffic from tunnels that are still connected.
+func (m *Manager) LifetimeSnapshot() map[string]LifetimeTotals {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := make(map[string]LifetimeTotals, len(m.lifetime))
+	for subdomain, totals := range m.lifetime {
+		snapshot[subdomain] = *totals
+	}
+
+	for subdomain, tc := range m.tunnels {
+		totals := snapshot[subdomain]
+		totals.BytesIn += tc.GetBytesIn()
+		totals.BytesOut += tc.GetBytesOut()
+		snapshot[subdomain] = totals
+	}
+
+	return snapshot
+}
@@ -0,0 +1,46 @@
+package tunnel
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// Allocation budget for a full register/unregister cycle: the connection,
+// its channels, event publication, uptime and lifetime bookkeeping, and the
+// drain goroutine. A loose ceiling, not a target — it catches a redesign
+// that starts allocating per cycle in bulk, not small drifts. Compare
+// BenchmarkRegisterUnregister with benchstat when tuning.
+const registerAllocBudget = 128
+
+func BenchmarkRegisterUnregister(b *testing.B) {
+	m := NewManager(zap.NewNop())
+	defer m.Shutdown()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		subdomain, err := m.Register(nil, "bench", "bench-instance")
+		if err != nil {
+			b.Fatal(err)
+		}
+		m.Unregister(subdomain)
+	}
+}
+
+func TestRegisterAllocBudget(t *testing.T) {
+	m := NewManager(zap.NewNop())
+	defer m.Shutdown()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		subdomain, err := m.Register(nil, "bench", "bench-instance")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Unregister(subdomain)
+	})
+	if allocs > registerAllocBudget {
+		t.Errorf("register/unregister cycle allocates %.1f objects, budget is %d",
+			allocs, registerAllocBudget)
+	}
+}
@@ -0,0 +1,50 @@
+package tunnel
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// waitForGoroutines waits for the goroutine count to drop back to at most
+// want, giving background goroutines a moment to observe their stop signals.
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	buf := make([]byte, 64*1024)
+	n := runtime.Stack(buf, true)
+	t.Fatalf("goroutine leak: %d goroutines still running, want <= %d\n%s",
+		runtime.NumGoroutine(), want, buf[:n])
+}
+
+func TestCleanupTaskStopsOnShutdown(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	m := NewManager(zap.NewNop())
+	m.StartCleanupTask(10*time.Millisecond, time.Minute)
+
+	// Let the ticker fire at least once before shutting down.
+	time.Sleep(30 * time.Millisecond)
+	m.Shutdown()
+
+	waitForGoroutines(t, before)
+}
+
+func TestStopCleanupTaskIsIdempotent(t *testing.T) {
+	m := NewManager(zap.NewNop())
+
+	// Must not panic when called repeatedly or without a running task.
+	m.StopCleanupTask()
+	m.StopCleanupTask()
+	m.Shutdown()
+}
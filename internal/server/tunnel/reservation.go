@@ -0,0 +1,177 @@
+package tunnel
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"drip/internal/shared/utils"
+	"go.uber.org/zap"
+)
+
+// Reservation is a subdomain claim held for future use, independent of any
+// active tunnel, so a URL can be handed out before the tunnel exists.
+type Reservation struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the reservation has lapsed.
+func (r *Reservation) Expired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// reservationsPath returns the file where reservations are persisted.
+func reservationsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".drip", "server_reservations.json")
+	}
+	return filepath.Join(home, ".drip", "server_reservations.json")
+}
+
+// loadReservations reads persisted reservations, dropping any that have
+// already expired. A missing or unreadable file starts fresh.
+func loadReservations(path string) map[string]*Reservation {
+	reservations := make(map[string]*Reservation)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return reservations
+	}
+	if err := json.Unmarshal(data, &reservations); err != nil {
+		return make(map[string]*Reservation)
+	}
+
+	for name, r := range reservations {
+		if r.Expired() {
+			delete(reservations, name)
+		}
+	}
+	return reservations
+}
+
+// saveReservations persists reservations. Callers must hold the manager
+// lock.
+func saveReservations(path string, reservations map[string]*Reservation) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(reservations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Reserve claims a subdomain for token until ttl elapses. Re-reserving with
+// the same token extends the claim; other tokens get ErrSubdomainClaimed.
+func (m *Manager) Reserve(name, token string, ttl time.Duration) (time.Time, error) {
+	if !utils.ValidateSubdomain(name) {
+		return time.Time{}, ErrInvalidSubdomain
+	}
+	if utils.IsReserved(name) {
+		return time.Time{}, ErrReservedSubdomain
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkSubdomainSafety(name); err != nil {
+		return time.Time{}, err
+	}
+	if m.used[name] {
+		return time.Time{}, ErrSubdomainTaken
+	}
+	if existing, ok := m.reservations[name]; ok && !existing.Expired() && existing.Token != token {
+		return time.Time{}, ErrSubdomainClaimed
+	}
+
+	expires := time.Now().Add(ttl)
+	m.reservations[name] = &Reservation{Token: token, ExpiresAt: expires}
+	m.persistReservations()
+
+	m.logger.Info("Subdomain reserved",
+		zap.String("subdomain", name),
+		zap.Time("expires_at", expires),
+	)
+	return expires, nil
+}
+
+// ReleaseReservation drops a reservation. Only the claiming token may
+// release it.
+func (m *Manager) ReleaseReservation(name, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.reservations[name]
+	if !ok || existing.Expired() {
+		return ErrTunnelNotFound
+	}
+	if existing.Token != token {
+		return ErrSubdomainClaimed
+	}
+
+	delete(m.reservations, name)
+	m.persistReservations()
+
+	m.logger.Info("Subdomain reservation released",
+		zap.String("subdomain", name),
+	)
+	return nil
+}
+
+// CheckReservation returns ErrSubdomainClaimed when name is reserved by a
+// different token. The owner (or anyone, once the claim expires) passes.
+func (m *Manager) CheckReservation(name, token string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if existing, ok := m.reservations[name]; ok && !existing.Expired() && existing.Token != token {
+		return ErrSubdomainClaimed
+	}
+	return nil
+}
+
+// IsReservedClaim reports whether name currently has an unexpired
+// reservation.
+func (m *Manager) IsReservedClaim(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	existing, ok := m.reservations[name]
+	return ok && !existing.Expired()
+}
+
+// ExpiringReservations returns the names and expiry times of reservations
+// that lapse within the given window.
+func (m *Manager) ExpiringReservations(within time.Duration) map[string]time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	deadline := time.Now().Add(within)
+	expiring := make(map[string]time.Time)
+	for name, r := range m.reservations {
+		if !r.Expired() && r.ExpiresAt.Before(deadline) {
+			expiring[name] = r.ExpiresAt
+		}
+	}
+	return expiring
+}
+
+// persistReservations prunes expired claims and writes the store. Caller
+// must hold m.mu.
+func (m *Manager) persistReservations() {
+	for name, r := range m.reservations {
+		if r.Expired() {
+			delete(m.reservations, name)
+		}
+	}
+	if err := saveReservations(m.reservationsPath, m.reservations); err != nil {
+		m.logger.Warn("Failed to persist reservations",
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,73 @@
+package tunnel
+
+import (
+	"strings"
+)
+
+// Router resolves public endpoints — an HTTP Host header, a TLS SNI
+// hostname, or a dedicated TCP/UDP port — to the tunnel connection serving
+// them. The HTTP handler and the SNI passthrough router share this one
+// lookup path, so subdomains, aliases, and custom domains behave
+// identically regardless of how traffic arrives, and a cluster-backed
+// implementation can later slot in behind the same component.
+type Router struct {
+	manager *Manager
+	domain  string
+}
+
+// NewRouter creates a router over the manager for the given base domain.
+func NewRouter(manager *Manager, domain string) *Router {
+	return &Router{manager: manager, domain: domain}
+}
+
+// RouteHost maps a hostname (with optional port, as found in a Host header
+// or SNI extension) to the canonical subdomain and live connection serving
+// it. Hosts under the base domain resolve by subdomain, then alias; hosts
+// outside it resolve as custom domains.
+//
+// ok is false when no live tunnel serves the host. A non-empty subdomain
+// alongside ok=false means the host is under the served domain but its
+// tunnel is gone (a 404 for the caller); an empty subdomain means the host
+// is foreign.
+func (r *Router) RouteHost(host string) (subdomain string, conn *Connection, ok bool) {
+	if idx := strings.Index(host, ":"); idx != -1 {
+		host = host[:idx]
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if host == r.domain {
+		return "", nil, false
+	}
+
+	if sub, under := strings.CutSuffix(host, "."+r.domain); under {
+		if conn, ok := r.manager.Get(sub); ok {
+			return sub, conn, true
+		}
+		if target, aliased := r.manager.ResolveAlias(sub); aliased {
+			conn, ok := r.manager.Get(target)
+			return target, conn, ok
+		}
+		return sub, nil, false
+	}
+
+	if target, custom := r.manager.ResolveCustomDomain(host); custom {
+		conn, ok := r.manager.Get(target)
+		return target, conn, ok
+	}
+
+	return "", nil, false
+}
+
+// RoutePort maps a dedicated public TCP/UDP port to the connection that
+// registered it.
+func (r *Router) RoutePort(port int) (*Connection, bool) {
+	m := r.manager
+
+	m.mu.RLock()
+	subdomain, ok := m.ports[port]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return m.Get(subdomain)
+}
@@ -0,0 +1,49 @@
+package tunnel
+
+import (
+	"strings"
+
+	"drip/internal/shared/utils"
+)
+
+// defaultBrandBlocklist lists names most often imitated in phishing pages
+// hosted on public tunnel services. Matching happens after confusable
+// normalization, so "pay-pa1" is caught too. Operators can extend the list
+// with --brand-blocklist.
+var defaultBrandBlocklist = []string{
+	"paypal", "apple", "google", "amazon", "microsoft", "netflix",
+	"facebook", "instagram", "whatsapp", "coinbase", "binance", "metamask",
+	"login", "signin", "verify", "secure", "account", "banking", "wallet",
+	"password", "webmail", "invoice",
+}
+
+// AddBrandBlocklist appends operator-supplied entries to the brand
+// blocklist. Entries are lowercased; empty strings are ignored.
+func (m *Manager) AddBrandBlocklist(names []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			m.brandBlock = append(m.brandBlock, name)
+		}
+	}
+}
+
+// checkSubdomainSafety returns ErrSuspiciousSubdomain when a requested name
+// is punycode-encoded or contains a blocklisted brand after confusable
+// normalization. Caller must hold m.mu.
+func (m *Manager) checkSubdomainSafety(name string) error {
+	if utils.IsPunycode(name) {
+		return ErrSuspiciousSubdomain
+	}
+
+	norm := utils.NormalizeConfusables(name)
+	for _, brand := range m.brandBlock {
+		if strings.Contains(norm, brand) {
+			return ErrSuspiciousSubdomain
+		}
+	}
+	return nil
+}
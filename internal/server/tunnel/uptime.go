@@ -0,0 +1,83 @@
+package tunnel
+
+import (
+	"time"
+)
+
+// uptimeRecord accumulates connected time for one subdomain. Records live
+// in memory, so percentages cover the window since the server started (or
+// since the subdomain was first seen, whichever is later).
+type uptimeRecord struct {
+	firstSeen   time.Time
+	connected   time.Duration // closed connection windows
+	connectedAt time.Time     // zero while disconnected
+}
+
+// UptimeReport is one subdomain's availability summary.
+type UptimeReport struct {
+	Subdomain        string  `json:"subdomain"`
+	Online           bool    `json:"online"`
+	FirstSeen        int64   `json:"first_seen"`
+	ObservedSeconds  int64   `json:"observed_seconds"`
+	ConnectedSeconds int64   `json:"connected_seconds"`
+	UptimePercent    float64 `json:"uptime_percent"`
+}
+
+// markConnected opens an availability window for subdomain. Caller must
+// hold m.mu.
+func (m *Manager) markConnected(subdomain string) {
+	now := time.Now()
+	rec, ok := m.uptime[subdomain]
+	if !ok {
+		rec = &uptimeRecord{firstSeen: now}
+		m.uptime[subdomain] = rec
+	}
+	rec.connectedAt = now
+}
+
+// markDisconnected closes the subdomain's current availability window.
+// Caller must hold m.mu.
+func (m *Manager) markDisconnected(subdomain string) {
+	rec, ok := m.uptime[subdomain]
+	if !ok || rec.connectedAt.IsZero() {
+		return
+	}
+	rec.connected += time.Since(rec.connectedAt)
+	rec.connectedAt = time.Time{}
+}
+
+// UptimeSnapshot returns availability summaries for every subdomain seen
+// since the server started, including currently disconnected ones.
+func (m *Manager) UptimeSnapshot() []UptimeReport {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	reports := make([]UptimeReport, 0, len(m.uptime))
+	for subdomain, rec := range m.uptime {
+		connected := rec.connected
+		online := !rec.connectedAt.IsZero()
+		if online {
+			connected += now.Sub(rec.connectedAt)
+		}
+
+		observed := now.Sub(rec.firstSeen)
+		percent := 100.0
+		if observed > 0 {
+			percent = float64(connected) / float64(observed) * 100
+			if percent > 100 {
+				percent = 100
+			}
+		}
+
+		reports = append(reports, UptimeReport{
+			Subdomain:        subdomain,
+			Online:           online,
+			FirstSeen:        rec.firstSeen.Unix(),
+			ObservedSeconds:  int64(observed.Seconds()),
+			ConnectedSeconds: int64(connected.Seconds()),
+			UptimePercent:    percent,
+		})
+	}
+	return reports
+}
@@ -0,0 +1,210 @@
+// Package watchdog samples process health on the server — goroutine count,
+// heap size, and registered gauges like frame write backlogs and pending
+// responses — and logs warnings with diffs when a metric trends upward
+// abnormally, so leaks surface in the logs before the OOM killer does. On a
+// warning it can also write a diagnostic dump (goroutine and heap profiles)
+// for offline analysis.
+package watchdog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"drip/internal/shared/clock"
+)
+
+const (
+	// DefaultInterval is how often the watchdog samples.
+	DefaultInterval = time.Minute
+
+	// defaultWindow is how many samples the trend comparison spans.
+	defaultWindow = 30
+
+	// growthFactor flags a metric that has at least doubled over the window.
+	growthFactor = 2.0
+
+	// dumpCooldown limits diagnostic dumps to one per period, however many
+	// metrics are trending.
+	dumpCooldown = 30 * time.Minute
+
+	goroutineFloor = 500
+	heapFloor      = 256 << 20
+)
+
+// gauge is one registered metric with its warning floor; values below the
+// floor never warn no matter how fast they grow.
+type gauge struct {
+	name  string
+	floor int64
+	fn    func() int64
+}
+
+// sample is one point-in-time reading of every metric.
+type sample struct {
+	at         time.Time
+	goroutines int64
+	heap       int64
+	gauges     []int64
+}
+
+// Watchdog periodically samples process health and warns on abnormal growth.
+type Watchdog struct {
+	logger   *zap.Logger
+	interval time.Duration
+	clock    clock.Clock
+	dumpDir  string
+
+	mu       sync.Mutex
+	gauges   []gauge
+	samples  []sample // sliding window, oldest first
+	lastDump time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a watchdog sampling at the given interval. Diagnostic dumps go
+// to dumpDir; an empty dumpDir disables dumps but keeps the warnings.
+func New(logger *zap.Logger, interval time.Duration, dumpDir string) *Watchdog {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Watchdog{
+		logger:   logger,
+		interval: interval,
+		clock:    clock.System,
+		dumpDir:  dumpDir,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// AddGauge registers a named metric. Growth warnings only fire once the
+// value is at or above floor. Call before Start.
+func (w *Watchdog) AddGauge(name string, floor int64, fn func() int64) {
+	w.mu.Lock()
+	w.gauges = append(w.gauges, gauge{name: name, floor: floor, fn: fn})
+	w.mu.Unlock()
+}
+
+// Start begins sampling in the background.
+func (w *Watchdog) Start() {
+	go w.loop()
+}
+
+// Stop ends sampling. Safe to call more than once.
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+func (w *Watchdog) loop() {
+	ticker := w.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Chan():
+			w.tick()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+func (w *Watchdog) tick() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	s := sample{
+		at:         w.clock.Now(),
+		goroutines: int64(runtime.NumGoroutine()),
+		heap:       int64(mem.HeapAlloc),
+		gauges:     make([]int64, len(w.gauges)),
+	}
+	for i, g := range w.gauges {
+		s.gauges[i] = g.fn()
+	}
+
+	w.samples = append(w.samples, s)
+	if len(w.samples) > defaultWindow {
+		w.samples = w.samples[len(w.samples)-defaultWindow:]
+	}
+	if len(w.samples) < 2 {
+		return
+	}
+
+	oldest := w.samples[0]
+	span := s.at.Sub(oldest.at)
+
+	trending := false
+	trending = w.check("goroutines", oldest.goroutines, s.goroutines, goroutineFloor, span) || trending
+	trending = w.check("heap_bytes", oldest.heap, s.heap, heapFloor, span) || trending
+	for i, g := range w.gauges {
+		trending = w.check(g.name, oldest.gauges[i], s.gauges[i], g.floor, span) || trending
+	}
+
+	if trending {
+		w.maybeDumpLocked(s.at)
+	}
+}
+
+// check logs a warning when a metric is above its floor and has grown by
+// growthFactor over the window. Returns whether it warned.
+func (w *Watchdog) check(name string, was, now, floor int64, span time.Duration) bool {
+	if now < floor {
+		return false
+	}
+	if was <= 0 || float64(now) < float64(was)*growthFactor {
+		return false
+	}
+
+	w.logger.Warn("Watchdog: metric trending upward",
+		zap.String("metric", name),
+		zap.Int64("was", was),
+		zap.Int64("now", now),
+		zap.Int64("delta", now-was),
+		zap.Duration("window", span),
+	)
+	return true
+}
+
+// maybeDumpLocked writes goroutine and heap profiles, rate-limited to one
+// dump per cooldown.
+func (w *Watchdog) maybeDumpLocked(now time.Time) {
+	if w.dumpDir == "" || now.Sub(w.lastDump) < dumpCooldown {
+		return
+	}
+	w.lastDump = now
+
+	if err := os.MkdirAll(w.dumpDir, 0700); err != nil {
+		w.logger.Warn("Watchdog: failed to create dump directory", zap.Error(err))
+		return
+	}
+
+	stamp := now.Format("20060102-150405")
+	goroutinePath := filepath.Join(w.dumpDir, fmt.Sprintf("watchdog-%s-goroutines.txt", stamp))
+	heapPath := filepath.Join(w.dumpDir, fmt.Sprintf("watchdog-%s-heap.pb.gz", stamp))
+
+	if f, err := os.OpenFile(goroutinePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600); err == nil {
+		_ = pprof.Lookup("goroutine").WriteTo(f, 1)
+		_ = f.Close()
+	}
+	if f, err := os.OpenFile(heapPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600); err == nil {
+		_ = pprof.Lookup("heap").WriteTo(f, 0)
+		_ = f.Close()
+	}
+
+	w.logger.Warn("Watchdog: diagnostic dump written",
+		zap.String("goroutines", goroutinePath),
+		zap.String("heap", heapPath),
+	)
+}
@@ -0,0 +1,38 @@
+// Package clock abstracts time for components built around heartbeats,
+// cleanup tickers, and backoff, so integration tests can fast-forward
+// deterministically with a fake clock instead of sleeping through real
+// intervals.
+package clock
+
+import "time"
+
+// Clock is the subset of package time that the tunnel components use.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker mirrors time.Ticker behind an interface so a fake clock can fire
+// ticks on demand.
+type Ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+// System is the real clock, backed by package time. Components default to
+// it; tests swap in a Fake.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker       { return systemTicker{time.NewTicker(d)} }
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) Chan() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()                  { t.t.Stop() }
@@ -0,0 +1,125 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a manually advanced Clock for tests. Time only moves when
+// Advance is called; timers and tickers due by the new time fire in
+// chronological order.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is one pending After channel or ticker. Tickers re-arm by
+// their period; After waiters fire once.
+type fakeWaiter struct {
+	at      time.Time
+	period  time.Duration // 0 for After
+	ch      chan time.Time
+	stopped bool
+}
+
+// NewFake returns a fake clock frozen at start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{at: f.now.Add(d), ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return w.ch
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{at: f.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return &fakeTicker{clock: f, waiter: w}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker due
+// along the way. Sends never block: like the time package, a tick is
+// dropped when the receiver has not consumed the previous one.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target := f.now.Add(d)
+	for {
+		next := f.nextDueLocked(target)
+		if next == nil {
+			break
+		}
+
+		f.now = next.at
+		select {
+		case next.ch <- next.at:
+		default:
+		}
+
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+		} else {
+			next.stopped = true
+		}
+		f.pruneLocked()
+	}
+	f.now = target
+}
+
+// nextDueLocked returns the earliest live waiter due at or before target.
+func (f *Fake) nextDueLocked(target time.Time) *fakeWaiter {
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].at.Before(f.waiters[j].at)
+	})
+	for _, w := range f.waiters {
+		if !w.stopped && !w.at.After(target) {
+			return w
+		}
+	}
+	return nil
+}
+
+func (f *Fake) pruneLocked() {
+	live := f.waiters[:0]
+	for _, w := range f.waiters {
+		if !w.stopped {
+			live = append(live, w)
+		}
+	}
+	f.waiters = live
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) Chan() <-chan time.Time { return t.waiter.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	t.waiter.stopped = true
+	t.clock.pruneLocked()
+	t.clock.mu.Unlock()
+}
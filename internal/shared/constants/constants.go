@@ -13,6 +13,12 @@ const (
 	// before yamux starts blocking stream opens under load.
 	YamuxAcceptBacklog = 4096
 
+	// YamuxStreamWindow is the per-stream receive window. Flow control is
+	// per stream, so a slow visitor only stalls its own stream; a window
+	// larger than yamux's 256KB default keeps fast streams from being
+	// throughput-capped on high-latency links.
+	YamuxStreamWindow = 1024 * 1024
+
 	// HeartbeatInterval is how often clients send heartbeat messages
 	HeartbeatInterval = 2 * time.Second
 
@@ -31,6 +37,30 @@ const (
 	// MaxReconnectAttempts is the maximum number of reconnection attempts (0 = infinite)
 	MaxReconnectAttempts = 0
 
+	// PublicReadHeaderTimeout bounds how long a public visitor may take to
+	// send request headers, limiting slow-loris style attacks on the edge.
+	PublicReadHeaderTimeout = 10 * time.Second
+
+	// PublicMaxHeaderBytes caps request header size for public HTTP traffic.
+	PublicMaxHeaderBytes = 64 * 1024
+
+	// PublicMaxConcurrentStreams limits HTTP/2 streams per public connection.
+	PublicMaxConcurrentStreams = 250
+
+	// PublicMaxConnsPerIP limits concurrent public HTTP connections from a
+	// single IP so one hostile visitor can't pin server goroutines or the
+	// owning tunnel's capacity.
+	PublicMaxConnsPerIP = 64
+
+	// MaxPendingPerTunnel caps concurrent in-flight public requests per
+	// tunnel. Further requests fast-fail with 503 instead of queueing
+	// unboundedly against a slow client.
+	MaxPendingPerTunnel = 512
+
+	// OpenStreamTimeout is how long the proxy waits for the tunnel client
+	// to accept a new stream before failing the public request.
+	OpenStreamTimeout = 10 * time.Second
+
 	// DefaultTCPPortMin/Max define the default allocation range for TCP tunnels
 	DefaultTCPPortMin = 20000
 	DefaultTCPPortMax = 40000
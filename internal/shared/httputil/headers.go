@@ -0,0 +1,79 @@
+package httputil
+
+import (
+	"net/http"
+	"sync"
+)
+
+// commonHeaderKeys interns the canonical forms of frequently seen header
+// keys, so per-request parsing shares one string instance instead of
+// retaining a fresh copy per request.
+var commonHeaderKeys = func() map[string]string {
+	keys := []string{
+		"Accept",
+		"Accept-Encoding",
+		"Accept-Language",
+		"Authorization",
+		"Cache-Control",
+		"Connection",
+		"Content-Encoding",
+		"Content-Length",
+		"Content-Type",
+		"Cookie",
+		"Date",
+		"Etag",
+		"Host",
+		"If-Modified-Since",
+		"If-None-Match",
+		"Last-Modified",
+		"Location",
+		"Origin",
+		"Referer",
+		"Server",
+		"Set-Cookie",
+		"Upgrade",
+		"User-Agent",
+		"Vary",
+		"X-Forwarded-For",
+		"X-Forwarded-Proto",
+		"X-Request-Id",
+	}
+	m := make(map[string]string, len(keys))
+	for _, k := range keys {
+		m[k] = k
+	}
+	return m
+}()
+
+// InternHeaderKey canonicalizes key, returning the shared instance for
+// common headers.
+func InternHeaderKey(key string) string {
+	canonical := http.CanonicalHeaderKey(key)
+	if interned, ok := commonHeaderKeys[canonical]; ok {
+		return interned
+	}
+	return canonical
+}
+
+// headerPool recycles header maps between requests; reused maps keep their
+// bucket storage, cutting a per-request allocation on busy HTTP tunnels.
+var headerPool = sync.Pool{
+	New: func() interface{} { return make(http.Header, 16) },
+}
+
+// GetHeader returns an empty header map from the pool.
+func GetHeader() http.Header {
+	return headerPool.Get().(http.Header)
+}
+
+// PutHeader clears h and returns it to the pool. The caller must not use h
+// afterwards.
+func PutHeader(h http.Header) {
+	if h == nil {
+		return
+	}
+	for k := range h {
+		delete(h, k)
+	}
+	headerPool.Put(h)
+}
@@ -53,7 +53,7 @@ func WriteProxyError(w io.Writer, code int, msg string) {
 		Proto:         "HTTP/1.1",
 		ProtoMajor:    1,
 		ProtoMinor:    1,
-		Header:        make(http.Header),
+		Header:        GetHeader(),
 		Body:          io.NopCloser(strings.NewReader(body)),
 		ContentLength: int64(len(body)),
 		Close:         true,
@@ -62,6 +62,7 @@ func WriteProxyError(w io.Writer, code int, msg string) {
 	resp.Header.Set("Content-Length", fmt.Sprintf("%d", len(body)))
 	_ = resp.Write(w)
 	_ = resp.Body.Close()
+	PutHeader(resp.Header)
 }
 
 // IsWebSocketUpgrade checks if the request is a WebSocket upgrade request.
@@ -69,3 +70,11 @@ func IsWebSocketUpgrade(req *http.Request) bool {
 	return strings.EqualFold(req.Header.Get("Upgrade"), "websocket") &&
 		strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade")
 }
+
+// IsConnectionUpgrade checks if the request asks to switch protocols, for
+// any Upgrade value (websocket, spdy, tcp, ...). Such requests must be
+// proxied as a bidirectional stream rather than a buffered exchange.
+func IsConnectionUpgrade(req *http.Request) bool {
+	return req.Header.Get("Upgrade") != "" &&
+		strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade")
+}
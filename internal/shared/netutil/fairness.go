@@ -0,0 +1,180 @@
+package netutil
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// fairTickInterval is how often the scheduler releases outbound budget.
+const fairTickInterval = 20 * time.Millisecond
+
+// FairScheduler divides a global outbound byte budget across tunnels in
+// round-robin order, so a single bulk transfer cannot starve everyone
+// else's interactive traffic when the server's uplink saturates. Tunnels
+// with nothing to send consume no budget.
+type FairScheduler struct {
+	bytesPerSec int64
+
+	mu     sync.Mutex
+	queues map[string][]*fairWaiter
+	order  []string // round-robin order of tunnels with pending writes
+}
+
+type fairWaiter struct {
+	n       int
+	granted chan struct{}
+}
+
+var (
+	fairMu        sync.RWMutex
+	fairScheduler *FairScheduler
+)
+
+// EnableFairScheduler installs a process-wide scheduler enforcing the given
+// outbound budget in bytes per second. Zero or negative leaves scheduling
+// disabled; FairConn and FairWriter then pass writes through untouched.
+func EnableFairScheduler(bytesPerSec int64) {
+	if bytesPerSec <= 0 {
+		return
+	}
+
+	s := &FairScheduler{
+		bytesPerSec: bytesPerSec,
+		queues:      make(map[string][]*fairWaiter),
+	}
+	go s.run()
+
+	fairMu.Lock()
+	fairScheduler = s
+	fairMu.Unlock()
+}
+
+func currentFairScheduler() *FairScheduler {
+	fairMu.RLock()
+	defer fairMu.RUnlock()
+	return fairScheduler
+}
+
+// FairConn wraps conn so its writes contend for the shared outbound budget
+// under the given tunnel key. Returns conn unchanged when no scheduler is
+// enabled.
+func FairConn(key string, conn net.Conn) net.Conn {
+	s := currentFairScheduler()
+	if s == nil {
+		return conn
+	}
+	return &fairConn{Conn: conn, key: key, sched: s}
+}
+
+// FairWriter wraps w so its writes contend for the shared outbound budget
+// under the given tunnel key. Returns w unchanged when no scheduler is
+// enabled.
+func FairWriter(key string, w io.Writer) io.Writer {
+	s := currentFairScheduler()
+	if s == nil {
+		return w
+	}
+	return &fairWriter{w: w, key: key, sched: s}
+}
+
+// reserve blocks until the scheduler grants a write of n bytes to key.
+func (s *FairScheduler) reserve(key string, n int) {
+	w := &fairWaiter{n: n, granted: make(chan struct{})}
+
+	s.mu.Lock()
+	if _, ok := s.queues[key]; !ok {
+		s.order = append(s.order, key)
+	}
+	s.queues[key] = append(s.queues[key], w)
+	s.mu.Unlock()
+
+	<-w.granted
+}
+
+func (s *FairScheduler) run() {
+	ticker := time.NewTicker(fairTickInterval)
+	defer ticker.Stop()
+
+	tickBudget := s.bytesPerSec * int64(fairTickInterval) / int64(time.Second)
+	if tickBudget < 1 {
+		tickBudget = 1
+	}
+
+	for range ticker.C {
+		s.grant(tickBudget)
+	}
+}
+
+// grant releases up to budget bytes of pending writes, taking one write per
+// tunnel per pass so short interactive writes interleave with bulk streams.
+func (s *FairScheduler) grant(budget int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for budget > 0 && len(s.order) > 0 {
+		remaining := make([]string, 0, len(s.order))
+		for _, key := range s.order {
+			q := s.queues[key]
+			if len(q) == 0 {
+				delete(s.queues, key)
+				continue
+			}
+			if budget <= 0 {
+				remaining = append(remaining, key)
+				continue
+			}
+
+			w := q[0]
+			close(w.granted)
+			budget -= int64(w.n)
+
+			if len(q) == 1 {
+				delete(s.queues, key)
+			} else {
+				s.queues[key] = q[1:]
+				remaining = append(remaining, key)
+			}
+		}
+		s.order = remaining
+	}
+}
+
+type fairConn struct {
+	net.Conn
+	key   string
+	sched *FairScheduler
+}
+
+func (c *fairConn) Write(p []byte) (int, error) {
+	c.sched.reserve(c.key, len(p))
+	return c.Conn.Write(p)
+}
+
+// CloseRead and CloseWrite forward TCP half-close to the wrapped connection
+// so Pipe keeps its half-close behavior through the wrapper.
+func (c *fairConn) CloseRead() error {
+	if cr, ok := c.Conn.(closeReader); ok {
+		return cr.CloseRead()
+	}
+	return c.Conn.Close()
+}
+
+func (c *fairConn) CloseWrite() error {
+	if cw, ok := c.Conn.(closeWriter); ok {
+		return cw.CloseWrite()
+	}
+	return c.Conn.Close()
+}
+
+type fairWriter struct {
+	w     io.Writer
+	key   string
+	sched *FairScheduler
+}
+
+func (f *fairWriter) Write(p []byte) (int, error) {
+	f.sched.reserve(f.key, len(p))
+	return f.w.Write(p)
+}
@@ -0,0 +1,157 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/quic"
+)
+
+// QUICControlALPN is the application protocol negotiated on QUIC control
+// channels. QUIC mandates ALPN, so the client transport and the server's
+// QUIC listener must both advertise it.
+const QUICControlALPN = "drip-control"
+
+// QUICStreamConn adapts one bidirectional QUIC stream to net.Conn so the
+// frame codec and yamux run over it unchanged. Deadlines map onto the
+// stream's read/write contexts with one caveat: installing a new deadline
+// must come from the goroutine doing the I/O (as the control paths do),
+// while a deadline in the past only cancels the installed context, which
+// is safe from any goroutine and unblocks a pending Read or Write.
+type QUICStreamConn struct {
+	endpoint *quic.Endpoint // closed with the conn when owned; nil on the server side
+	conn     *quic.Conn
+	stream   *quic.Stream
+
+	mu          sync.Mutex
+	readCancel  context.CancelFunc
+	writeCancel context.CancelFunc
+}
+
+// NewQUICStreamConn wraps a QUIC stream as a net.Conn. When endpoint is
+// non-nil (the dialing side owns a dedicated UDP socket) it is closed
+// together with the connection.
+func NewQUICStreamConn(endpoint *quic.Endpoint, conn *quic.Conn, stream *quic.Stream) *QUICStreamConn {
+	c := &QUICStreamConn{
+		endpoint: endpoint,
+		conn:     conn,
+		stream:   stream,
+	}
+	// Install cancellable contexts up front so a past deadline can always
+	// unblock pending I/O, mirroring SetDeadline(now) on a TCP conn.
+	c.armRead(time.Time{})
+	c.armWrite(time.Time{})
+	return c
+}
+
+func (c *QUICStreamConn) Read(b []byte) (int, error) {
+	return c.stream.Read(b)
+}
+
+// Write flushes after every write: the stream buffers internally, and
+// control frames must reach the wire when written, not when the buffer
+// fills.
+func (c *QUICStreamConn) Write(b []byte) (int, error) {
+	n, err := c.stream.Write(b)
+	if err != nil {
+		return n, err
+	}
+	return n, c.stream.Flush()
+}
+
+// Close aborts the QUIC connection without waiting for the peer, matching
+// the immediate-close semantics the control paths expect from a TCP conn.
+func (c *QUICStreamConn) Close() error {
+	c.mu.Lock()
+	if c.readCancel != nil {
+		c.readCancel()
+	}
+	if c.writeCancel != nil {
+		c.writeCancel()
+	}
+	c.mu.Unlock()
+
+	c.conn.Abort(nil)
+
+	if c.endpoint != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = c.endpoint.Close(ctx)
+	}
+	return nil
+}
+
+func (c *QUICStreamConn) LocalAddr() net.Addr {
+	return net.UDPAddrFromAddrPort(c.conn.LocalAddr())
+}
+
+func (c *QUICStreamConn) RemoteAddr() net.Addr {
+	return net.UDPAddrFromAddrPort(c.conn.RemoteAddr())
+}
+
+func (c *QUICStreamConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+func (c *QUICStreamConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.readCancel != nil {
+		c.readCancel()
+		c.readCancel = nil
+	}
+	if pastDeadline(t) {
+		// Cancel-only: replacing the stream's context would race a
+		// concurrent Read, and cancelling already unblocked it.
+		return nil
+	}
+	c.armRead(t)
+	return nil
+}
+
+func (c *QUICStreamConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.writeCancel != nil {
+		c.writeCancel()
+		c.writeCancel = nil
+	}
+	if pastDeadline(t) {
+		return nil
+	}
+	c.armWrite(t)
+	return nil
+}
+
+// armRead installs a fresh cancellable read context, bounded by t unless
+// t is zero. Callers hold c.mu except during construction.
+func (c *QUICStreamConn) armRead(t time.Time) {
+	ctx := context.Background()
+	if !t.IsZero() {
+		ctx, c.readCancel = context.WithDeadline(ctx, t)
+	} else {
+		ctx, c.readCancel = context.WithCancel(ctx)
+	}
+	c.stream.SetReadContext(ctx)
+}
+
+func (c *QUICStreamConn) armWrite(t time.Time) {
+	ctx := context.Background()
+	if !t.IsZero() {
+		ctx, c.writeCancel = context.WithDeadline(ctx, t)
+	} else {
+		ctx, c.writeCancel = context.WithCancel(ctx)
+	}
+	c.stream.SetWriteContext(ctx)
+}
+
+func pastDeadline(t time.Time) bool {
+	return !t.IsZero() && !t.After(time.Now())
+}
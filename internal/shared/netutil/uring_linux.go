@@ -0,0 +1,295 @@
+//go:build linux && drip_iouring
+
+package netutil
+
+// Experimental io_uring-backed copy loop, enabled with -tags drip_iouring.
+// The ring is set up per copy with a small queue and a single reused buffer;
+// reads and writes are submitted as linked-free alternating ops, trading the
+// per-iteration syscall pair of a classic copy loop for one io_uring_enter
+// per operation with kernel-side polling (IORING_FEAT_FAST_POLL) absorbing
+// socket readiness.
+//
+// The server's proxied streams currently terminate in yamux (userspace), so
+// this path only engages for fd-to-fd copies; see the benchmarks in
+// copy_bench_test.go for the comparison against splice and io.Copy.
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	sysIoUringSetup = 425
+	sysIoUringEnter = 426
+
+	ioringOpRead  = 22
+	ioringOpWrite = 23
+
+	ioringEnterGetevents = 1
+
+	ioringOffSqRing = 0
+	ioringOffCqRing = 0x8000000
+	ioringOffSqes   = 0x10000000
+
+	uringQueueDepth = 8
+	uringCopyBuf    = 64 * 1024
+)
+
+type ioSqringOffsets struct {
+	head        uint32
+	tail        uint32
+	ringMask    uint32
+	ringEntries uint32
+	flags       uint32
+	dropped     uint32
+	array       uint32
+	resv1       uint32
+	resv2       uint64
+}
+
+type ioCqringOffsets struct {
+	head        uint32
+	tail        uint32
+	ringMask    uint32
+	ringEntries uint32
+	overflow    uint32
+	cqes        uint32
+	flags       uint32
+	resv1       uint32
+	resv2       uint64
+}
+
+type ioUringParams struct {
+	sqEntries    uint32
+	cqEntries    uint32
+	flags        uint32
+	sqThreadCPU  uint32
+	sqThreadIdle uint32
+	features     uint32
+	wqFd         uint32
+	resv         [3]uint32
+	sqOff        ioSqringOffsets
+	cqOff        ioCqringOffsets
+}
+
+type ioUringSqe struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64
+	addr        uint64
+	len         uint32
+	opcodeFlags uint32
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFdIn  int32
+	pad         [2]uint64
+}
+
+type ioUringCqe struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+// uring is a minimal single-issuer io_uring instance.
+type uring struct {
+	fd int
+
+	sqRing []byte
+	cqRing []byte
+	sqes   []byte
+
+	sqHead    *uint32
+	sqTail    *uint32
+	sqMask    *uint32
+	sqArray   *uint32
+	cqHead    *uint32
+	cqTail    *uint32
+	cqMask    *uint32
+	cqCqes    unsafe.Pointer
+	sqEntries []ioUringSqe
+}
+
+var (
+	uringProbeOnce sync.Once
+	uringSupported bool
+)
+
+// URingAvailable reports whether the running kernel accepts io_uring setup.
+func URingAvailable() bool {
+	uringProbeOnce.Do(func() {
+		r, err := newURing(uringQueueDepth)
+		if err == nil {
+			uringSupported = true
+			r.close()
+		}
+	})
+	return uringSupported
+}
+
+func newURing(entries uint32) (*uring, error) {
+	var params ioUringParams
+	fd, _, errno := syscall.Syscall(sysIoUringSetup, uintptr(entries), uintptr(unsafe.Pointer(&params)), 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("io_uring_setup: %w", errno)
+	}
+
+	r := &uring{fd: int(fd)}
+
+	sqSize := int(params.sqOff.array) + int(params.sqEntries)*4
+	cqSize := int(params.cqOff.cqes) + int(params.cqEntries)*int(unsafe.Sizeof(ioUringCqe{}))
+
+	var err error
+	r.sqRing, err = syscall.Mmap(r.fd, ioringOffSqRing, sqSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE)
+	if err != nil {
+		r.close()
+		return nil, fmt.Errorf("mmap sq ring: %w", err)
+	}
+	r.cqRing, err = syscall.Mmap(r.fd, ioringOffCqRing, cqSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE)
+	if err != nil {
+		r.close()
+		return nil, fmt.Errorf("mmap cq ring: %w", err)
+	}
+	r.sqes, err = syscall.Mmap(r.fd, ioringOffSqes, int(params.sqEntries)*int(unsafe.Sizeof(ioUringSqe{})), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE)
+	if err != nil {
+		r.close()
+		return nil, fmt.Errorf("mmap sqes: %w", err)
+	}
+
+	base := unsafe.Pointer(&r.sqRing[0])
+	r.sqHead = (*uint32)(unsafe.Add(base, params.sqOff.head))
+	r.sqTail = (*uint32)(unsafe.Add(base, params.sqOff.tail))
+	r.sqMask = (*uint32)(unsafe.Add(base, params.sqOff.ringMask))
+	r.sqArray = (*uint32)(unsafe.Add(base, params.sqOff.array))
+
+	cqBase := unsafe.Pointer(&r.cqRing[0])
+	r.cqHead = (*uint32)(unsafe.Add(cqBase, params.cqOff.head))
+	r.cqTail = (*uint32)(unsafe.Add(cqBase, params.cqOff.tail))
+	r.cqMask = (*uint32)(unsafe.Add(cqBase, params.cqOff.ringMask))
+	r.cqCqes = unsafe.Add(cqBase, params.cqOff.cqes)
+
+	r.sqEntries = unsafe.Slice((*ioUringSqe)(unsafe.Pointer(&r.sqes[0])), params.sqEntries)
+
+	return r, nil
+}
+
+func (r *uring) close() {
+	if r.sqes != nil {
+		_ = syscall.Munmap(r.sqes)
+	}
+	if r.cqRing != nil {
+		_ = syscall.Munmap(r.cqRing)
+	}
+	if r.sqRing != nil {
+		_ = syscall.Munmap(r.sqRing)
+	}
+	_ = syscall.Close(r.fd)
+}
+
+// submitAndWait queues one op and blocks for its completion result.
+func (r *uring) submitAndWait(opcode uint8, fd int32, buf []byte, n uint32) (int32, error) {
+	tail := atomic.LoadUint32(r.sqTail)
+	idx := tail & *r.sqMask
+
+	sqe := &r.sqEntries[idx]
+	*sqe = ioUringSqe{
+		opcode: opcode,
+		fd:     fd,
+		addr:   uint64(uintptr(unsafe.Pointer(&buf[0]))),
+		len:    n,
+		off:    ^uint64(0), // -1: use the file offset (ignored for sockets)
+	}
+
+	array := unsafe.Slice(r.sqArray, *r.sqMask+1)
+	array[idx] = idx
+	atomic.StoreUint32(r.sqTail, tail+1)
+
+	_, _, errno := syscall.Syscall6(sysIoUringEnter, uintptr(r.fd), 1, 1, ioringEnterGetevents, 0, 0)
+	if errno != 0 {
+		return 0, fmt.Errorf("io_uring_enter: %w", errno)
+	}
+
+	head := atomic.LoadUint32(r.cqHead)
+	cqe := (*ioUringCqe)(unsafe.Add(r.cqCqes, uintptr(head&*r.cqMask)*unsafe.Sizeof(ioUringCqe{})))
+	res := cqe.res
+	atomic.StoreUint32(r.cqHead, head+1)
+
+	if res < 0 {
+		return 0, syscall.Errno(-res)
+	}
+	return res, nil
+}
+
+// URingCopy copies src to dst until EOF using an io_uring. Both connections
+// must expose raw file descriptors (syscall.Conn).
+func URingCopy(dst, src syscall.Conn) (int64, error) {
+	return URingCopyN(dst, src, -1)
+}
+
+// URingCopyN copies up to limit bytes from src to dst using an io_uring; a
+// negative limit copies until EOF.
+func URingCopyN(dst, src syscall.Conn, limit int64) (int64, error) {
+	srcFd, err := rawFd(src)
+	if err != nil {
+		return 0, err
+	}
+	dstFd, err := rawFd(dst)
+	if err != nil {
+		return 0, err
+	}
+
+	ring, err := newURing(uringQueueDepth)
+	if err != nil {
+		return 0, err
+	}
+	defer ring.close()
+
+	buf := make([]byte, uringCopyBuf)
+	var written int64
+
+	for limit < 0 || written < limit {
+		want := uint32(len(buf))
+		if limit >= 0 && limit-written < int64(want) {
+			want = uint32(limit - written)
+		}
+
+		n, err := ring.submitAndWait(ioringOpRead, srcFd, buf, want)
+		if err != nil {
+			return written, err
+		}
+		if n == 0 {
+			return written, nil
+		}
+
+		off := int32(0)
+		for off < n {
+			w, err := ring.submitAndWait(ioringOpWrite, dstFd, buf[off:n], uint32(n-off))
+			if err != nil {
+				return written, err
+			}
+			off += w
+			written += int64(w)
+		}
+	}
+	return written, nil
+}
+
+// rawFd extracts the file descriptor behind a syscall.Conn. The fd stays
+// owned by the runtime; it is only borrowed for the duration of the copy.
+func rawFd(c syscall.Conn) (int32, error) {
+	rc, err := c.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var fd int32
+	if err := rc.Control(func(f uintptr) { fd = int32(f) }); err != nil {
+		return 0, err
+	}
+	return fd, nil
+}
@@ -0,0 +1,25 @@
+//go:build !linux || !drip_iouring
+
+package netutil
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// io_uring support is Linux-only and opt-in via -tags drip_iouring.
+
+// URingAvailable reports whether the io_uring copy path can be used.
+func URingAvailable() bool {
+	return false
+}
+
+// URingCopy is unavailable without the drip_iouring build tag.
+func URingCopy(dst, src syscall.Conn) (int64, error) {
+	return 0, fmt.Errorf("io_uring support not built in (rebuild with -tags drip_iouring)")
+}
+
+// URingCopyN is unavailable without the drip_iouring build tag.
+func URingCopyN(dst, src syscall.Conn, limit int64) (int64, error) {
+	return URingCopy(dst, src)
+}
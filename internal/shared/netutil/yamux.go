@@ -0,0 +1,22 @@
+package netutil
+
+import (
+	"io"
+
+	"drip/internal/shared/constants"
+
+	"github.com/hashicorp/yamux"
+)
+
+// YamuxConfig returns the multiplexer configuration both sides of a
+// control connection use. Keepalive stays off because the protocol has
+// its own ping frames; each stream gets an independent flow-controlled
+// window so slow and fast requests don't block each other.
+func YamuxConfig() *yamux.Config {
+	cfg := yamux.DefaultConfig()
+	cfg.EnableKeepAlive = false
+	cfg.LogOutput = io.Discard
+	cfg.AcceptBacklog = constants.YamuxAcceptBacklog
+	cfg.MaxStreamWindowSize = constants.YamuxStreamWindow
+	return cfg
+}
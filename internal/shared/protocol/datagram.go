@@ -0,0 +1,47 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MaxDatagramSize is the largest UDP payload the tunnel relays; it matches
+// the maximum length expressible in the 2-byte frame prefix.
+const MaxDatagramSize = 65535
+
+// WriteDatagram frames one datagram onto a reliable stream as a 2-byte
+// big-endian length followed by the payload. Callers must serialize writes
+// to the same stream.
+func WriteDatagram(w io.Writer, p []byte) error {
+	if len(p) > MaxDatagramSize {
+		return fmt.Errorf("datagram too large: %d bytes", len(p))
+	}
+
+	var hdr [2]byte
+	binary.BigEndian.PutUint16(hdr[:], uint16(len(p)))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(p)
+	return err
+}
+
+// ReadDatagram reads one framed datagram into buf and returns its length.
+// buf should hold MaxDatagramSize bytes; a datagram larger than buf is an
+// error.
+func ReadDatagram(r io.Reader, buf []byte) (int, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, err
+	}
+
+	n := int(binary.BigEndian.Uint16(hdr[:]))
+	if n > len(buf) {
+		return 0, fmt.Errorf("datagram larger than buffer: %d bytes", n)
+	}
+	if _, err := io.ReadFull(r, buf[:n]); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
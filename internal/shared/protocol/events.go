@@ -0,0 +1,21 @@
+package protocol
+
+import "time"
+
+// Event types pushed to subscribed clients over the control channel.
+const (
+	EventTunnelRegistered = "tunnel_registered"
+	EventTunnelClosed     = "tunnel_closed"
+	EventQuotaWarning     = "quota_warning"
+	EventAdminNotice      = "admin_notice"
+	EventAnomaly          = "anomaly"
+)
+
+// Event is one server-side event delivered to clients that opened an event
+// subscription stream (FrameTypeEventSub) on their control session.
+type Event struct {
+	Type      string    `json:"type"`
+	Subdomain string    `json:"subdomain,omitempty"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
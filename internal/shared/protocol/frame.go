@@ -12,6 +12,12 @@ import (
 const (
 	FrameHeaderSize = 5
 	MaxFrameSize    = 10 * 1024 * 1024
+
+	// MaxControlPayload caps the payload of JSON control messages (register,
+	// data connect, forward). Data frames may be large; control messages are
+	// a few hundred bytes, so parsers reject anything bigger before decoding
+	// rather than letting an unauthenticated peer force a 10 MB JSON parse.
+	MaxControlPayload = 64 * 1024
 )
 
 // FrameType defines the type of frame
@@ -26,6 +32,11 @@ const (
 	FrameTypeError          FrameType = 0x06
 	FrameTypeDataConnect    FrameType = 0x07
 	FrameTypeDataConnectAck FrameType = 0x08
+	FrameTypeEventSub       FrameType = 0x09
+	FrameTypeEvent          FrameType = 0x0A
+	FrameTypeForward        FrameType = 0x0B
+	FrameTypeForwardAck     FrameType = 0x0C
+	FrameTypePowChallenge   FrameType = 0x0D
 )
 
 // String returns the string representation of frame type
@@ -47,6 +58,16 @@ func (t FrameType) String() string {
 		return "DataConnect"
 	case FrameTypeDataConnectAck:
 		return "DataConnectAck"
+	case FrameTypeEventSub:
+		return "EventSub"
+	case FrameTypeEvent:
+		return "Event"
+	case FrameTypeForward:
+		return "Forward"
+	case FrameTypeForwardAck:
+		return "ForwardAck"
+	case FrameTypePowChallenge:
+		return "PowChallenge"
 	default:
 		return fmt.Sprintf("Unknown(%d)", t)
 	}
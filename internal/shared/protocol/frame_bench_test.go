@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// Allocation budgets for the frame codec hot path. These are deliberately
+// loose ceilings, not targets: they exist so a redesign that starts
+// allocating per frame fails the suite instead of slipping through review.
+// Compare the benchmarks below with benchstat when tuning.
+const (
+	writeFrameAllocBudget = 3
+	readFrameAllocBudget  = 6
+)
+
+func benchFramePayload() []byte {
+	payload := make([]byte, 4096)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	return payload
+}
+
+func BenchmarkWriteFrame(b *testing.B) {
+	frame := NewFrame(FrameTypeDataConnect, benchFramePayload())
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(frame.Payload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := WriteFrame(io.Discard, frame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadFrame(b *testing.B) {
+	var buf bytes.Buffer
+	if err := WriteFrame(&buf, NewFrame(FrameTypeDataConnect, benchFramePayload())); err != nil {
+		b.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	reader := bytes.NewReader(encoded)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(encoded)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader.Reset(encoded)
+		frame, err := ReadFrame(reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		frame.Release()
+	}
+}
+
+func TestWriteFrameAllocBudget(t *testing.T) {
+	frame := NewFrame(FrameTypeDataConnect, benchFramePayload())
+
+	allocs := testing.AllocsPerRun(200, func() {
+		if err := WriteFrame(io.Discard, frame); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > writeFrameAllocBudget {
+		t.Errorf("WriteFrame allocates %.1f objects per frame, budget is %d",
+			allocs, writeFrameAllocBudget)
+	}
+}
+
+func TestReadFrameAllocBudget(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFrame(&buf, NewFrame(FrameTypeDataConnect, benchFramePayload())); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	reader := bytes.NewReader(encoded)
+
+	allocs := testing.AllocsPerRun(200, func() {
+		reader.Reset(encoded)
+		frame, err := ReadFrame(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frame.Release()
+	})
+	if allocs > readFrameAllocBudget {
+		t.Errorf("ReadFrame allocates %.1f objects per frame, budget is %d",
+			allocs, readFrameAllocBudget)
+	}
+}
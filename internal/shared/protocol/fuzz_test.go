@@ -0,0 +1,87 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Fuzz targets for the parsers fed untrusted bytes straight off the public
+// listener: the frame reader and the control plane JSON messages. Run with
+// e.g. go test -fuzz=FuzzReadFrame ./internal/shared/protocol.
+
+func FuzzReadFrame(f *testing.F) {
+	encode := func(t FrameType, payload []byte) []byte {
+		var buf bytes.Buffer
+		if err := WriteFrame(&buf, NewFrame(t, payload)); err != nil {
+			f.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	f.Add(encode(FrameTypeHeartbeat, nil))
+	f.Add(encode(FrameTypeRegister, []byte(`{"token":"t","tunnel_type":"http","local_port":3000}`)))
+	f.Add(encode(FrameTypeForward, bytes.Repeat([]byte{0xAB}, 1024)))
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x01})             // length over MaxFrameSize
+	f.Add([]byte{0x00, 0x00, 0x00, 0x08, 0x0B, 0x01, 0x02}) // truncated payload
+	f.Add([]byte{0x00})                                     // truncated header
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		frame, err := ReadFrame(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		if len(frame.Payload) > MaxFrameSize {
+			t.Fatalf("accepted oversized payload: %d bytes", len(frame.Payload))
+		}
+
+		// Anything accepted must survive a write/read round trip unchanged.
+		var buf bytes.Buffer
+		if err := WriteFrame(&buf, frame); err != nil {
+			t.Fatalf("failed to re-encode accepted frame: %v", err)
+		}
+		again, err := ReadFrame(&buf)
+		if err != nil {
+			t.Fatalf("failed to re-read encoded frame: %v", err)
+		}
+		if again.Type != frame.Type || !bytes.Equal(again.Payload, frame.Payload) {
+			t.Fatalf("round trip changed frame: type %s -> %s, payload %d -> %d bytes",
+				frame.Type, again.Type, len(frame.Payload), len(again.Payload))
+		}
+		again.Release()
+		frame.Release()
+	})
+}
+
+func FuzzUnmarshalRegisterRequest(f *testing.F) {
+	if seed, err := MarshalJSON(benchRegisterRequest()); err == nil {
+		f.Add(seed)
+	}
+	f.Add([]byte(`{"token":"t","tunnel_type":"tcp","local_port":5432}`))
+	f.Add([]byte(`{"token":1}`))
+	f.Add([]byte(`{"extra_tunnels":[{"subdomain":"a","local_port":-1}]}`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`{`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var req RegisterRequest
+		if err := UnmarshalJSON(data, &req); err != nil {
+			return
+		}
+
+		// Accepted requests must re-encode and decode back to the same
+		// routing-relevant fields.
+		out, err := MarshalJSON(&req)
+		if err != nil {
+			t.Fatalf("failed to re-encode accepted request: %v", err)
+		}
+		var again RegisterRequest
+		if err := UnmarshalJSON(out, &again); err != nil {
+			t.Fatalf("failed to re-decode encoded request: %v", err)
+		}
+		if again.Token != req.Token || again.CustomSubdomain != req.CustomSubdomain ||
+			again.TunnelType != req.TunnelType || again.LocalPort != req.LocalPort {
+			t.Fatalf("round trip changed request: %+v -> %+v", req, again)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package protocol
+
+import "testing"
+
+// Benchmarks for the control plane JSON codec. Run with -tags drip_stdjson
+// to compare the standard library fallback against the default codec.
+
+func benchRegisterRequest() *RegisterRequest {
+	return &RegisterRequest{
+		Token:           "bench-token",
+		CustomSubdomain: "benchapp",
+		TunnelType:      TunnelTypeHTTP,
+		LocalPort:       3000,
+		InstanceID:      "bench-instance",
+		Services:        []string{"grpc.health.v1.Health"},
+		Redirects: []RedirectRule{
+			{From: "/old/*", To: "/new/"},
+		},
+		ExtraTunnels: []ExtraTunnel{
+			{Subdomain: "benchapi", LocalPort: 4000},
+		},
+	}
+}
+
+func BenchmarkMarshalRegisterRequest(b *testing.B) {
+	req := benchRegisterRequest()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MarshalJSON(req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalRegisterRequest(b *testing.B) {
+	data, err := MarshalJSON(benchRegisterRequest())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var req RegisterRequest
+		if err := UnmarshalJSON(data, &req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
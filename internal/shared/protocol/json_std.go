@@ -0,0 +1,43 @@
+//go:build drip_stdjson
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// Standard library JSON codec, selected with -tags drip_stdjson. Slower than
+// the default goccy codec but useful as a known-good reference when chasing
+// a suspected codec bug.
+
+var encodeBufPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// MarshalJSON encodes a control plane message through a pooled buffer.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	buf := encodeBufPool.Get().(*bytes.Buffer)
+	defer encodeBufPool.Put(buf)
+	buf.Reset()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode appends a trailing newline; frames carry the bare document.
+	b := buf.Bytes()
+	if n := len(b); n > 0 && b[n-1] == '\n' {
+		b = b[:n-1]
+	}
+
+	out := make([]byte, len(b))
+	copy(out, b)
+	return out, nil
+}
+
+// UnmarshalJSON decodes a control plane message.
+func UnmarshalJSON(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
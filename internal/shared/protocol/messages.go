@@ -1,7 +1,5 @@
 package protocol
 
-import json "github.com/goccy/go-json"
-
 // PoolCapabilities advertises client connection pool capabilities
 type PoolCapabilities struct {
 	MaxDataConns int `json:"max_data_conns"` // Maximum data connections client supports
@@ -14,6 +12,23 @@ type RegisterRequest struct {
 	CustomSubdomain string     `json:"custom_subdomain"` // Optional custom subdomain
 	TunnelType      TunnelType `json:"tunnel_type"`      // http, tcp, udp
 	LocalPort       int        `json:"local_port"`       // Local port to forward to
+	RemotePort      int        `json:"remote_port,omitempty"` // Requested fixed public port (TCP/UDP)
+	InstanceID      string     `json:"instance_id,omitempty"` // Client instance ID for idempotent retries
+	Services        []string   `json:"services,omitempty"`    // Discovered service names (e.g. gRPC reflection)
+	Alias           string     `json:"alias,omitempty"`       // Stable alias resolving to this tunnel's subdomain
+	CustomDomain    string     `json:"custom_domain,omitempty"` // CNAMEd hostname routed to this tunnel (HTTP/HTTPS only)
+	NameStyle       string     `json:"name_style,omitempty"`  // Generated-name style ("words"); ignored with a custom subdomain
+	SocksUser       string     `json:"socks_user,omitempty"`  // SOCKS5 username required by the public endpoint
+	SocksPass       string     `json:"socks_pass,omitempty"`  // SOCKS5 password required by the public endpoint
+
+	// PowChallenge/PowNonce echo back a solved proof-of-work challenge when
+	// an anonymous server requires one (see FrameTypePowChallenge).
+	PowChallenge string `json:"pow_challenge,omitempty"`
+	PowNonce     string `json:"pow_nonce,omitempty"`
+
+	Redirects          []RedirectRule `json:"redirects,omitempty"`            // Server-side redirect rules
+	ForceTrailingSlash bool           `json:"force_trailing_slash,omitempty"` // Redirect extensionless paths to path + "/"
+	ExtraTunnels       []ExtraTunnel  `json:"extra_tunnels,omitempty"`        // Additional subdomains over this connection
 
 	// Connection pool fields (optional, for multi-connection support)
 	ConnectionType   string            `json:"connection_type,omitempty"`   // "primary" or empty for legacy
@@ -21,6 +36,22 @@ type RegisterRequest struct {
 	PoolCapabilities *PoolCapabilities `json:"pool_capabilities,omitempty"` // Client pool capabilities
 }
 
+// ExtraTunnel is an additional tunnel registered over the same control
+// connection. HTTP/HTTPS extras are routed by subdomain; TCP extras get
+// their own public port and the subdomain may be left empty.
+type ExtraTunnel struct {
+	Subdomain string `json:"subdomain"`  // Custom subdomain (required for HTTP/HTTPS)
+	LocalPort int    `json:"local_port"` // Local port this tunnel forwards to
+}
+
+// RedirectRule is one server-side redirect evaluated before a request is
+// forwarded to the tunnel. From ending in "/*" matches the path prefix and
+// carries the remainder over to To.
+type RedirectRule struct {
+	From string `json:"from"` // Request path, or prefix when ending in /*
+	To   string `json:"to"`   // Redirect target path
+}
+
 // RegisterResponse is sent by server after successful registration
 type RegisterResponse struct {
 	Subdomain string `json:"subdomain"`        // Assigned subdomain
@@ -28,6 +59,8 @@ type RegisterResponse struct {
 	URL       string `json:"url"`              // Full tunnel URL
 	Message   string `json:"message"`          // Success message
 
+	ExtraURLs []string `json:"extra_urls,omitempty"` // URLs of extra tunnels, in request order
+
 	// Connection pool fields (optional, for multi-connection support)
 	TunnelID         string `json:"tunnel_id,omitempty"`          // Unique tunnel identifier
 	SupportsDataConn bool   `json:"supports_data_conn,omitempty"` // Server supports multi-connection
@@ -48,17 +81,23 @@ type DataConnectResponse struct {
 	Message      string `json:"message,omitempty"`     // Optional message
 }
 
-// ErrorMessage represents an error
-type ErrorMessage struct {
-	Code    string `json:"code"`    // Error code
-	Message string `json:"message"` // Error message
+// ForwardRequest asks the server to pipe this connection into an existing
+// tunnel (reverse/local-forward mode), so teammates can reach a tunneled
+// service without a public TCP port
+type ForwardRequest struct {
+	Token     string `json:"token"`     // Authentication token
+	Subdomain string `json:"subdomain"` // Tunnel (or alias) to attach to
 }
 
-// Marshal helpers for control plane messages (JSON encoding)
-func MarshalJSON(v interface{}) ([]byte, error) {
-	return json.Marshal(v)
+// ForwardResponse acknowledges a forward request
+type ForwardResponse struct {
+	Accepted   bool   `json:"accepted"`              // Whether the forward was accepted
+	TunnelType string `json:"tunnel_type,omitempty"` // Type of the target tunnel
+	Message    string `json:"message,omitempty"`     // Optional message
 }
 
-func UnmarshalJSON(data []byte, v interface{}) error {
-	return json.Unmarshal(data, v)
+// ErrorMessage represents an error
+type ErrorMessage struct {
+	Code    string `json:"code"`    // Error code
+	Message string `json:"message"` // Error message
 }
@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"crypto/sha256"
+	"math/bits"
+	"strconv"
+)
+
+// PowChallenge is sent by a server running without token auth when it
+// requires registrations to carry a proof-of-work solution. The client
+// retries registration with the solved challenge echoed back.
+type PowChallenge struct {
+	Challenge  string `json:"challenge"`  // Server-issued single-use value
+	Difficulty int    `json:"difficulty"` // Required leading zero bits
+}
+
+// CheckPow reports whether sha256(challenge + ":" + nonce) has at least
+// difficulty leading zero bits.
+func CheckPow(challenge, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	return leadingZeroBits(sum[:]) >= difficulty
+}
+
+// SolvePow searches nonces until CheckPow passes. Around 20 bits of
+// difficulty costs a modern core roughly a second; each extra bit doubles
+// that.
+func SolvePow(challenge string, difficulty int) string {
+	for i := uint64(0); ; i++ {
+		nonce := strconv.FormatUint(i, 16)
+		if CheckPow(challenge, nonce, difficulty) {
+			return nonce
+		}
+	}
+}
+
+func leadingZeroBits(sum []byte) int {
+	n := 0
+	for _, b := range sum {
+		n += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+	return n
+}
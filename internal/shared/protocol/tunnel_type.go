@@ -12,6 +12,8 @@ const (
 	TunnelTypeTCP TunnelType = "tcp"
 	// TunnelTypeUDP is for UDP traffic (future support)
 	TunnelTypeUDP TunnelType = "udp"
+	// TunnelTypeSOCKS exposes a SOCKS5 proxy into the client's network
+	TunnelTypeSOCKS TunnelType = "socks"
 )
 
 // String returns the string representation
@@ -22,7 +24,7 @@ func (t TunnelType) String() string {
 // IsValid checks if tunnel type is valid
 func (t TunnelType) IsValid() bool {
 	switch t {
-	case TunnelTypeHTTP, TunnelTypeHTTPS, TunnelTypeTCP, TunnelTypeUDP:
+	case TunnelTypeHTTP, TunnelTypeHTTPS, TunnelTypeTCP, TunnelTypeUDP, TunnelTypeSOCKS:
 		return true
 	default:
 		return false
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"drip/internal/shared/clock"
 )
 
 type FrameWriter struct {
@@ -40,6 +42,8 @@ type FrameWriter struct {
 	// Backlog tracking
 	queuedFrames atomic.Int64
 	queuedBytes  atomic.Int64
+
+	clock clock.Clock // batching and heartbeat timers; tests inject a fake
 }
 
 func NewFrameWriter(conn io.Writer) *FrameWriter {
@@ -49,7 +53,26 @@ func NewFrameWriter(conn io.Writer) *FrameWriter {
 }
 
 func NewFrameWriterWithConfig(conn io.Writer, maxBatch int, maxBatchWait time.Duration, queueSize int) *FrameWriter {
-	w := &FrameWriter{
+	w := newFrameWriter(conn, maxBatch, maxBatchWait, queueSize, clock.System)
+	go w.writeLoop()
+	return w
+}
+
+// NewFrameWriterWithClock is NewFrameWriter with an injected clock, so
+// integration tests can drive the batching and heartbeat timers with a
+// fake clock instead of waiting out real intervals.
+func NewFrameWriterWithClock(conn io.Writer, clk clock.Clock) *FrameWriter {
+	if clk == nil {
+		clk = clock.System
+	}
+	w := newFrameWriter(conn, 256, 2*time.Millisecond, 4096, clk)
+	w.EnableAdaptiveFlush(16)
+	go w.writeLoop()
+	return w
+}
+
+func newFrameWriter(conn io.Writer, maxBatch int, maxBatchWait time.Duration, queueSize int, clk clock.Clock) *FrameWriter {
+	return &FrameWriter{
 		conn:  conn,
 		queue: make(chan *Frame, queueSize),
 		controlQueue: make(chan *Frame, func() int {
@@ -63,22 +86,21 @@ func NewFrameWriterWithConfig(conn io.Writer, maxBatch int, maxBatchWait time.Du
 		maxBatchWait:     maxBatchWait,
 		done:             make(chan struct{}),
 		heartbeatControl: make(chan struct{}, 1),
+		clock:            clk,
 	}
-	go w.writeLoop()
-	return w
 }
 
 func (w *FrameWriter) writeLoop() {
-	batchTicker := time.NewTicker(w.maxBatchWait)
+	batchTicker := w.clock.NewTicker(w.maxBatchWait)
 	defer batchTicker.Stop()
 
-	var heartbeatTicker *time.Ticker
+	var heartbeatTicker clock.Ticker
 	var heartbeatCh <-chan time.Time
 
 	w.mu.Lock()
 	if w.heartbeatEnabled && w.heartbeatInterval > 0 {
-		heartbeatTicker = time.NewTicker(w.heartbeatInterval)
-		heartbeatCh = heartbeatTicker.C
+		heartbeatTicker = w.clock.NewTicker(w.heartbeatInterval)
+		heartbeatCh = heartbeatTicker.Chan()
 	}
 	w.mu.Unlock()
 
@@ -125,7 +147,7 @@ func (w *FrameWriter) writeLoop() {
 			}
 			w.mu.Unlock()
 
-		case <-batchTicker.C:
+		case <-batchTicker.Chan():
 			w.mu.Lock()
 			if len(w.batch) > 0 {
 				w.flushBatchLocked()
@@ -149,8 +171,8 @@ func (w *FrameWriter) writeLoop() {
 				heartbeatCh = nil
 			}
 			if w.heartbeatEnabled && w.heartbeatInterval > 0 {
-				heartbeatTicker = time.NewTicker(w.heartbeatInterval)
-				heartbeatCh = heartbeatTicker.C
+				heartbeatTicker = w.clock.NewTicker(w.heartbeatInterval)
+				heartbeatCh = heartbeatTicker.Chan()
 			}
 			w.mu.Unlock()
 
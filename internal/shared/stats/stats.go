@@ -14,6 +14,7 @@ type TrafficStats struct {
 
 	// Request counts
 	totalRequests     int64
+	serverErrors      int64
 	activeConnections int64
 
 	// For speed calculation
@@ -54,6 +55,11 @@ func (s *TrafficStats) AddRequest() {
 	atomic.AddInt64(&s.totalRequests, 1)
 }
 
+// AddServerError increments the backend 5xx response counter
+func (s *TrafficStats) AddServerError() {
+	atomic.AddInt64(&s.serverErrors, 1)
+}
+
 func (s *TrafficStats) IncActiveConnections() {
 	atomic.AddInt64(&s.activeConnections, 1)
 }
@@ -80,6 +86,11 @@ func (s *TrafficStats) GetTotalRequests() int64 {
 	return atomic.LoadInt64(&s.totalRequests)
 }
 
+// GetServerErrors returns total backend 5xx response count
+func (s *TrafficStats) GetServerErrors() int64 {
+	return atomic.LoadInt64(&s.serverErrors)
+}
+
 func (s *TrafficStats) GetActiveConnections() int64 {
 	return atomic.LoadInt64(&s.activeConnections)
 }
@@ -154,6 +165,7 @@ type Snapshot struct {
 	TotalBytesOut     int64
 	TotalBytes        int64
 	TotalRequests     int64
+	ServerErrors      int64
 	ActiveConnections int64
 	SpeedIn           int64 // bytes per second
 	SpeedOut          int64 // bytes per second
@@ -176,6 +188,7 @@ func (s *TrafficStats) GetSnapshot() Snapshot {
 		TotalBytesOut:     totalOut,
 		TotalBytes:        totalIn + totalOut,
 		TotalRequests:     atomic.LoadInt64(&s.totalRequests),
+		ServerErrors:      atomic.LoadInt64(&s.serverErrors),
 		ActiveConnections: active,
 		SpeedIn:           speedIn,
 		SpeedOut:          speedOut,
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// dashboardMaxLog bounds the scrolling request log; older lines fall off.
+const dashboardMaxLog = 256
+
+// Dashboard is the client's live tunnel view, drawn on the terminal's
+// alternate screen buffer. Unlike the old cursor-movement repainting, a
+// full-frame redraw on a separate screen survives scrolling, stray output,
+// and resizes; the normal screen (and its scrollback) comes back intact on
+// Stop.
+type Dashboard struct {
+	mu     sync.Mutex
+	status TunnelStatus
+	state  string
+	log    []string
+	width  int
+	height int
+
+	out     *os.File
+	active  bool
+	stopCh  chan struct{}
+	winchCh chan os.Signal
+}
+
+// IsTerminal reports whether stdout is an interactive terminal. The
+// dashboard falls back to plain line output when it is not (pipes, daemon
+// log files, CI).
+func IsTerminal() bool {
+	return term.IsTerminal(os.Stdout.Fd())
+}
+
+// NewDashboard creates a dashboard; call Start to take over the screen.
+func NewDashboard() *Dashboard {
+	return &Dashboard{
+		out:    os.Stdout,
+		state:  "connecting",
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start switches to the alternate screen and begins tracking resizes.
+func (d *Dashboard) Start() {
+	d.mu.Lock()
+	if d.active {
+		d.mu.Unlock()
+		return
+	}
+	d.active = true
+	d.width, d.height = d.terminalSize()
+	d.mu.Unlock()
+
+	// Alternate screen, clear, hide cursor.
+	fmt.Fprint(d.out, "\x1b[?1049h\x1b[2J\x1b[H\x1b[?25l")
+
+	d.winchCh = make(chan os.Signal, 1)
+	signal.Notify(d.winchCh, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-d.winchCh:
+				d.mu.Lock()
+				d.width, d.height = d.terminalSize()
+				d.mu.Unlock()
+				d.Render()
+			case <-d.stopCh:
+				return
+			}
+		}
+	}()
+
+	d.Render()
+}
+
+// Stop restores the normal screen. Safe to call more than once.
+func (d *Dashboard) Stop() {
+	d.mu.Lock()
+	if !d.active {
+		d.mu.Unlock()
+		return
+	}
+	d.active = false
+	d.mu.Unlock()
+
+	close(d.stopCh)
+	signal.Stop(d.winchCh)
+
+	// Show cursor, leave the alternate screen.
+	fmt.Fprint(d.out, "\x1b[?25h\x1b[?1049l")
+}
+
+func (d *Dashboard) terminalSize() (int, int) {
+	w, h, err := term.GetSize(d.out.Fd())
+	if err != nil || w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
+
+// SetStatus updates the header and metrics shown on the next Render.
+func (d *Dashboard) SetStatus(status *TunnelStatus) {
+	d.mu.Lock()
+	d.status = *status
+	d.mu.Unlock()
+}
+
+// SetState updates the connection state line (online, reconnecting, ...).
+func (d *Dashboard) SetState(state string) {
+	d.mu.Lock()
+	d.state = state
+	d.mu.Unlock()
+}
+
+// AppendLog pushes a line onto the scrolling request log.
+func (d *Dashboard) AppendLog(line string) {
+	d.mu.Lock()
+	d.log = append(d.log, line)
+	if len(d.log) > dashboardMaxLog {
+		d.log = d.log[len(d.log)-dashboardMaxLog:]
+	}
+	d.mu.Unlock()
+	d.Render()
+}
+
+// Render redraws the whole frame from the current state.
+func (d *Dashboard) Render() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.active {
+		return
+	}
+
+	width, height := d.width, d.height
+	status := d.status
+
+	requestLabel := "requests"
+	if status.Type == "tcp" {
+		requestLabel = "connections"
+	}
+
+	stateStr := Success("● " + d.state)
+	if d.state != "online" {
+		stateStr = Warning("● " + d.state)
+	}
+
+	lines := []string{
+		" " + Highlight("drip") + "  " + Muted(strings.ToUpper(status.Type)+" tunnel") + "  " + stateStr,
+		"",
+		" " + URL(status.URL) + Muted("  ⇢ ") + status.LocalAddr,
+		fmt.Sprintf(" %s %s   %s %s  %s %s   %s %s",
+			Muted("latency"), formatLatency(status.Latency),
+			Muted("traffic"), fmt.Sprintf("↓ %s  ↑ %s", formatBytes(status.BytesIn), formatBytes(status.BytesOut)),
+			Muted("speed"), fmt.Sprintf("↓ %s  ↑ %s", formatSpeed(status.SpeedIn), formatSpeed(status.SpeedOut)),
+			Muted(requestLabel), fmt.Sprintf("%d", status.TotalRequest)),
+		" " + Muted(strings.Repeat("─", max(width-2, 1))),
+	}
+
+	// The request log fills the rest of the screen, newest at the bottom;
+	// the last line stays free for the quit hint.
+	logRows := height - len(lines) - 1
+	if logRows < 0 {
+		logRows = 0
+	}
+	visible := d.log
+	if len(visible) > logRows {
+		visible = visible[len(visible)-logRows:]
+	}
+	lines = append(lines, visible...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, " "+Muted("Ctrl+C to stop • reconnects automatically"))
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i >= height {
+			break
+		}
+		b.WriteString(truncateToWidth(line, width))
+		b.WriteString("\x1b[K") // clear to end of line, avoids stale tails
+		if i < len(lines)-1 && i < height-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	fmt.Fprint(d.out, b.String())
+}
+
+// truncateToWidth trims a line to the terminal width, counting printable
+// runes and skipping ANSI escape sequences.
+func truncateToWidth(line string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	printed := 0
+	inEscape := false
+	for _, r := range line {
+		if inEscape {
+			b.WriteRune(r)
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				inEscape = false
+			}
+			continue
+		}
+		if r == '\x1b' {
+			inEscape = true
+			b.WriteRune(r)
+			continue
+		}
+		if printed >= width {
+			break
+		}
+		b.WriteRune(r)
+		printed++
+	}
+	return b.String()
+}
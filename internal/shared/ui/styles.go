@@ -103,6 +103,21 @@ func Cyan(text string) string {
 	return cyanStyle.Render(text)
 }
 
+// Green returns a styled green text (no prefix)
+func Green(text string) string {
+	return successStyle.Render(text)
+}
+
+// Yellow returns a styled yellow text (no prefix)
+func Yellow(text string) string {
+	return warningStyle.Render(text)
+}
+
+// Red returns a styled red text (no prefix)
+func Red(text string) string {
+	return errorStyle.Render(text)
+}
+
 // URL returns a styled URL
 func URL(text string) string {
 	return urlStyle.Render(text)
@@ -189,6 +189,11 @@ func formatLatency(d time.Duration) string {
 	return style.Render(fmt.Sprintf("%dms", ms))
 }
 
+// FormatBytes formats bytes to human readable format
+func FormatBytes(bytes int64) string {
+	return formatBytes(bytes)
+}
+
 // formatBytes formats bytes to human readable format
 func formatBytes(bytes int64) string {
 	const unit = 1024
@@ -0,0 +1,34 @@
+package utils
+
+import "strings"
+
+// confusableReplacer folds characters commonly substituted for letters in
+// typosquat and homograph names back to the letter they imitate, and drops
+// hyphens, so "pay-pa1" normalizes to "paypal". Subdomains are already
+// limited to lowercase ASCII, which keeps the table small.
+var confusableReplacer = strings.NewReplacer(
+	"0", "o",
+	"1", "l",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"6", "b",
+	"7", "t",
+	"8", "b",
+	"9", "g",
+	"vv", "w",
+	"rn", "m",
+	"-", "",
+)
+
+// NormalizeConfusables returns s with lookalike characters folded to the
+// letters they imitate, for comparison against brand blocklists.
+func NormalizeConfusables(s string) string {
+	return confusableReplacer.Replace(strings.ToLower(s))
+}
+
+// IsPunycode reports whether a DNS label is punycode-encoded (an "xn--"
+// prefix). Encoded labels can render as non-ASCII lookalikes in browsers.
+func IsPunycode(label string) bool {
+	return strings.HasPrefix(label, "xn--")
+}
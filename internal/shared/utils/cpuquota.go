@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CgroupCPUQuota returns the number of CPUs this process may use under its
+// cgroup CPU quota, and whether a quota is in effect. In containers limited
+// to one or two CPUs this is far below runtime.NumCPU(), which reports the
+// host's cores; sizing GOMAXPROCS from the host count there causes heavy
+// scheduler thrashing. Returns false on hosts without cgroups (non-Linux)
+// or when the quota is unlimited.
+func CgroupCPUQuota() (float64, bool) {
+	// cgroup v2: "max 100000" (unlimited) or "<quota> <period>" in µs.
+	if data, err := os.ReadFile("/sys/fs/cgroup/cpu.max"); err == nil {
+		fields := strings.Fields(strings.TrimSpace(string(data)))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+				return quota / period, true
+			}
+		}
+		return 0, false
+	}
+
+	// cgroup v1: quota and period in separate files; quota -1 is unlimited.
+	quota, err1 := readCgroupValue("/sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+	period, err2 := readCgroupValue("/sys/fs/cgroup/cpu/cpu.cfs_period_us")
+	if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+		return quota / period, true
+	}
+	return 0, false
+}
+
+func readCgroupValue(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+}
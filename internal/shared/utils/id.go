@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"time"
 )
@@ -29,3 +30,13 @@ func generateFallbackID() string {
 	// Simple fallback using timestamp
 	return hex.EncodeToString([]byte(time.Now().String()))
 }
+
+// TokenID derives a short non-reversible identifier from an auth token,
+// safe to put on log lines. Empty tokens map to "anonymous".
+func TokenID(token string) string {
+	if token == "" {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:4])
+}
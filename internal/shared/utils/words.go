@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// NameStyleWords selects word-based generated subdomains like
+// "quiet-otter-42" instead of random character strings.
+const NameStyleWords = "words"
+
+// Curated word lists for memorable generated names: short, easy to spell
+// over the phone, and free of lookalike spellings. Roughly 200k
+// combinations with the numeric suffix.
+var subdomainAdjectives = []string{
+	"amber", "brave", "breezy", "bright", "calm", "cedar", "clever", "cosmic",
+	"crisp", "dapper", "dusty", "eager", "fancy", "fuzzy", "gentle", "happy",
+	"hazel", "humble", "jolly", "keen", "kind", "lively", "lucky", "mellow",
+	"merry", "misty", "neat", "nimble", "perky", "plucky", "proud", "quick",
+	"quiet", "rapid", "rosy", "rustic", "shiny", "snappy", "snug", "spry",
+	"steady", "sturdy", "sunny", "swift", "tidy", "vivid", "warm", "witty",
+}
+
+var subdomainNouns = []string{
+	"badger", "beaver", "bison", "crane", "cricket", "deer", "dingo", "eagle",
+	"falcon", "ferret", "finch", "fox", "gecko", "heron", "hedgehog", "ibex",
+	"jackal", "koala", "lemur", "lynx", "magpie", "marmot", "marten", "mink",
+	"moose", "newt", "otter", "owl", "panda", "pelican", "pika", "puffin",
+	"quail", "rabbit", "raven", "robin", "seal", "shrew", "sparrow", "stoat",
+	"swan", "tapir", "toucan", "turtle", "vole", "walrus", "weasel", "wren",
+}
+
+// GenerateWordSubdomain generates a memorable two-word name with a numeric
+// suffix, like "quiet-otter-42".
+func GenerateWordSubdomain() string {
+	return fmt.Sprintf("%s-%s-%d",
+		pickWord(subdomainAdjectives),
+		pickWord(subdomainNouns),
+		10+randomBelow(90),
+	)
+}
+
+func pickWord(words []string) string {
+	return words[randomBelow(len(words))]
+}
+
+func randomBelow(n int) int {
+	num, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// Fallback to a fixed pick if crypto/rand fails
+		return 0
+	}
+	return int(num.Int64())
+}
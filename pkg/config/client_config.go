@@ -4,15 +4,68 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // ClientConfig represents the client configuration
 type ClientConfig struct {
-	Server string `yaml:"server"` // Server address (e.g., tunnel.example.com:443)
-	Token  string `yaml:"token"`  // Authentication token
-	TLS    bool   `yaml:"tls"`    // Use TLS (always true for production)
+	Server  string                 `yaml:"server"`            // Server address (e.g., tunnel.example.com:443)
+	Servers []string               `yaml:"servers,omitempty"` // Additional endpoints; the client probes and uses the fastest
+	Token   string                 `yaml:"token"`             // Authentication token
+	TLS     bool                   `yaml:"tls"`               // Use TLS (always true for production)
+	Alerts  *AlertConfig           `yaml:"alerts,omitempty"`  // Optional connection quality alerts
+	Tunnels map[string]NamedTunnel `yaml:"tunnels,omitempty"` // Named tunnels started together by 'drip up'
+
+	Telemetry *TelemetryConfig `yaml:"telemetry,omitempty"` // Opt-in crash reports and usage counters
+	Runtime   *RuntimeConfig   `yaml:"runtime,omitempty"`   // Optional Go runtime overrides
+}
+
+// RuntimeConfig overrides Go runtime tuning. Without it, GOMAXPROCS follows
+// the cgroup CPU quota when one is in effect (container limits) and the
+// runtime defaults otherwise; the standard GOMAXPROCS/GOGC/GOMEMLIMIT
+// environment variables always win over these settings.
+type RuntimeConfig struct {
+	GOMAXPROCS    int `yaml:"gomaxprocs,omitempty"`      // Fixed GOMAXPROCS value
+	GCPercent     int `yaml:"gc_percent,omitempty"`      // GC target percentage (GOGC)
+	MemoryLimitMB int `yaml:"memory_limit_mb,omitempty"` // Soft heap limit in MiB (GOMEMLIMIT)
+}
+
+// TelemetryConfig controls opt-in telemetry. Nothing is collected or sent
+// unless Enabled is true. Reports contain the drip version, OS/arch, and a
+// stack trace on crashes — never traffic, hostnames, or tokens.
+type TelemetryConfig struct {
+	Enabled  bool   `yaml:"enabled"`            // Opt in to crash reports and usage counters
+	Endpoint string `yaml:"endpoint,omitempty"` // Collector URL; without one, reports stay on disk
+}
+
+// NamedTunnel is one entry in the client config's tunnels: section.
+//
+//	tunnels:
+//	  web:
+//	    type: http
+//	    port: 3000
+//	    subdomain: myapp
+//	  db:
+//	    type: tcp
+//	    port: 5432
+type NamedTunnel struct {
+	Type      string `yaml:"type"`                // http, https, or tcp
+	Port      int    `yaml:"port"`                // Local port to tunnel
+	Subdomain string `yaml:"subdomain,omitempty"` // Optional custom subdomain
+	Address   string `yaml:"address,omitempty"`   // Local address (default 127.0.0.1)
+}
+
+// AlertConfig holds connection quality alert thresholds. A threshold of
+// zero disables that check.
+type AlertConfig struct {
+	LatencyAbove      time.Duration `yaml:"latency_above,omitempty"`       // Alert when latency exceeds this
+	ReconnectsPerHour int           `yaml:"reconnects_per_hour,omitempty"` // Alert when drops in the last hour exceed this
+	ErrorRatePercent  float64       `yaml:"error_rate_percent,omitempty"`  // Alert when backend 5xx percentage exceeds this
+	Notify            bool          `yaml:"notify,omitempty"`              // Send a desktop notification when an alert fires
+	Hook              string        `yaml:"hook,omitempty"`                // Command to run when an alert fires
+	Cooldown          time.Duration `yaml:"cooldown,omitempty"`            // Minimum time between repeats of the same alert
 }
 
 // DefaultClientConfig returns the default configuration path
@@ -43,10 +96,21 @@ func LoadClientConfig(path string) (*ClientConfig, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
-	if config.Server == "" {
+	if config.Server == "" && len(config.Servers) == 0 {
 		return nil, fmt.Errorf("server address is required in config")
 	}
 
+	for name, t := range config.Tunnels {
+		switch t.Type {
+		case "http", "https", "tcp":
+		default:
+			return nil, fmt.Errorf("tunnel %q has invalid type %q (must be http, https, or tcp)", name, t.Type)
+		}
+		if t.Port < 1 || t.Port > 65535 {
+			return nil, fmt.Errorf("tunnel %q has invalid port %d", name, t.Port)
+		}
+	}
+
 	return &config, nil
 }
 
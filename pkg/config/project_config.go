@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigName is the per-project config file discovered from the
+// working directory, like .nvmrc.
+const ProjectConfigName = ".drip.yml"
+
+// ProjectConfig defines the tunnels for one project.
+//
+//	tunnels:
+//	  - type: http
+//	    port: 3000
+//	    subdomain: myapp
+//	  - type: tcp
+//	    port: 5432
+type ProjectConfig struct {
+	Tunnels []ProjectTunnel `yaml:"tunnels"`
+}
+
+// ProjectTunnel is one tunnel entry in a project config.
+type ProjectTunnel struct {
+	Type      string `yaml:"type"`                // http, https, or tcp
+	Port      int    `yaml:"port"`                // Local port to tunnel
+	Subdomain string `yaml:"subdomain,omitempty"` // Optional custom subdomain
+	Address   string `yaml:"address,omitempty"`   // Local address (default 127.0.0.1)
+}
+
+// FindProjectConfig walks from dir up to the filesystem root looking for a
+// .drip.yml. It returns the path, or "" when no project config exists.
+func FindProjectConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig loads and validates a project config file.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+
+	var config ProjectConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(config.Tunnels) == 0 {
+		return nil, fmt.Errorf("%s defines no tunnels", path)
+	}
+	for i, t := range config.Tunnels {
+		switch t.Type {
+		case "http", "https", "tcp":
+		default:
+			return nil, fmt.Errorf("%s: tunnel %d has invalid type %q (must be http, https, or tcp)", path, i+1, t.Type)
+		}
+		if t.Port < 1 || t.Port > 65535 {
+			return nil, fmt.Errorf("%s: tunnel %d has invalid port %d", path, i+1, t.Port)
+		}
+	}
+
+	return &config, nil
+}